@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"io"
 	"log"
@@ -31,12 +32,21 @@ import (
 // 2025/09/30 19:04:34 published 3883600 messages (12879.7 t/s), consumed 1659300 messages (5599.9 t/s)
 // 2026/01/10 20:37:33 published 3929000 messages (12759.7 t/s), consumed 1621900 messages (5459.9 t/s)
 
+// SQLite backend with the 'run' process on a single CPU shared with the benchmark,
+// the rates are averages of four 60 s runs.
+
+// batchSizePublish = 100, batchSizeConsume = 100, batchSizeAck = 100
+// acks loading and saving messages one by one: published 738.9 t/s, consumed 703.8 t/s
+// acks loading and saving messages in bulk:    published 1000.8 t/s, consumed 983.2 t/s
+// 2026/10/18 05:49:53 published 44100 messages (919.9 t/s), consumed 42033 messages (1134.3 t/s)
+
 const threadsCountW = 24
 const threadsCountR = 32
 
-const batchSizePublish = 1
-const batchSizeConsume = 10
-const batchSizeAck = 1
+// batch sizes can be set with flags, e.g. -batch-ack 1 to measure acks one by one
+var batchSizePublish = 100
+var batchSizeConsume = 100
+var batchSizeAck = 100
 
 var publishedCount atomic.Int32
 var consumedCount atomic.Int32
@@ -49,6 +59,11 @@ var httpClient = &http.Client{
 }
 
 func main() {
+	flag.IntVar(&batchSizePublish, "batch-publish", batchSizePublish, "messages per publish request")
+	flag.IntVar(&batchSizeConsume, "batch-consume", batchSizeConsume, "messages per consume request")
+	flag.IntVar(&batchSizeAck, "batch-ack", batchSizeAck, "messages per ack request")
+	flag.Parse()
+
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
 
@@ -97,7 +112,8 @@ func printStats(ctx context.Context) {
 const baseURL = "http://127.0.0.1:8060"
 
 type Message struct {
-	ID string `json:"id"`
+	ID        string `json:"id"`
+	AttemptID string `json:"attempt_id"`
 }
 
 func runPublisher(ctx context.Context, threadNum int) {
@@ -188,18 +204,12 @@ func runConsumer(ctx context.Context) {
 		}
 
 		for chunk := range slices.Chunk(messages, batchSizeAck) {
-			ids := make([]string, 0, len(chunk))
-
-			for _, message := range chunk {
-				ids = append(ids, message.ID)
-			}
-
-			if err := ackMessages(ids); err != nil {
+			if err := ackMessages(chunk); err != nil {
 				log.Println("ack messages error:", err)
 				continue
 			}
 
-			consumedCount.Add(int32(len(ids)))
+			consumedCount.Add(int32(len(chunk)))
 		}
 	}
 }
@@ -245,11 +255,12 @@ func consumeMessages(limit int) ([]Message, error) {
 	return responseDTO, nil
 }
 
-func ackMessages(ids []string) error {
-	reqElements := make([]any, 0, len(ids))
-	for _, id := range ids {
+func ackMessages(messages []Message) error {
+	reqElements := make([]any, 0, len(messages))
+	for _, message := range messages {
 		reqElements = append(reqElements, map[string]any{
-			"id": id,
+			"id":         message.ID,
+			"attempt_id": message.AttemptID,
 		})
 	}
 	requestBody, err := json.Marshal(reqElements)
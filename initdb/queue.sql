@@ -3,29 +3,52 @@ CREATE TYPE message_status AS ENUM ('PREPARED', 'AVAILABLE', 'PROCESSING', 'DELA
 CREATE TABLE messages (
     id uuid PRIMARY KEY,
     queue varchar(255) NOT NULL,
+    group_key varchar(255) NULL,
     created_at timestamptz NOT NULL,
     finalized_at timestamptz NULL,
     status message_status NOT NULL,
     status_changed_at timestamptz NOT NULL,
     delayed_until timestamptz NULL,
     timeout_at timestamptz NULL,
+    attempt_id uuid NULL,
     priority smallint NOT NULL,
+    aged_at timestamptz NULL,
     retries int NOT NULL,
     generation int NOT NULL,
+    trace_parent varchar(55) NULL,
     version int NOT NULL
 );
 
+CREATE INDEX ON messages (queue) WHERE status = 'PREPARED';
 CREATE INDEX ON messages (queue, status, priority DESC, status_changed_at ASC) WHERE status = 'AVAILABLE';
-CREATE INDEX ON messages (status, delayed_until) WHERE status = 'DELAYED';
-CREATE INDEX ON messages (status, timeout_at) WHERE status = 'PROCESSING';
-CREATE INDEX ON messages (status, finalized_at) WHERE status IN ('DELIVERED', 'DROPPED');
+CREATE INDEX ON messages (status, delayed_until) INCLUDE (queue) WHERE status = 'DELAYED';
+CREATE INDEX ON messages (status, timeout_at) INCLUDE (queue) WHERE status = 'PROCESSING';
+CREATE INDEX ON messages (status, finalized_at) INCLUDE (queue) WHERE status IN ('DELIVERED', 'DROPPED');
 CREATE INDEX ON messages (created_at);
+CREATE INDEX ON messages (queue, group_key, created_at) WHERE group_key IS NOT NULL;
 
 CREATE TABLE message_payloads (
     msg_id uuid PRIMARY KEY,
     payload text NOT NULL
 );
 
+-- only messages with headers have a row here
+CREATE TABLE message_headers (
+    msg_id uuid PRIMARY KEY,
+    headers jsonb NOT NULL
+);
+
+-- a dedup key outlives its message until the dedup window expires
+CREATE TABLE message_dedup_keys (
+    queue varchar(255) NOT NULL,
+    dedup_key varchar(255) NOT NULL,
+    msg_id uuid NOT NULL,
+    expires_at timestamptz NOT NULL,
+    PRIMARY KEY (queue, dedup_key)
+);
+
+CREATE INDEX ON message_dedup_keys (expires_at);
+
 CREATE TABLE message_history (
     msg_id uuid NOT NULL,
     generation int NOT NULL,
@@ -46,5 +69,40 @@ CREATE TABLE archived_messages (
     retries int NOT NULL,
     generation int NOT NULL,
     payload text NOT NULL,
+    headers jsonb NOT NULL,
     history jsonb NOT NULL
 );
+
+CREATE INDEX ON archived_messages (queue, finalized_at);
+
+CREATE TABLE queue_token_buckets (
+    queue varchar(255) PRIMARY KEY,
+    tokens double precision NOT NULL,
+    updated_at timestamptz NOT NULL
+);
+
+-- a queue is paused while it has a row here, consumers get no messages from it
+CREATE TABLE queue_pauses (
+    queue varchar(255) PRIMARY KEY,
+    paused_at timestamptz NOT NULL
+);
+
+-- the schema above matches all migrations of server/internal/migrations, keep them in sync
+CREATE TABLE schema_migrations (
+    version int PRIMARY KEY,
+    name varchar(255) NOT NULL,
+    applied_at timestamptz NOT NULL
+);
+
+INSERT INTO schema_migrations (version, name, applied_at) VALUES
+    (1, 'baseline', now()),
+    (2, 'attempt_ids', now()),
+    (3, 'archive_retention', now()),
+    (4, 'rate_limits', now()),
+    (5, 'priority_aging', now()),
+    (6, 'trace_parent', now()),
+    (7, 'message_headers', now()),
+    (8, 'dedup_keys', now()),
+    (9, 'message_groups', now()),
+    (10, 'queue_pauses', now()),
+    (11, 'status_count_indexes', now());
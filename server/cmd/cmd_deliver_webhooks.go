@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"server/internal/appbuilder"
+	"server/internal/utils/runkit"
+)
+
+func DeliverWebhooks(app *appbuilder.App) {
+	if err := PingDB(app.DB); err != nil {
+		app.Logger.Error("database connection failed", "error", err)
+		return
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	// the event bus wakes up the dispatcher as soon as new messages are available
+	err := runkit.Multiple{
+		runkit.Retrier{
+			Fn:     app.EventBus,
+			Name:   "event bus",
+			Logger: app.Logger,
+		},
+		runkit.Retrier{
+			Fn:     app.WebhookDispatcher,
+			Name:   "webhook delivery",
+			Logger: app.Logger,
+		},
+	}.Run(ctx)
+
+	if err != nil {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"server/internal/appbuilder"
+	"server/internal/migrations"
+)
+
+const migrateUsage = "Usage: queue migrate up|status|baseline <version>"
+
+// Migrate manages the database schema.
+// Usage: queue migrate up|status|baseline <version>
+func Migrate(app *appbuilder.App, args []string) {
+	if len(args) < 1 {
+		fmt.Println(migrateUsage)
+		os.Exit(2)
+	}
+
+	if err := PingDB(app.DB); err != nil {
+		app.Logger.Error("database connection failed", "error", err)
+		os.Exit(1)
+	}
+
+	migrator, err := migrations.NewMigrator(app.Logger, app.DB, app.Dialect)
+	if err != nil {
+		fmt.Printf("invalid migrations: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx, done := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer done()
+
+	switch {
+	case args[0] == "up" && len(args) == 1:
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			fmt.Printf("applied %d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			fmt.Printf("migration failed: %v\n", err)
+			os.Exit(1)
+		}
+		if len(applied) == 0 {
+			fmt.Println("schema is up to date")
+		}
+	case args[0] == "status" && len(args) == 1:
+		status, err := migrator.Status(ctx)
+		if err != nil {
+			fmt.Printf("status failed: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("current version: %d, latest version: %d\n", status.Current, status.Latest)
+		for _, migration := range status.Pending {
+			fmt.Printf("pending %d_%s\n", migration.Version, migration.Name)
+		}
+	case args[0] == "baseline" && len(args) == 2:
+		version, err := strconv.Atoi(args[1])
+		if err != nil {
+			fmt.Printf("invalid version: %v\n", err)
+			os.Exit(2)
+		}
+		if err := migrator.Baseline(ctx, version); err != nil {
+			fmt.Printf("baseline failed: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("recorded versions up to %d as applied\n", version)
+	default:
+		fmt.Println(migrateUsage)
+		os.Exit(2)
+	}
+}
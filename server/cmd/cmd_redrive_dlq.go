@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"server/internal/appbuilder"
+	"server/internal/domain"
+	"server/internal/usecases"
+	"server/internal/utils/opt"
+)
+
+// RedriveDLQ moves messages from the given DLQ back to their source queues, printing progress after each batch.
+// Usage: queue redrive-dlq [-limit N] <dlq>
+func RedriveDLQ(app *appbuilder.App, args []string) {
+	flagSet := flag.NewFlagSet(CmdRedriveDLQ, flag.ExitOnError)
+
+	var limit int
+	flagSet.IntVar(&limit, "limit", 0, "max number of messages to redrive, all if 0")
+
+	_ = flagSet.Parse(args)
+
+	if flagSet.NArg() != 1 {
+		fmt.Println("Usage: queue redrive-dlq [-limit N] <dlq>")
+		os.Exit(2)
+	}
+
+	queue, err := domain.NewQueueName(flagSet.Arg(0))
+	if err != nil {
+		fmt.Printf("invalid queue name: %v\n", err)
+		os.Exit(2)
+	}
+
+	if err := PingDB(app.DB); err != nil {
+		app.Logger.Error("database connection failed", "error", err)
+		os.Exit(1)
+	}
+
+	ctx, done := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer done()
+
+	params := usecases.RedriveDLQParams{Queue: queue}
+	if limit > 0 {
+		params.Limit = opt.Some(limit)
+	}
+
+	result, err := app.RedriveDLQ.Do(ctx, params, func(progress usecases.RedriveDLQProgress) {
+		fmt.Printf("redriven: %d, skipped: %d\n", progress.Redriven, progress.Skipped)
+	})
+	if err != nil {
+		fmt.Printf("redrive failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("done, redriven: %d, skipped: %d\n", result.Redriven, result.Skipped)
+}
@@ -2,8 +2,6 @@ package main
 
 import (
 	"context"
-	"fmt"
-	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -18,18 +16,15 @@ func Run(app *appbuilder.App) {
 		return
 	}
 
+	if err := CheckSchema(app); err != nil {
+		app.Logger.Error("schema check failed", "error", err)
+		return
+	}
+
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
-	err := runkit.Multiple{
-		runkit.HTTPServer{
-			Name: "API",
-			Server: &http.Server{
-				Addr:    fmt.Sprintf(":%d", app.Config.APIPort()),
-				Handler: app.Router,
-			},
-			Logger: app.Logger,
-		},
+	err := append(apiServers(app),
 		runkit.Retrier{
 			Fn:     app.EventBus,
 			Name:   "event bus",
@@ -40,6 +35,11 @@ func Run(app *appbuilder.App) {
 			Name:   "resume delayed",
 			Logger: app.Logger,
 		},
+		runkit.Retrier{
+			Fn:     app.AgePriorities,
+			Name:   "priority aging",
+			Logger: app.Logger,
+		},
 		runkit.Retrier{
 			Fn:     app.ExpireProcessing,
 			Name:   "expire processing",
@@ -50,7 +50,22 @@ func Run(app *appbuilder.App) {
 			Name:   "message archivation",
 			Logger: app.Logger,
 		},
-	}.Run(ctx)
+		runkit.Retrier{
+			Fn:     app.PurgeArchive,
+			Name:   "archive purge",
+			Logger: app.Logger,
+		},
+		runkit.Retrier{
+			Fn:     app.DeleteExpiredDedupKeys,
+			Name:   "dedup keys cleanup",
+			Logger: app.Logger,
+		},
+		runkit.Retrier{
+			Fn:     app.WebhookDispatcher,
+			Name:   "webhook delivery",
+			Logger: app.Logger,
+		},
+	).Run(ctx)
 
 	if err != nil {
 		os.Exit(1)
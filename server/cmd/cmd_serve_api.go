@@ -18,10 +18,30 @@ func ServeAPI(app *appbuilder.App) {
 		return
 	}
 
+	if err := CheckSchema(app); err != nil {
+		app.Logger.Error("schema check failed", "error", err)
+		return
+	}
+
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
-	err := runkit.Multiple{
+	err := append(apiServers(app),
+		runkit.Retrier{
+			Fn:     app.EventBus,
+			Name:   "event bus",
+			Logger: app.Logger,
+		},
+	).Run(ctx)
+
+	if err != nil {
+		os.Exit(1)
+	}
+}
+
+// apiServers returns the HTTP API server and, if its port is configured, the gRPC API server.
+func apiServers(app *appbuilder.App) runkit.Multiple {
+	servers := runkit.Multiple{
 		runkit.HTTPServer{
 			Name: "API",
 			Server: &http.Server{
@@ -30,14 +50,16 @@ func ServeAPI(app *appbuilder.App) {
 			},
 			Logger: app.Logger,
 		},
-		runkit.Retrier{
-			Fn:     app.EventBus,
-			Name:   "event bus",
-			Logger: app.Logger,
-		},
-	}.Run(ctx)
+	}
 
-	if err != nil {
-		os.Exit(1)
+	if grpcPort, isSet := app.Config.GRPCPort().Value(); isSet {
+		servers = append(servers, runkit.GRPCServer{
+			Name:   "gRPC API",
+			Addr:   fmt.Sprintf(":%d", grpcPort),
+			Server: app.GRPCServer,
+			Logger: app.Logger,
+		})
 	}
+
+	return servers
 }
@@ -12,7 +12,10 @@ import (
 	"time"
 
 	"server/internal/appbuilder"
+	"server/internal/config"
 	"server/internal/config/yamlconfig"
+	"server/internal/migrations"
+	"server/internal/utils/runkit"
 )
 
 const (
@@ -21,10 +24,20 @@ const (
 	CmdArchiveMessages  = "archive-messages"
 	CmdExpireProcessing = "expire-processing"
 	CmdResumeDelayed    = "resume-delayed"
+	CmdPurgeArchive     = "purge-archive"
+	CmdDeleteDedupKeys  = "delete-dedup-keys"
+	CmdDeliverWebhooks  = "deliver-webhooks"
+	CmdAgePriorities    = "age-priorities"
+	CmdRedriveDLQ       = "redrive-dlq"
+	CmdMigrate          = "migrate"
 )
 
 func main() {
-	availableCommands := []string{CmdRun, CmdServeAPI, CmdArchiveMessages, CmdExpireProcessing, CmdResumeDelayed}
+	availableCommands := []string{CmdRun, CmdServeAPI, CmdArchiveMessages, CmdExpireProcessing, CmdResumeDelayed, CmdPurgeArchive, CmdDeleteDedupKeys, CmdDeliverWebhooks, CmdAgePriorities, CmdRedriveDLQ, CmdMigrate}
+
+	longRunningCommands := []string{CmdRun, CmdServeAPI, CmdArchiveMessages, CmdExpireProcessing, CmdResumeDelayed, CmdPurgeArchive, CmdDeleteDedupKeys, CmdDeliverWebhooks, CmdAgePriorities}
+	// workers that neither publish nor wait for events
+	eventlessCommands := []string{CmdArchiveMessages, CmdPurgeArchive, CmdDeleteDedupKeys, CmdAgePriorities}
 
 	flagSet := flag.NewFlagSet("", flag.ContinueOnError)
 
@@ -48,11 +61,33 @@ func main() {
 		log.Fatalf("yamlconfig.LoadFromFile: %v", err)
 	}
 
+	// in-process events can't reach other processes, so with the memory event bus the long-running
+	// processes that publish or wait for events must be a single one; one-off commands are fine
+	sharesEventBus := slices.Contains(longRunningCommands, args[0]) && !slices.Contains(eventlessCommands, args[0])
+	if conf.EventBusDriver() == config.EventBusDriverMemory && sharesEventBus && args[0] != CmdRun {
+		log.Fatalf("event bus %q is not supported by %q, use the %q command", config.EventBusDriverMemory, args[0], CmdRun)
+	}
+
 	app, err := appbuilder.BuildApp(conf, nil)
 	if err != nil {
 		log.Fatalf("appbuilder.BuildApp: %v", err)
 	}
 
+	// queue configs are reloaded in the background, so that queues can be added without a restart;
+	// one-off commands run against the config they started with
+	reloadCtx, stopReload := context.WithCancel(context.Background())
+	defer stopReload()
+
+	if slices.Contains(longRunningCommands, args[0]) {
+		go func() {
+			_ = runkit.Retrier{
+				Fn:     yamlconfig.NewReloader(app.Logger, configPath, conf),
+				Name:   "config reload",
+				Logger: app.Logger,
+			}.Run(reloadCtx)
+		}()
+	}
+
 	switch args[0] {
 	case CmdRun:
 		Run(app)
@@ -64,6 +99,26 @@ func main() {
 		ExpireProcessing(app)
 	case CmdResumeDelayed:
 		ResumeDelayed(app)
+	case CmdPurgeArchive:
+		PurgeArchive(app)
+	case CmdDeleteDedupKeys:
+		DeleteDedupKeys(app)
+	case CmdDeliverWebhooks:
+		DeliverWebhooks(app)
+	case CmdAgePriorities:
+		AgePriorities(app)
+	case CmdRedriveDLQ:
+		RedriveDLQ(app, args[1:])
+	case CmdMigrate:
+		Migrate(app, args[1:])
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// flush spans which are still buffered
+	if err := app.Tracer.Shutdown(shutdownCtx); err != nil {
+		app.Logger.Error("tracer shutdown failed", "error", err)
 	}
 }
 
@@ -77,3 +132,25 @@ func PingDB(db *sql.DB) error {
 
 	return nil
 }
+
+// CheckSchema refuses to start against a database which isn't migrated to the version of the binary,
+// if the check is enabled in the config.
+func CheckSchema(app *appbuilder.App) error {
+	if !app.Config.CheckSchema() {
+		return nil
+	}
+
+	migrator, err := migrations.NewMigrator(app.Logger, app.DB, app.Dialect)
+	if err != nil {
+		return fmt.Errorf("migrations.NewMigrator: %w", err)
+	}
+
+	ctx, closeCtx := context.WithTimeout(context.Background(), 5*time.Second)
+	defer closeCtx()
+
+	if err := migrator.CheckVersion(ctx); err != nil {
+		return fmt.Errorf("migrator.CheckVersion: %w", err)
+	}
+
+	return nil
+}
@@ -1,8 +1,10 @@
-package base
+// Package apibatch maps batch requests and results of usecases, shared by the HTTP and gRPC transports.
+package apibatch
 
 import (
 	"errors"
 
+	"server/internal/apierrors"
 	"server/internal/usecases"
 	"server/pkg/httpmodels"
 )
@@ -49,7 +51,7 @@ func MapBatchResults[T1, T2 any](
 
 			// Errors from mapItemErrors are already mapped
 			if !errors.As(result.Error, &mappedErr) {
-				mappedErr = ExtractKnownErrors(result.Error)
+				mappedErr = apierrors.ExtractKnownErrors(result.Error)
 			}
 
 			mappedResults[i] = httpmodels.BatchResult[T2]{
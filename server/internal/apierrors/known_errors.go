@@ -0,0 +1,59 @@
+// Package apierrors classifies domain and usecase errors into API error codes,
+// so that the HTTP and gRPC transports report the same error the same way.
+package apierrors
+
+import (
+	"errors"
+
+	"server/internal/auth"
+	"server/internal/config"
+	"server/internal/domain"
+	"server/internal/storage"
+	"server/internal/usecases"
+	"server/pkg/httpmodels"
+)
+
+func ExtractKnownErrors(err error) *httpmodels.Error {
+	if errors.Is(err, usecases.ErrBatchSizeTooBig) {
+		return httpmodels.NewError(httpmodels.ErrorCodeBatchSizeTooBig, err.Error())
+	}
+
+	if errors.Is(err, usecases.ErrDirectWriteToDLQNotAllowed) {
+		return httpmodels.NewError(httpmodels.ErrorCodeQueueNotWritable, err.Error())
+	}
+
+	if errors.Is(err, usecases.ErrNotDLQ) {
+		return httpmodels.NewError(httpmodels.ErrorCodeRequestInvalid, err.Error())
+	}
+
+	if errors.Is(err, usecases.ErrInvalidCursor) {
+		return httpmodels.NewError(httpmodels.ErrorCodeRequestInvalid, err.Error())
+	}
+
+	if errors.Is(err, domain.ErrPriorityOutOfRange) {
+		return httpmodels.NewError(httpmodels.ErrorCodeRequestInvalid, err.Error())
+	}
+
+	if errors.Is(err, auth.ErrForbidden) {
+		return httpmodels.NewError(httpmodels.ErrorCodeForbidden, err.Error())
+	}
+
+	if errors.Is(err, domain.ErrAttemptMismatch) {
+		return httpmodels.NewError(httpmodels.ErrorCodeAttemptMismatch, err.Error())
+	}
+
+	if errors.Is(err, domain.ErrMaxProcessingTimeReached) {
+		return httpmodels.NewError(httpmodels.ErrorCodeMaxLeaseReached, err.Error())
+	}
+
+	if errors.Is(err, storage.ErrMsgNotFound) || errors.Is(err, storage.ErrArchivedMsgNotFound) {
+		return httpmodels.NewError(httpmodels.ErrorCodeMessageNotFound, err.Error())
+	}
+
+	var queueError config.QueueNotFoundError
+	if errors.As(err, &queueError) {
+		return httpmodels.NewError(httpmodels.ErrorCodeQueueNotFound, err.Error())
+	}
+
+	return httpmodels.NewError(httpmodels.ErrorCodeUnknown, err.Error())
+}
@@ -2,56 +2,85 @@ package appbuilder
 
 import (
 	"database/sql"
-	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc"
+	_ "modernc.org/sqlite"
 
 	"server/internal/appbuilder/requestscope"
+	"server/internal/auth"
 	"server/internal/config"
 	"server/internal/domain"
 	"server/internal/eventbus"
+	"server/internal/eventbus/memory"
 	"server/internal/eventbus/postgres"
+	"server/internal/grpcserver"
+	"server/internal/metrics"
 	"server/internal/openapi"
 	"server/internal/routes"
+	"server/internal/routes/base"
 	"server/internal/storage"
+	"server/internal/tracing"
 	"server/internal/usecases"
+	"server/internal/utils/dbutils"
 	"server/internal/utils/timeutils"
+	"server/internal/webhooks"
 )
 
 type Overrides struct {
 	Clock timeutils.Clock
+
+	// SpanExporter receives spans synchronously instead of the OTLP exporter from config (e.g. in-memory one in tests)
+	SpanExporter sdktrace.SpanExporter
 }
 
 type App struct {
 	Config *config.Config
 
-	Clock  timeutils.Clock
-	Logger *slog.Logger
-	DB     *sql.DB
+	Clock   timeutils.Clock
+	Logger  *slog.Logger
+	DB      *sql.DB
+	Dialect dbutils.Dialect
 
 	MsgRepo         *storage.MessageRepository
 	ArchivedMsgRepo *storage.ArchivedMsgRepository
 
 	EventBus *eventbus.EventBus
+	Metrics  *metrics.Metrics
+	Tracer   *tracing.Tracer
 
 	RequestScopeFactory requestscope.Factory
 
-	PublishMessages  *usecases.PublishMessages
-	ReleaseMessages  *usecases.ReleaseMessages
-	ConsumeMessages  *usecases.ConsumeMessages
-	AckMessages      *usecases.AckMessages
-	NackMessages     *usecases.NackMessages
-	RedirectMessages *usecases.RedirectMessages
-	CheckMessages    *usecases.CheckMessages
-	ArchiveMessages  *usecases.ArchiveMessages
-	ExpireProcessing *usecases.ExpireProcessing
-	ResumeDelayed    *usecases.ResumeDelayed
-
-	Router *http.ServeMux
+	PublishMessages        *usecases.PublishMessages
+	ReleaseMessages        *usecases.ReleaseMessages
+	ConsumeMessages        *usecases.ConsumeMessages
+	AckMessages            *usecases.AckMessages
+	NackMessages           *usecases.NackMessages
+	RedirectMessages       *usecases.RedirectMessages
+	ExtendMessages         *usecases.ExtendMessages
+	CheckMessages          *usecases.CheckMessages
+	ListMessages           *usecases.ListMessages
+	RedriveDLQ             *usecases.RedriveDLQ
+	GetQueueStats          *usecases.GetQueueStats
+	PauseQueue             *usecases.PauseQueue
+	ResumeQueue            *usecases.ResumeQueue
+	PurgeQueue             *usecases.PurgeQueue
+	ArchiveMessages        *usecases.ArchiveMessages
+	PurgeArchive           *usecases.PurgeArchive
+	DeleteExpiredDedupKeys *usecases.DeleteExpiredDedupKeys
+	ExpireProcessing       *usecases.ExpireProcessing
+	ResumeDelayed          *usecases.ResumeDelayed
+	AgePriorities          *usecases.AgePriorities
+
+	WebhookDispatcher *webhooks.Dispatcher
+
+	Router     *http.ServeMux
+	GRPCServer *grpc.Server
 }
 
 func BuildApp(conf *config.Config, overrides *Overrides) (*App, error) {
@@ -66,80 +95,193 @@ func BuildApp(conf *config.Config, overrides *Overrides) (*App, error) {
 		clock = overrides.Clock
 	}
 
-	if conf.DatabaseType() != config.DBTypePostgres {
-		return nil, errors.New("database type not supported")
+	tracer, err := buildTracer(conf, overrides)
+	if err != nil {
+		return nil, fmt.Errorf("buildTracer: %w", err)
 	}
 
-	dbURL := fmt.Sprintf(
-		"postgres://%s:%s@%s/%s",
-		conf.PostgresConfig().MustValue().Username(),
-		conf.PostgresConfig().MustValue().Password(),
-		conf.PostgresConfig().MustValue().Host(),
-		conf.PostgresConfig().MustValue().DBName(),
-	)
-	db, err := sql.Open("pgx", dbURL)
+	db, dialect, err := openDB(conf)
 	if err != nil {
-		return nil, fmt.Errorf("sql.Open: %w", err)
+		return nil, err
 	}
 
 	db.SetMaxIdleConns(64)
 	db.SetMaxOpenConns(64)
 
-	msgRepo := storage.NewMessageRepository(clock, logger)
+	msgRepo := storage.NewMessageRepository(clock, logger, dialect)
 	archivedMsgRepo := storage.NewArchivedMsgRepository()
+	tokenBucketRepo := storage.NewTokenBucketRepository(dialect)
+	pauseRepo := storage.NewQueuePauseRepository()
+
+	var pubSubDriver eventbus.PubSubDriver = postgres.NewPubSubDriver(db)
+	if conf.EventBusDriver() == config.EventBusDriverMemory {
+		pubSubDriver = memory.NewPubSubDriver()
+	}
 
-	eventBus := eventbus.NewEventBus(logger, clock, postgres.NewPubSubDriver(db))
+	eventBus := eventbus.NewEventBus(logger, clock, pubSubDriver)
 
 	nackPolicy := domain.NewNackPolicy(clock, config.NewDomainProvider(conf))
 
 	requestScopeFactory := NewRequestScopeFactory(eventBus)
 
-	publishMessages := usecases.NewPublishMessages(logger, clock, db, msgRepo, requestScopeFactory, conf)
-	releaseMessages := usecases.NewReleaseMessages(logger, clock, db, msgRepo, requestScopeFactory, conf)
-	consumeMessages := usecases.NewConsumeMessages(logger, clock, db, msgRepo, eventBus, conf)
-	ackMessages := usecases.NewAckMessages(clock, logger, db, msgRepo, requestScopeFactory, conf)
-	nackMessages := usecases.NewNackMessages(clock, logger, db, msgRepo, requestScopeFactory, nackPolicy, conf)
-	redirectMessages := usecases.NewRedirectMessages(clock, logger, db, msgRepo, requestScopeFactory, conf)
-	checkMessages := usecases.NewCheckMessages(db, msgRepo, archivedMsgRepo, conf)
-	archiveMessages := usecases.NewArchiveMessages(clock, db, msgRepo, archivedMsgRepo)
-	expireProcessing := usecases.NewExpireProcessing(clock, logger, db, msgRepo, requestScopeFactory, nackPolicy)
-	resumeDelayed := usecases.NewResumeDelayed(clock, logger, db, msgRepo, requestScopeFactory)
+	appMetrics := metrics.New()
+	appMetrics.MustRegister(metrics.NewQueueCollector(logger, db, msgRepo, conf))
+
+	publishMessages := usecases.NewPublishMessages(logger, clock, db, msgRepo, requestScopeFactory, conf, appMetrics, tracer)
+	releaseMessages := usecases.NewReleaseMessages(logger, clock, db, msgRepo, requestScopeFactory, conf, tracer)
+	consumeMessages := usecases.NewConsumeMessages(logger, clock, db, msgRepo, tokenBucketRepo, eventBus, conf, appMetrics, tracer)
+	ackMessages := usecases.NewAckMessages(clock, logger, db, msgRepo, requestScopeFactory, conf, appMetrics, tracer)
+	nackMessages := usecases.NewNackMessages(clock, logger, db, msgRepo, requestScopeFactory, nackPolicy, conf, appMetrics, tracer)
+	redirectMessages := usecases.NewRedirectMessages(clock, logger, db, msgRepo, requestScopeFactory, conf, appMetrics, tracer)
+	extendMessages := usecases.NewExtendMessages(clock, logger, db, msgRepo, conf, tracer)
+	checkMessages := usecases.NewCheckMessages(db, msgRepo, archivedMsgRepo, conf, tracer)
+	listMessages := usecases.NewListMessages(logger, db, msgRepo, archivedMsgRepo, conf, tracer)
+	redriveDLQ := usecases.NewRedriveDLQ(clock, logger, db, msgRepo, requestScopeFactory, conf, appMetrics, tracer)
+	getQueueStats := usecases.NewGetQueueStats(clock, logger, db, msgRepo, conf, tracer)
+	pauseQueue := usecases.NewPauseQueue(clock, logger, db, pauseRepo, conf, tracer)
+	resumeQueue := usecases.NewResumeQueue(logger, db, pauseRepo, requestScopeFactory, conf, tracer)
+	purgeQueue := usecases.NewPurgeQueue(clock, logger, db, msgRepo, archivedMsgRepo, conf, appMetrics, tracer)
+	archiveMessages := usecases.NewArchiveMessages(clock, db, msgRepo, archivedMsgRepo, appMetrics, tracer)
+	purgeArchive := usecases.NewPurgeArchive(clock, db, archivedMsgRepo, conf, appMetrics, tracer)
+	deleteExpiredDedupKeys := usecases.NewDeleteExpiredDedupKeys(clock, db, msgRepo, appMetrics, tracer)
+	expireProcessing := usecases.NewExpireProcessing(clock, logger, db, msgRepo, requestScopeFactory, nackPolicy, conf, appMetrics, tracer)
+	resumeDelayed := usecases.NewResumeDelayed(clock, logger, db, msgRepo, requestScopeFactory, conf, appMetrics, tracer)
+	agePriorities := usecases.NewAgePriorities(clock, logger, db, msgRepo, conf, appMetrics, tracer)
+
+	webhookDispatcher := webhooks.NewDispatcher(logger, conf, consumeMessages, ackMessages, nackMessages, appMetrics, tracer)
+
+	apiMux := http.NewServeMux()
+	routes.NewPublishMessages(logger, publishMessages).Mount(apiMux)
+	routes.NewReleaseMessages(logger, releaseMessages).Mount(apiMux)
+	routes.NewConsumeMessages(logger, consumeMessages).Mount(apiMux)
+	routes.NewAckMessages(logger, ackMessages).Mount(apiMux)
+	routes.NewNackMessages(logger, nackMessages).Mount(apiMux)
+	routes.NewRedirectMessages(logger, redirectMessages).Mount(apiMux)
+	routes.NewExtendMessages(logger, extendMessages).Mount(apiMux)
+	routes.NewCheckMessages(logger, checkMessages).Mount(apiMux)
+	routes.NewListMessages(logger, listMessages).Mount(apiMux)
+	routes.NewRedriveDLQ(logger, redriveDLQ).Mount(apiMux)
+	routes.NewGetQueueStats(logger, getQueueStats).Mount(apiMux)
+	routes.NewPauseQueue(logger, pauseQueue).Mount(apiMux)
+	routes.NewResumeQueue(logger, resumeQueue).Mount(apiMux)
+	routes.NewPurgeQueue(logger, purgeQueue).Mount(apiMux)
+
+	var authenticator *auth.Authenticator
+	var apiHandler http.Handler = apiMux
+	// queue names and depths are exposed by metrics, so they need a key as well
+	var metricsHandler http.Handler = appMetrics.Handler()
+	if authConfig, isSet := conf.AuthConfig().Value(); isSet {
+		authenticator = auth.NewAuthenticator(authConfig.APIKeys())
+		apiHandler = base.NewAuthMiddleware(logger, authenticator, apiMux)
+		metricsHandler = base.NewAuthMiddleware(logger, authenticator, metricsHandler)
+	}
+
+	apiHandler = base.NewTracingMiddleware(tracer, apiMux, base.NewMetricsMiddleware(appMetrics, apiMux, apiHandler))
 
 	mux := http.NewServeMux()
 	openapi.MountHandlers(mux)
-	routes.NewPublishMessages(logger, publishMessages).Mount(mux)
-	routes.NewReleaseMessages(logger, releaseMessages).Mount(mux)
-	routes.NewConsumeMessages(logger, consumeMessages).Mount(mux)
-	routes.NewAckMessages(logger, ackMessages).Mount(mux)
-	routes.NewNackMessages(logger, nackMessages).Mount(mux)
-	routes.NewRedirectMessages(logger, redirectMessages).Mount(mux)
-	routes.NewCheckMessages(logger, checkMessages).Mount(mux)
+	mux.Handle("/messages/", apiHandler)
+	mux.Handle("/queues/", apiHandler)
+	mux.Handle("GET /metrics", metricsHandler)
+
+	grpcServer := grpcserver.NewGRPCServer(grpcserver.NewServer(
+		logger,
+		publishMessages,
+		releaseMessages,
+		consumeMessages,
+		ackMessages,
+		nackMessages,
+		redirectMessages,
+		extendMessages,
+		checkMessages,
+	), authenticator, tracer)
 
 	return &App{
 		Config: conf,
 
-		Clock:  clock,
-		Logger: logger,
-		DB:     db,
+		Clock:   clock,
+		Logger:  logger,
+		DB:      db,
+		Dialect: dialect,
 
 		MsgRepo:         msgRepo,
 		ArchivedMsgRepo: archivedMsgRepo,
 
 		EventBus: eventBus,
+		Metrics:  appMetrics,
+		Tracer:   tracer,
 
 		RequestScopeFactory: requestScopeFactory,
 
-		PublishMessages:  publishMessages,
-		ReleaseMessages:  releaseMessages,
-		ConsumeMessages:  consumeMessages,
-		AckMessages:      ackMessages,
-		NackMessages:     nackMessages,
-		RedirectMessages: redirectMessages,
-		CheckMessages:    checkMessages,
-		ArchiveMessages:  archiveMessages,
-		ExpireProcessing: expireProcessing,
-		ResumeDelayed:    resumeDelayed,
-
-		Router: mux,
+		PublishMessages:        publishMessages,
+		ReleaseMessages:        releaseMessages,
+		ConsumeMessages:        consumeMessages,
+		AckMessages:            ackMessages,
+		NackMessages:           nackMessages,
+		RedirectMessages:       redirectMessages,
+		ExtendMessages:         extendMessages,
+		CheckMessages:          checkMessages,
+		ListMessages:           listMessages,
+		RedriveDLQ:             redriveDLQ,
+		GetQueueStats:          getQueueStats,
+		PauseQueue:             pauseQueue,
+		ResumeQueue:            resumeQueue,
+		PurgeQueue:             purgeQueue,
+		ArchiveMessages:        archiveMessages,
+		PurgeArchive:           purgeArchive,
+		DeleteExpiredDedupKeys: deleteExpiredDedupKeys,
+		ExpireProcessing:       expireProcessing,
+		ResumeDelayed:          resumeDelayed,
+		AgePriorities:          agePriorities,
+
+		WebhookDispatcher: webhookDispatcher,
+
+		Router:     mux,
+		GRPCServer: grpcServer,
 	}, nil
 }
+
+func openDB(conf *config.Config) (*sql.DB, dbutils.Dialect, error) {
+	if sqliteConf, isSet := conf.SQLiteConfig().Value(); isSet {
+		// Times are written as unix microseconds, so they compare and sort in queries like in Postgres.
+		// Write transactions take the lock at BEGIN: a transaction upgrading its read lock
+		// fails at once if another one writes, instead of waiting for the busy timeout.
+		dsn := "file:" + sqliteConf.Path() + "?_time_integer_format=unix_micro&_inttotime=true" +
+			"&_txlock=immediate&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+
+		db, err := sql.Open("sqlite", dsn)
+		if err != nil {
+			return nil, "", fmt.Errorf("sql.Open: %w", err)
+		}
+
+		return db, dbutils.DialectSQLite, nil
+	}
+
+	pgConf := conf.PostgresConfig().MustValue()
+	dbURL := fmt.Sprintf(
+		"postgres://%s:%s@%s/%s",
+		pgConf.Username(),
+		pgConf.Password(),
+		pgConf.Host(),
+		pgConf.DBName(),
+	)
+	db, err := sql.Open("pgx", dbURL)
+	if err != nil {
+		return nil, "", fmt.Errorf("sql.Open: %w", err)
+	}
+
+	return db, dbutils.DialectPostgres, nil
+}
+
+func buildTracer(conf *config.Config, overrides *Overrides) (*tracing.Tracer, error) {
+	if overrides.SpanExporter != nil {
+		return tracing.NewTracer(sdktrace.NewSimpleSpanProcessor(overrides.SpanExporter), 1), nil
+	}
+
+	tracingConfig, isSet := conf.TracingConfig().Value()
+	if !isSet {
+		return tracing.NewNoopTracer(), nil
+	}
+
+	return tracing.NewOTLPTracer(tracingConfig)
+}
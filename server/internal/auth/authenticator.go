@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+)
+
+type APIKey struct {
+	keyHash   [sha256.Size]byte
+	principal *Principal
+}
+
+func NewAPIKey(key string, principal *Principal) (*APIKey, error) {
+	if key == "" {
+		return nil, errors.New("api key must not be empty")
+	}
+
+	return &APIKey{
+		keyHash:   sha256.Sum256([]byte(key)),
+		principal: principal,
+	}, nil
+}
+
+func (k *APIKey) Principal() *Principal { return k.principal }
+
+type Authenticator struct {
+	keys []*APIKey
+}
+
+func NewAuthenticator(keys []*APIKey) *Authenticator {
+	return &Authenticator{keys: keys}
+}
+
+func (a *Authenticator) Authenticate(key string) (*Principal, bool) {
+	var found *Principal
+
+	// hashes have the same size whatever the key, so that the comparison doesn't leak its length;
+	// every key is compared in constant time to not leak which prefix matched
+	keyHash := sha256.Sum256([]byte(key))
+
+	for _, apiKey := range a.keys {
+		if subtle.ConstantTimeCompare(apiKey.keyHash[:], keyHash[:]) == 1 {
+			found = apiKey.principal
+		}
+	}
+
+	return found, found != nil
+}
@@ -0,0 +1,40 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthenticator_Authenticate(t *testing.T) {
+	svc, err := NewPrincipal("svc", map[Action][]string{ActionPublish: {"orders"}})
+	require.NoError(t, err)
+	admin, err := NewPrincipal("admin", map[Action][]string{ActionPublish: {"*"}})
+	require.NoError(t, err)
+
+	svcKey, err := NewAPIKey("svc-key", svc)
+	require.NoError(t, err)
+	adminKey, err := NewAPIKey("admin-key-long", admin)
+	require.NoError(t, err)
+
+	authenticator := NewAuthenticator([]*APIKey{svcKey, adminKey})
+
+	tests := []struct {
+		key      string
+		expected *Principal
+	}{
+		{"svc-key", svc},
+		{"admin-key-long", admin},
+		{"svc-ke", nil},
+		{"svc-key-", nil},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			principal, ok := authenticator.Authenticate(tt.key)
+			require.Equal(t, tt.expected != nil, ok)
+			require.Same(t, tt.expected, principal)
+		})
+	}
+}
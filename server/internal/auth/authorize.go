@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"server/internal/domain"
+)
+
+var ErrForbidden = errors.New("forbidden")
+
+type principalCtxKey struct{}
+
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalCtxKey{}, principal)
+}
+
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalCtxKey{}).(*Principal)
+	return principal, ok
+}
+
+// Authorize checks that the principal attached to the context may perform the action on the queue.
+// Contexts without a principal belong to internal callers (background workers, single-process mode)
+// or to an API with authentication disabled, so they are always allowed.
+func Authorize(ctx context.Context, action Action, queue domain.QueueName) error {
+	return AuthorizeAny(ctx, queue, action)
+}
+
+// AuthorizeAny is like Authorize, but passes if any of the given actions is allowed.
+func AuthorizeAny(ctx context.Context, queue domain.QueueName, actions ...Action) error {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	for _, action := range actions {
+		if principal.Can(action, queue) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s can't %v on queue %s", ErrForbidden, principal.Name(), actions, queue)
+}
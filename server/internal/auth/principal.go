@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+
+	"server/internal/domain"
+)
+
+type Action string
+
+const (
+	ActionPublish Action = "publish"
+	ActionConsume Action = "consume"
+	ActionAdmin   Action = "admin" // implies all other actions
+)
+
+func AllActions() []Action {
+	return []Action{ActionPublish, ActionConsume, ActionAdmin}
+}
+
+// Principal is an authenticated API client with a set of granted actions,
+// each scoped to queue name glob patterns (path.Match syntax, "*" also matches dots: "orders.*").
+type Principal struct {
+	name   string
+	grants map[Action][]string
+}
+
+func NewPrincipal(name string, grants map[Action][]string) (*Principal, error) {
+	if name == "" {
+		return nil, errors.New("principal name must not be empty")
+	}
+
+	for action, patterns := range grants {
+		if !slices.Contains(AllActions(), action) {
+			return nil, fmt.Errorf("unknown action %q", action)
+		}
+
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("invalid queue pattern %q: %w", pattern, err)
+			}
+		}
+	}
+
+	return &Principal{
+		name:   name,
+		grants: grants,
+	}, nil
+}
+
+func (p *Principal) Name() string { return p.name }
+
+func (p *Principal) Can(action Action, queue domain.QueueName) bool {
+	return matchAny(p.grants[action], queue) || matchAny(p.grants[ActionAdmin], queue)
+}
+
+func matchAny(patterns []string, queue domain.QueueName) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, queue.String()); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"server/internal/domain"
+)
+
+func TestPrincipal_Can(t *testing.T) {
+	principal, err := NewPrincipal("svc", map[Action][]string{
+		ActionPublish: {"orders.*", "emails"},
+		ActionConsume: {"orders.paid"},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		action   Action
+		queue    string
+		expected bool
+	}{
+		{ActionPublish, "orders.created", true},
+		{ActionPublish, "emails", true},
+		{ActionPublish, "emails.sent", false},
+		{ActionPublish, "orders.created:dl", true}, // wildcards cover DLQs as well
+		{ActionPublish, "emails:dl", false},
+		{ActionConsume, "orders.paid", true},
+		{ActionConsume, "orders.paid:dl", false}, // an exact grant doesn't cover the DLQ of the queue
+		{ActionConsume, "orders.created", false},
+		{ActionAdmin, "orders.paid", false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.action)+" "+tt.queue, func(t *testing.T) {
+			require.Equal(t, tt.expected, principal.Can(tt.action, domain.UnsafeQueueName(tt.queue)))
+		})
+	}
+}
+
+func TestPrincipal_AdminImpliesAllActions(t *testing.T) {
+	principal, err := NewPrincipal("operator", map[Action][]string{
+		ActionAdmin: {"*"},
+	})
+	require.NoError(t, err)
+
+	for _, action := range AllActions() {
+		require.True(t, principal.Can(action, domain.UnsafeQueueName("any")))
+	}
+}
+
+func TestNewPrincipal_Invalid(t *testing.T) {
+	_, err := NewPrincipal("svc", map[Action][]string{"delete": {"*"}})
+	require.ErrorContains(t, err, "unknown action")
+
+	_, err = NewPrincipal("svc", map[Action][]string{ActionPublish: {"[orders"}})
+	require.ErrorContains(t, err, "invalid queue pattern")
+}
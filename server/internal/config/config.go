@@ -3,7 +3,12 @@ package config
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
 
+	"server/internal/auth"
 	"server/internal/domain"
 	"server/internal/utils/opt"
 )
@@ -12,30 +17,71 @@ type DBType string
 
 const (
 	DBTypePostgres DBType = "postgres"
+	DBTypeSQLite   DBType = "sqlite" // a single file, only for a single instance
+)
+
+type EventBusDriver string
+
+const (
+	EventBusDriverPostgres EventBusDriver = "postgres" // LISTEN/NOTIFY, works across instances
+	EventBusDriverMemory   EventBusDriver = "memory"   // in-process, only for a single instance
 )
 
 type Config struct {
 	apiPort        uint16
+	grpcPort       opt.Val[uint16]
 	databaseType   DBType
 	postgresConfig opt.Val[*PostgresConfig]
+	sqliteConfig   opt.Val[*SQLiteConfig]
+	checkSchema    bool
+	eventBusDriver EventBusDriver
 	batchSizeLimit int
-	queues         map[domain.QueueName]*domain.QueueConfig
+	statsCacheTTL  opt.Val[time.Duration]
+	queues         atomic.Pointer[map[domain.QueueName]*domain.QueueConfig] // swapped on reload, maps are never modified
+	authConfig     opt.Val[*AuthConfig]
+	webhooks       map[domain.QueueName]*WebhookConfig
+	tracingConfig  opt.Val[*TracingConfig]
 }
 
 func NewConfig(
 	apiPort uint16,
+	grpcPort opt.Val[uint16],
 	pgConfig opt.Val[*PostgresConfig],
+	sqliteConfig opt.Val[*SQLiteConfig],
+	checkSchema bool,
+	eventBusDriver EventBusDriver,
 	batchSizeLimit int,
+	statsCacheTTL opt.Val[time.Duration],
 	queues map[domain.QueueName]*domain.QueueConfig,
+	authConfig opt.Val[*AuthConfig],
+	webhooks map[domain.QueueName]*WebhookConfig,
+	tracingConfig opt.Val[*TracingConfig],
 ) (*Config, error) {
-	if !pgConfig.IsSet() {
-		return nil, fmt.Errorf("postgres config required")
+	if pgConfig.IsSet() == sqliteConfig.IsSet() {
+		return nil, errors.New("either postgres or sqlite config required")
+	}
+
+	databaseType := DBTypePostgres
+	if sqliteConfig.IsSet() {
+		databaseType = DBTypeSQLite
+	}
+
+	if eventBusDriver != EventBusDriverPostgres && eventBusDriver != EventBusDriverMemory {
+		return nil, fmt.Errorf("unknown event bus driver %q", eventBusDriver)
+	}
+
+	if databaseType == DBTypeSQLite && eventBusDriver == EventBusDriverPostgres {
+		return nil, errors.New("sqlite requires the memory event bus")
 	}
 
 	if batchSizeLimit <= 0 {
 		return nil, errors.New("batch size limit must be greater than zero")
 	}
 
+	if ttl, isSet := statsCacheTTL.Value(); isSet && ttl <= 0 {
+		return nil, errors.New("stats cache ttl must be greater than zero")
+	}
+
 	if len(queues) == 0 {
 		return nil, errors.New("at least one queue must be defined")
 	}
@@ -55,22 +101,88 @@ func NewConfig(
 		}
 	}
 
-	return &Config{
+	for queue, webhook := range webhooks {
+		config, configExist := queues[queue]
+		if !configExist {
+			return nil, fmt.Errorf("webhook of queue %q: queue config not found", queue)
+		}
+		if webhook.Timeout() > config.ProcessingTimeout() {
+			return nil, fmt.Errorf("webhook of queue %q: timeout must not exceed processing timeout", queue)
+		}
+		// the dispatcher consumes as many messages as it delivers concurrently
+		if webhook.Concurrency() > batchSizeLimit {
+			return nil, fmt.Errorf("webhook of queue %q: concurrency must not exceed batch size limit", queue)
+		}
+	}
+
+	conf := &Config{
 		apiPort:        apiPort,
-		databaseType:   DBTypePostgres,
+		grpcPort:       grpcPort,
+		databaseType:   databaseType,
 		postgresConfig: pgConfig,
+		sqliteConfig:   sqliteConfig,
+		checkSchema:    checkSchema,
+		eventBusDriver: eventBusDriver,
 		batchSizeLimit: batchSizeLimit,
-		queues:         queues,
-	}, nil
+		statsCacheTTL:  statsCacheTTL,
+		authConfig:     authConfig,
+		webhooks:       webhooks,
+		tracingConfig:  tracingConfig,
+	}
+	conf.queues.Store(&queues)
+
+	return conf, nil
 }
 
 func (c *Config) APIPort() uint16                          { return c.apiPort }
+func (c *Config) GRPCPort() opt.Val[uint16]                { return c.grpcPort }
 func (c *Config) DatabaseType() DBType                     { return c.databaseType }
 func (c *Config) PostgresConfig() opt.Val[*PostgresConfig] { return c.postgresConfig }
+func (c *Config) SQLiteConfig() opt.Val[*SQLiteConfig]     { return c.sqliteConfig }
+func (c *Config) CheckSchema() bool                        { return c.checkSchema }
+func (c *Config) EventBusDriver() EventBusDriver           { return c.eventBusDriver }
 func (c *Config) BatchSizeLimit() int                      { return c.batchSizeLimit }
+func (c *Config) StatsCacheTTL() opt.Val[time.Duration]    { return c.statsCacheTTL }
+func (c *Config) AuthConfig() opt.Val[*AuthConfig]         { return c.authConfig }
+func (c *Config) TracingConfig() opt.Val[*TracingConfig]   { return c.tracingConfig }
+
+// QueueNames returns names of all configured queues (including DLQs) in alphabetical order.
+func (c *Config) QueueNames() []domain.QueueName {
+	queues := *c.queues.Load()
+
+	names := make([]domain.QueueName, 0, len(queues))
+	for name := range queues {
+		names = append(names, name)
+	}
+
+	slices.SortFunc(names, func(a, b domain.QueueName) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	return names
+}
+
+// WebhookQueues returns names of queues in push mode in alphabetical order.
+func (c *Config) WebhookQueues() []domain.QueueName {
+	var names []domain.QueueName
+	for _, name := range c.QueueNames() {
+		if _, exist := c.webhooks[name]; exist {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// GetWebhookConfig returns webhook config of the queue if the queue is in push mode.
+func (c *Config) GetWebhookConfig(queue domain.QueueName) opt.Val[*WebhookConfig] {
+	if webhook, exist := c.webhooks[queue]; exist {
+		return opt.Some(webhook)
+	}
+	return opt.None[*WebhookConfig]()
+}
 
 func (c *Config) GetQueueConfig(queue domain.QueueName) (*domain.QueueConfig, error) {
-	if conf, exist := c.queues[queue]; exist {
+	if conf, exist := (*c.queues.Load())[queue]; exist {
 		return conf, nil
 	}
 	return nil, newQueueNotFoundError(queue)
@@ -111,3 +223,44 @@ func (c *PostgresConfig) Host() string     { return c.host }
 func (c *PostgresConfig) DBName() string   { return c.dbName }
 func (c *PostgresConfig) Username() string { return c.username }
 func (c *PostgresConfig) Password() string { return c.password }
+
+type SQLiteConfig struct {
+	path string
+}
+
+func NewSQLiteConfig(path string) (*SQLiteConfig, error) {
+	if path == "" {
+		return nil, errors.New("path must not be empty")
+	}
+
+	return &SQLiteConfig{
+		path: path,
+	}, nil
+}
+
+func (c *SQLiteConfig) Path() string { return c.path }
+
+type AuthConfig struct {
+	apiKeys []*auth.APIKey
+}
+
+func NewAuthConfig(apiKeys []*auth.APIKey) (*AuthConfig, error) {
+	if len(apiKeys) == 0 {
+		return nil, errors.New("at least one api key must be defined")
+	}
+
+	names := make(map[string]struct{}, len(apiKeys))
+	for _, apiKey := range apiKeys {
+		name := apiKey.Principal().Name()
+		if _, exist := names[name]; exist {
+			return nil, fmt.Errorf("duplicate api key name %q", name)
+		}
+		names[name] = struct{}{}
+	}
+
+	return &AuthConfig{
+		apiKeys: apiKeys,
+	}, nil
+}
+
+func (c *AuthConfig) APIKeys() []*auth.APIKey { return c.apiKeys }
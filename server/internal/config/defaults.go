@@ -10,9 +10,18 @@ import (
 const (
 	DefaultAPIPort            = uint16(8060)
 	DefaultBatchSizeLimit     = 200
+	DefaultEventBusDriver     = EventBusDriverPostgres
 	DefaultBackoffEnabled     = true
 	DefaultBackoffMaxAttempts = 5
 	DefaultDeadLettering      = true
+	DefaultWebhookConcurrency = 1
+	DefaultWebhookTimeout     = 30 * time.Second
+	DefaultRateLimitInterval  = time.Second
+	DefaultAgingStep          = 1
+	DefaultPriority           = 100
+	DefaultDedupWindow        = 5 * time.Minute
+	DefaultTracingInsecure    = false
+	DefaultTracingSampleRatio = 1.0
 )
 
 func DefaultBackoffShape() []time.Duration {
@@ -36,7 +45,9 @@ func DefaultBackoffConfig() *domain.BackoffConfig {
 	return cfg
 }
 
-func DefaultDLQueueConfig(parentProcessingTimeout time.Duration) *domain.QueueConfig {
+// DefaultDLQueueConfig derives config of a DLQ from its parent queue.
+// Archived dead letters are kept as long as archived messages of the parent queue.
+func DefaultDLQueueConfig(parent *domain.QueueConfig) *domain.QueueConfig {
 	backoffConf, err := domain.NewBackoffConfig(
 		[]time.Duration{time.Minute},
 		opt.None[int](), // infinite retries
@@ -47,13 +58,20 @@ func DefaultDLQueueConfig(parentProcessingTimeout time.Duration) *domain.QueueCo
 
 	// derive timeout from the parent queue, but not less than a minute
 	timeout := time.Minute
-	if parentProcessingTimeout > timeout {
-		timeout = parentProcessingTimeout
+	if parent.ProcessingTimeout() > timeout {
+		timeout = parent.ProcessingTimeout()
 	}
 
 	conf, err := domain.NewQueueConfig(
 		opt.Some(backoffConf),
 		timeout,
+		opt.None[time.Duration](),
+		parent.Retention(),
+		opt.None[*domain.RateLimit](),
+		opt.None[*domain.PriorityAging](),
+		parent.PriorityRange(), // dead-lettered messages keep their priority
+		parent.DefaultPriority(),
+		parent.DedupWindow(),
 		false,
 	)
 	if err != nil {
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"fmt"
+)
+
+// ReloadQueues replaces queue configs with the ones of next, a freshly loaded and validated config.
+// Other settings are fixed at startup and changing them requires a restart.
+//
+// The change is rejected if it would orphan a DLQ, as its messages couldn't be consumed or redriven
+// anymore, or if it would break a webhook, as webhook deliveries are set up at startup too. Since the
+// DLQ config comes with its source queue, a queue with dead lettering can't be removed either, whether
+// its DLQ holds messages or not. Messages of a removed queue are kept as they are, timed out or delayed
+// ones are left to workers until the queue is configured again.
+func (c *Config) ReloadQueues(next *Config) error {
+	current := *c.queues.Load()
+	reloaded := *next.queues.Load()
+
+	for queue := range current {
+		if !queue.IsDLQ() {
+			continue
+		}
+		if _, exist := reloaded[queue]; !exist {
+			return fmt.Errorf("dlq %q would be orphaned", queue)
+		}
+	}
+
+	for queue, webhook := range c.webhooks {
+		queueConfig, exist := reloaded[queue]
+		if !exist {
+			return fmt.Errorf("webhook of queue %q: queue config not found", queue)
+		}
+		if webhook.Timeout() > queueConfig.ProcessingTimeout() {
+			return fmt.Errorf("webhook of queue %q: timeout must not exceed processing timeout", queue)
+		}
+	}
+
+	c.queues.Store(&reloaded)
+
+	return nil
+}
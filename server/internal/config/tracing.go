@@ -0,0 +1,34 @@
+package config
+
+import "errors"
+
+// TracingConfig enables export of spans to an OpenTelemetry collector via OTLP/gRPC.
+type TracingConfig struct {
+	endpoint    string
+	insecure    bool
+	sampleRatio float64
+}
+
+func NewTracingConfig(
+	endpoint string,
+	insecure bool,
+	sampleRatio float64,
+) (*TracingConfig, error) {
+	if endpoint == "" {
+		return nil, errors.New("endpoint must not be empty")
+	}
+
+	if sampleRatio < 0 || sampleRatio > 1 {
+		return nil, errors.New("sample ratio must be between 0 and 1")
+	}
+
+	return &TracingConfig{
+		endpoint:    endpoint,
+		insecure:    insecure,
+		sampleRatio: sampleRatio,
+	}, nil
+}
+
+func (c *TracingConfig) Endpoint() string     { return c.endpoint }
+func (c *TracingConfig) Insecure() bool       { return c.insecure }
+func (c *TracingConfig) SampleRatio() float64 { return c.sampleRatio }
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"time"
+)
+
+// WebhookConfig turns a queue into push mode: messages are delivered to the URL
+// by the webhook dispatcher instead of being consumed via the API.
+type WebhookConfig struct {
+	url         string
+	headers     map[string]string
+	concurrency int
+	timeout     time.Duration
+}
+
+func NewWebhookConfig(
+	rawURL string,
+	headers map[string]string,
+	concurrency int,
+	timeout time.Duration,
+) (*WebhookConfig, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("url.Parse: %w", err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, errors.New("url must have http or https scheme")
+	}
+
+	if concurrency <= 0 {
+		return nil, errors.New("concurrency must be greater than zero")
+	}
+
+	if timeout <= 0 {
+		return nil, errors.New("timeout must be greater than zero")
+	}
+
+	return &WebhookConfig{
+		url:         rawURL,
+		headers:     maps.Clone(headers),
+		concurrency: concurrency,
+		timeout:     timeout,
+	}, nil
+}
+
+func (c *WebhookConfig) URL() string                { return c.url }
+func (c *WebhookConfig) Headers() map[string]string { return maps.Clone(c.headers) }
+func (c *WebhookConfig) Concurrency() int           { return c.concurrency }
+func (c *WebhookConfig) Timeout() time.Duration     { return c.timeout }
@@ -11,12 +11,29 @@ import (
 type ConfigDTO struct {
 	DB struct {
 		PostgresConfig *PostgresConfig `yaml:"postgres"`
+		SQLiteConfig   *SQLiteConfig   `yaml:"sqlite"` // instead of postgres, only for a single instance
+
+		// refuse to start if the schema is not migrated to the version the binary expects
+		CheckSchema bool `yaml:"check_schema"`
 	} `yaml:"db"`
 	App *struct {
 		APIPort        *uint16 `yaml:"api_port"`
+		GRPCPort       *uint16 `yaml:"grpc_port"` // gRPC API is disabled if not set
 		BatchSizeLimit *int    `yaml:"batch_size_limit"`
+
+		// "postgres" (default) or "memory", the latter only works with a single instance
+		// and is the default and the only option with sqlite
+		EventBus *string `yaml:"event_bus"`
+
+		// queue stats are collected on every request if not set
+		StatsCacheTTL *time.Duration `yaml:"stats_cache_ttl"`
+
+		// default retention of archived messages, can be overridden per queue
+		ArchiveRetention *time.Duration `yaml:"archive_retention"`
 	} `yaml:"app"`
-	Queues map[string]QueueConfig `yaml:"queues"`
+	Queues  map[string]QueueConfig `yaml:"queues"`
+	Auth    *AuthConfig            `yaml:"auth"`
+	Tracing *TracingConfig         `yaml:"tracing"` // tracing is disabled if not set
 }
 
 type PostgresConfig struct {
@@ -26,10 +43,46 @@ type PostgresConfig struct {
 	Password string `yaml:"password"`
 }
 
+type SQLiteConfig struct {
+	Path string `yaml:"path"`
+}
+
 type QueueConfig struct {
-	Backoff           *BackoffConfig `yaml:"backoff"`
-	ProcessingTimeout time.Duration  `yaml:"processing_timeout"`
-	DeadLettering     *bool          `yaml:"dead_lettering"`
+	Backoff           *BackoffConfig  `yaml:"backoff"`
+	ProcessingTimeout time.Duration   `yaml:"processing_timeout"`
+	MaxProcessingTime *time.Duration  `yaml:"max_processing_time"`
+	Retention         *time.Duration  `yaml:"retention"`
+	DeadLettering     *bool           `yaml:"dead_lettering"`
+	Webhook           *WebhookConfig  `yaml:"webhook"`
+	RateLimit         *RateLimit      `yaml:"rate_limit"`
+	PriorityAging     *PriorityAging  `yaml:"priority_aging"`
+	Priority          *PriorityConfig `yaml:"priority"`
+	DedupWindow       *time.Duration  `yaml:"dedup_window"`
+}
+
+type PriorityConfig struct {
+	Min     *int `yaml:"min"`
+	Max     *int `yaml:"max"`
+	Default *int `yaml:"default"`
+}
+
+type PriorityAging struct {
+	Interval    time.Duration `yaml:"interval"`
+	Step        *int          `yaml:"step"`
+	MaxPriority *int          `yaml:"max_priority"`
+}
+
+type RateLimit struct {
+	Messages int            `yaml:"messages"`
+	Interval *time.Duration `yaml:"interval"`
+	Burst    *int           `yaml:"burst"`
+}
+
+type WebhookConfig struct {
+	URL         string            `yaml:"url"`
+	Headers     map[string]string `yaml:"headers"`
+	Concurrency *int              `yaml:"concurrency"`
+	Timeout     *time.Duration    `yaml:"timeout"`
 }
 
 type BackoffConfig struct {
@@ -38,6 +91,22 @@ type BackoffConfig struct {
 	MaxAttempts *OptionalLimit  `yaml:"max_attempts"`
 }
 
+type TracingConfig struct {
+	Endpoint    string   `yaml:"endpoint"` // OTLP/gRPC collector address, e.g. localhost:4317
+	Insecure    *bool    `yaml:"insecure"`
+	SampleRatio *float64 `yaml:"sample_ratio"`
+}
+
+type AuthConfig struct {
+	APIKeys []APIKeyConfig `yaml:"api_keys"`
+}
+
+type APIKeyConfig struct {
+	Name        string              `yaml:"name"`
+	Key         string              `yaml:"key"`
+	Permissions map[string][]string `yaml:"permissions"` // action => queue name patterns
+}
+
 func NewFromReader(r io.Reader) (*ConfigDTO, error) {
 	var root yaml.Node
 	if err := yaml.NewDecoder(r).Decode(&root); err != nil {
@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"server/internal/auth"
 	"server/internal/config"
 	"server/internal/domain"
 )
@@ -25,10 +26,20 @@ func TestLoadFromFile_full(t *testing.T) {
 	require.Equal(t, "queue", cfg.PostgresConfig().MustValue().DBName())
 	require.Equal(t, "user", cfg.PostgresConfig().MustValue().Username())
 	require.Equal(t, "secret", cfg.PostgresConfig().MustValue().Password())
+	require.True(t, cfg.CheckSchema())
 
 	// App
 	require.Equal(t, uint16(8880), cfg.APIPort())
+	require.Equal(t, uint16(8881), cfg.GRPCPort().MustValue())
 	require.Equal(t, 122, cfg.BatchSizeLimit())
+	require.Equal(t, 10*time.Second, cfg.StatsCacheTTL().MustValue())
+	require.Equal(t, config.EventBusDriverMemory, cfg.EventBusDriver())
+
+	// Tracing
+	tracing := cfg.TracingConfig().MustValue()
+	require.Equal(t, "otel-collector:4317", tracing.Endpoint())
+	require.True(t, tracing.Insecure())
+	require.Equal(t, 0.25, tracing.SampleRatio())
 
 	// Queues
 	for _, qName := range []string{"queue1", "queue2"} {
@@ -36,8 +47,15 @@ func TestLoadFromFile_full(t *testing.T) {
 		require.NoError(t, err)
 
 		require.Equal(t, 5*time.Minute, q.ProcessingTimeout())
+		require.Equal(t, time.Hour, q.MaxProcessingTime().MustValue())
+		require.Equal(t, 720*time.Hour, q.Retention().MustValue())
 		require.True(t, q.IsDeadLetteringOn())
 
+		// DLQ inherits retention
+		dlq, err := cfg.GetQueueConfig(domain.UnsafeQueueName(qName + ":dl"))
+		require.NoError(t, err)
+		require.Equal(t, 720*time.Hour, dlq.Retention().MustValue())
+
 		// Backoff
 		require.True(t, q.Backoff().IsSet())
 		require.Equal(t, []time.Duration{
@@ -63,17 +81,31 @@ func TestLoadFromFile_minimal(t *testing.T) {
 	require.Equal(t, "queue", cfg.PostgresConfig().MustValue().DBName())
 	require.Equal(t, "user", cfg.PostgresConfig().MustValue().Username())
 	require.Equal(t, "", cfg.PostgresConfig().MustValue().Password())
+	require.False(t, cfg.CheckSchema())
 
 	// App
 	require.Equal(t, config.DefaultAPIPort, cfg.APIPort())
+	require.False(t, cfg.GRPCPort().IsSet())
 	require.Equal(t, config.DefaultBatchSizeLimit, cfg.BatchSizeLimit())
+	require.False(t, cfg.StatsCacheTTL().IsSet())
+	require.Equal(t, config.DefaultEventBusDriver, cfg.EventBusDriver())
 
 	// Queue
 	q, err := cfg.GetQueueConfig(domain.UnsafeQueueName("queue1"))
 	require.NoError(t, err)
 
 	require.Equal(t, 5*time.Minute, q.ProcessingTimeout())
+	require.False(t, q.MaxProcessingTime().IsSet())
+	require.False(t, q.Retention().IsSet())
 	require.True(t, q.IsDeadLetteringOn())
+	require.Equal(t, config.DefaultDedupWindow, q.DedupWindow())
+	require.False(t, cfg.GetWebhookConfig(domain.UnsafeQueueName("queue1")).IsSet())
+
+	// Auth
+	require.False(t, cfg.AuthConfig().IsSet())
+
+	// Tracing
+	require.False(t, cfg.TracingConfig().IsSet())
 
 	// Backoff
 	require.Equal(t, config.DefaultBackoffEnabled, q.Backoff().IsSet())
@@ -84,6 +116,18 @@ func TestLoadFromFile_minimal(t *testing.T) {
 	}
 }
 
+func TestLoadFromFile_sqlite(t *testing.T) {
+	cfg, err := LoadFromFile("testdata/config.sqlite.yaml")
+	require.NoError(t, err, "expected config to load without error")
+	require.NotNil(t, cfg)
+
+	require.Equal(t, config.DBTypeSQLite, cfg.DatabaseType())
+	require.False(t, cfg.PostgresConfig().IsSet())
+	require.True(t, cfg.SQLiteConfig().IsSet())
+	require.Equal(t, "/var/lib/queue/queue.db", cfg.SQLiteConfig().MustValue().Path())
+	require.Equal(t, config.EventBusDriverMemory, cfg.EventBusDriver())
+}
+
 func TestLoadFromFile_disabled(t *testing.T) {
 	cfg, err := LoadFromFile("testdata/config.disabled.yaml")
 	require.NoError(t, err, "expected config to load without error")
@@ -134,15 +178,90 @@ func TestLoadFromFile_custom(t *testing.T) {
 	require.NoError(t, err)
 
 	require.Equal(t, 5*time.Minute, q.ProcessingTimeout())
+	require.Equal(t, 24*time.Hour, q.Retention().MustValue())
 	require.True(t, q.IsDeadLetteringOn())
 
+	// Webhook
+	webhook, isSet := cfg.GetWebhookConfig(domain.UnsafeQueueName("queue1")).Value()
+	require.True(t, isSet)
+	require.Equal(t, "https://example.com/hooks/queue1", webhook.URL())
+	require.Equal(t, map[string]string{"Authorization": "Bearer token"}, webhook.Headers())
+	require.Equal(t, 4, webhook.Concurrency())
+	require.Equal(t, config.DefaultWebhookTimeout, webhook.Timeout())
+
+	// Rate limit
+	rateLimit, isSet := q.RateLimit().Value()
+	require.True(t, isSet)
+	require.Equal(t, 100, rateLimit.Messages())
+	require.Equal(t, time.Minute, rateLimit.Interval())
+	require.Equal(t, 100, rateLimit.Burst())
+
+	// Priority
+	require.Equal(t, 0, q.PriorityRange().Min().Int())
+	require.Equal(t, 10, q.PriorityRange().Max().Int())
+	require.Equal(t, 10, q.DefaultPriority().Int()) // global default moved into the range
+
+	// Priority aging
+	aging, isSet := q.PriorityAging().Value()
+	require.True(t, isSet)
+	require.Equal(t, 5*time.Minute, aging.Interval())
+	require.Equal(t, config.DefaultAgingStep, aging.Step())
+	require.Equal(t, 10, aging.MaxPriority().Int(), "capped at the top of the priority range by default")
+
+	// Deduplication
+	require.Equal(t, time.Hour, q.DedupWindow())
+
 	// Backoff
 	require.True(t, q.Backoff().IsSet())
 	require.Equal(t, config.DefaultBackoffShape(), q.Backoff().MustValue().Shape())
 	require.False(t, q.Backoff().MustValue().MaxAttempts().IsSet())
 }
 
+func TestLoadFromFile_auth(t *testing.T) {
+	t.Setenv("ORDERS_API_KEY", "orders-secret")
+	t.Setenv("ADMIN_API_KEY", "123456")
+
+	cfg, err := LoadFromFile("testdata/config.auth.yaml")
+	require.NoError(t, err, "expected config to load without error")
+	require.NotNil(t, cfg)
+
+	require.True(t, cfg.AuthConfig().IsSet())
+	authenticator := auth.NewAuthenticator(cfg.AuthConfig().MustValue().APIKeys())
+
+	_, ok := authenticator.Authenticate("unknown")
+	require.False(t, ok)
+
+	orders, ok := authenticator.Authenticate("orders-secret")
+	require.True(t, ok)
+	require.Equal(t, "orders-service", orders.Name())
+	require.True(t, orders.Can(auth.ActionPublish, domain.UnsafeQueueName("orders.created")))
+	require.True(t, orders.Can(auth.ActionConsume, domain.UnsafeQueueName("orders.paid")))
+	require.False(t, orders.Can(auth.ActionConsume, domain.UnsafeQueueName("orders.created")))
+	require.False(t, orders.Can(auth.ActionPublish, domain.UnsafeQueueName("emails")))
+
+	// numeric-looking keys from env must survive the HIL evaluation
+	operator, ok := authenticator.Authenticate("123456")
+	require.True(t, ok)
+	require.Equal(t, "operator", operator.Name())
+	require.True(t, operator.Can(auth.ActionConsume, domain.UnsafeQueueName("emails")))
+}
+
+func TestLoadFromFile_WebhookConcurrencyAboveBatchSizeLimit(t *testing.T) {
+	_, err := LoadFromFile("testdata/config.err.webhook.yaml")
+	require.ErrorContains(t, err, "concurrency must not exceed batch size limit")
+}
+
+func TestLoadFromFile_AgingAbovePriorityRange(t *testing.T) {
+	_, err := LoadFromFile("testdata/config.err.aging.yaml")
+	require.ErrorContains(t, err, "priority aging max priority must not exceed the priority range")
+}
+
 func TestLoadFromFile_DirectConfigOfDLQNotAllowed(t *testing.T) {
 	_, err := LoadFromFile("testdata/config.err.dlq.yaml")
 	require.ErrorContains(t, err, "manual configuration of DL queues is not allowed")
 }
+
+func TestLoadFromFile_SQLiteWithPostgresEventBus(t *testing.T) {
+	_, err := LoadFromFile("testdata/config.err.sqlite.yaml")
+	require.ErrorContains(t, err, "sqlite requires the memory event bus")
+}
@@ -2,7 +2,9 @@ package yamlconfig
 
 import (
 	"fmt"
+	"time"
 
+	"server/internal/auth"
 	"server/internal/config"
 	"server/internal/domain"
 	"server/internal/utils/opt"
@@ -27,7 +29,23 @@ func MapToAppConfig(dto *ConfigDTO) (*config.Config, error) {
 		postgresConfig = opt.Some(tmp)
 	}
 
+	var sqliteConfig opt.Val[*config.SQLiteConfig]
+	if dto.DB.SQLiteConfig != nil {
+		tmp, err := config.NewSQLiteConfig(dto.DB.SQLiteConfig.Path)
+		if err != nil {
+			return nil, fmt.Errorf("config.NewSQLiteConfig: %w", err)
+		}
+
+		sqliteConfig = opt.Some(tmp)
+	}
+
+	defaultRetention := opt.None[time.Duration]() // keep archived messages forever
+	if dto.App != nil && dto.App.ArchiveRetention != nil {
+		defaultRetention = opt.Some(*dto.App.ArchiveRetention)
+	}
+
 	queues := make(map[domain.QueueName]*domain.QueueConfig, len(dto.Queues))
+	webhooks := make(map[domain.QueueName]*config.WebhookConfig)
 	for qNameStr, qConf := range dto.Queues {
 		qName, err := domain.NewQueueName(qNameStr)
 		if err != nil {
@@ -45,42 +63,256 @@ func MapToAppConfig(dto *ConfigDTO) (*config.Config, error) {
 
 		deadLetteringOn := derefOrDefault(qConf.DeadLettering, config.DefaultDeadLettering)
 
+		rateLimit, err := mapRateLimit(qConf.RateLimit)
+		if err != nil {
+			return nil, fmt.Errorf("queue %s: %w", qNameStr, err)
+		}
+
+		priorityRange, defaultPriority, err := mapPriorityConfig(qConf.Priority)
+		if err != nil {
+			return nil, fmt.Errorf("queue %s: %w", qNameStr, err)
+		}
+
+		priorityAging, err := mapPriorityAging(qConf.PriorityAging, priorityRange)
+		if err != nil {
+			return nil, fmt.Errorf("queue %s: %w", qNameStr, err)
+		}
+
+		retention := defaultRetention
+		if qConf.Retention != nil {
+			retention = opt.Some(*qConf.Retention)
+		}
+
 		queues[qName], err = domain.NewQueueConfig(
 			backoffConfig,
 			qConf.ProcessingTimeout,
+			opt.FromRef(qConf.MaxProcessingTime),
+			retention,
+			rateLimit,
+			priorityAging,
+			priorityRange,
+			defaultPriority,
+			derefOrDefault(qConf.DedupWindow, config.DefaultDedupWindow),
 			deadLetteringOn,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("queue %s: domain.NewQueueConfig: %w", qNameStr, err)
 		}
 
+		if qConf.Webhook != nil {
+			webhooks[qName], err = mapWebhookConfig(qConf.Webhook, qConf.ProcessingTimeout)
+			if err != nil {
+				return nil, fmt.Errorf("queue %s: %w", qNameStr, err)
+			}
+		}
+
 		if deadLetteringOn {
 			dlQueue, err := qName.DLQName()
 			if err != nil {
 				return nil, fmt.Errorf("queue.DLQName: %w", err)
 			}
 
-			queues[dlQueue] = config.DefaultDLQueueConfig(qConf.ProcessingTimeout)
+			queues[dlQueue] = config.DefaultDLQueueConfig(queues[qName])
 		}
 	}
 
 	apiPort := config.DefaultAPIPort
+	grpcPort := opt.None[uint16]()
 	batchSizeLimit := config.DefaultBatchSizeLimit
+	eventBusDriver := config.DefaultEventBusDriver
+	if sqliteConfig.IsSet() {
+		// there is no LISTEN/NOTIFY in SQLite
+		eventBusDriver = config.EventBusDriverMemory
+	}
+	statsCacheTTL := opt.None[time.Duration]()
 	if dto.App != nil {
 		if dto.App.APIPort != nil {
 			apiPort = *dto.App.APIPort
 		}
+		grpcPort = opt.FromRef(dto.App.GRPCPort)
 		if dto.App.BatchSizeLimit != nil {
 			batchSizeLimit = *dto.App.BatchSizeLimit
 		}
+		statsCacheTTL = opt.FromRef(dto.App.StatsCacheTTL)
+		if dto.App.EventBus != nil {
+			eventBusDriver = config.EventBusDriver(*dto.App.EventBus)
+		}
+	}
+
+	authConfig, err := mapAuthConfig(dto.Auth)
+	if err != nil {
+		return nil, fmt.Errorf("auth: %w", err)
+	}
+
+	tracingConfig, err := mapTracingConfig(dto.Tracing)
+	if err != nil {
+		return nil, fmt.Errorf("tracing: %w", err)
 	}
 
 	return config.NewConfig(
 		apiPort,
+		grpcPort,
 		postgresConfig,
+		sqliteConfig,
+		dto.DB.CheckSchema,
+		eventBusDriver,
 		batchSizeLimit,
+		statsCacheTTL,
 		queues,
+		authConfig,
+		webhooks,
+		tracingConfig,
+	)
+}
+
+func mapRateLimit(dto *RateLimit) (opt.Val[*domain.RateLimit], error) {
+	none := opt.None[*domain.RateLimit]()
+
+	if dto == nil {
+		return none, nil
+	}
+
+	rateLimit, err := domain.NewRateLimit(
+		dto.Messages,
+		derefOrDefault(dto.Interval, config.DefaultRateLimitInterval),
+		derefOrDefault(dto.Burst, dto.Messages),
+	)
+	if err != nil {
+		return none, fmt.Errorf("domain.NewRateLimit: %w", err)
+	}
+
+	return opt.Some(rateLimit), nil
+}
+
+// mapPriorityAging caps aging at the top of the queue's priority range unless the cap is set explicitly.
+func mapPriorityAging(dto *PriorityAging, priorityRange *domain.PriorityRange) (opt.Val[*domain.PriorityAging], error) {
+	none := opt.None[*domain.PriorityAging]()
+
+	if dto == nil {
+		return none, nil
+	}
+
+	maxPriority, err := domain.NewPriority(derefOrDefault(dto.MaxPriority, priorityRange.Max().Int()))
+	if err != nil {
+		return none, fmt.Errorf("max_priority: %w", err)
+	}
+
+	aging, err := domain.NewPriorityAging(
+		dto.Interval,
+		derefOrDefault(dto.Step, config.DefaultAgingStep),
+		maxPriority,
+	)
+	if err != nil {
+		return none, fmt.Errorf("domain.NewPriorityAging: %w", err)
+	}
+
+	return opt.Some(aging), nil
+}
+
+func mapPriorityConfig(dto *PriorityConfig) (*domain.PriorityRange, domain.Priority, error) {
+	if dto == nil {
+		return domain.FullPriorityRange(), domain.UnsafePriority(config.DefaultPriority), nil
+	}
+
+	minPriority, err := domain.NewPriority(derefOrDefault(dto.Min, domain.MinPriority))
+	if err != nil {
+		return nil, domain.Priority{}, fmt.Errorf("priority.min: %w", err)
+	}
+
+	maxPriority, err := domain.NewPriority(derefOrDefault(dto.Max, domain.MaxPriority))
+	if err != nil {
+		return nil, domain.Priority{}, fmt.Errorf("priority.max: %w", err)
+	}
+
+	priorityRange, err := domain.NewPriorityRange(minPriority, maxPriority)
+	if err != nil {
+		return nil, domain.Priority{}, fmt.Errorf("domain.NewPriorityRange: %w", err)
+	}
+
+	// the global default is moved into the range if the range doesn't include it
+	defaultPriority, err := domain.NewPriority(derefOrDefault(
+		dto.Default,
+		min(max(config.DefaultPriority, minPriority.Int()), maxPriority.Int()),
+	))
+	if err != nil {
+		return nil, domain.Priority{}, fmt.Errorf("priority.default: %w", err)
+	}
+
+	return priorityRange, defaultPriority, nil
+}
+
+func mapWebhookConfig(dto *WebhookConfig, processingTimeout time.Duration) (*config.WebhookConfig, error) {
+	// by default a delivery must fit into the processing timeout, so the message isn't redelivered meanwhile
+	timeout := min(config.DefaultWebhookTimeout, processingTimeout)
+	if dto.Timeout != nil {
+		timeout = *dto.Timeout
+	}
+
+	conf, err := config.NewWebhookConfig(
+		dto.URL,
+		dto.Headers,
+		derefOrDefault(dto.Concurrency, config.DefaultWebhookConcurrency),
+		timeout,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("config.NewWebhookConfig: %w", err)
+	}
+
+	return conf, nil
+}
+
+func mapAuthConfig(dto *AuthConfig) (opt.Val[*config.AuthConfig], error) {
+	none := opt.None[*config.AuthConfig]()
+
+	if dto == nil {
+		return none, nil
+	}
+
+	apiKeys := make([]*auth.APIKey, 0, len(dto.APIKeys))
+	for _, keyConf := range dto.APIKeys {
+		grants := make(map[auth.Action][]string, len(keyConf.Permissions))
+		for action, patterns := range keyConf.Permissions {
+			grants[auth.Action(action)] = patterns
+		}
+
+		principal, err := auth.NewPrincipal(keyConf.Name, grants)
+		if err != nil {
+			return none, fmt.Errorf("auth.NewPrincipal: %w", err)
+		}
+
+		apiKey, err := auth.NewAPIKey(keyConf.Key, principal)
+		if err != nil {
+			return none, fmt.Errorf("api key %s: auth.NewAPIKey: %w", keyConf.Name, err)
+		}
+
+		apiKeys = append(apiKeys, apiKey)
+	}
+
+	conf, err := config.NewAuthConfig(apiKeys)
+	if err != nil {
+		return none, fmt.Errorf("config.NewAuthConfig: %w", err)
+	}
+
+	return opt.Some(conf), nil
+}
+
+func mapTracingConfig(dto *TracingConfig) (opt.Val[*config.TracingConfig], error) {
+	none := opt.None[*config.TracingConfig]()
+
+	if dto == nil {
+		return none, nil
+	}
+
+	conf, err := config.NewTracingConfig(
+		dto.Endpoint,
+		derefOrDefault(dto.Insecure, config.DefaultTracingInsecure),
+		derefOrDefault(dto.SampleRatio, config.DefaultTracingSampleRatio),
 	)
+	if err != nil {
+		return none, fmt.Errorf("config.NewTracingConfig: %w", err)
+	}
+
+	return opt.Some(conf), nil
 }
 
 func mapBackoffConfig(dto *BackoffConfig) (opt.Val[*domain.BackoffConfig], error) {
@@ -0,0 +1,98 @@
+package yamlconfig
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"server/internal/config"
+)
+
+const fileCheckInterval = 5 * time.Second
+
+// Reloader reloads queue configs from the config file on SIGHUP or when the file changes,
+// so that queues can be added without restarting instances. An invalid file is reported
+// and the current config is kept.
+type Reloader struct {
+	logger     *slog.Logger
+	configPath string
+	conf       *config.Config
+}
+
+func NewReloader(logger *slog.Logger, configPath string, conf *config.Config) *Reloader {
+	return &Reloader{
+		logger:     logger,
+		configPath: configPath,
+		conf:       conf,
+	}
+}
+
+func (r *Reloader) Run(ctx context.Context) error {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	defer signal.Stop(sighup)
+
+	modTime, err := r.modTime()
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(fileCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-sighup:
+			r.reloadWithLog("signal")
+		case <-ticker.C:
+			newModTime, err := r.modTime()
+			if err != nil {
+				r.logger.Error("config file check failed", "error", err)
+				continue
+			}
+
+			if !newModTime.Equal(modTime) {
+				modTime = newModTime
+				r.reloadWithLog("file change")
+			}
+		}
+	}
+}
+
+// Reload loads the config file and applies its queue configs.
+func (r *Reloader) Reload() error {
+	next, err := LoadFromFile(r.configPath)
+	if err != nil {
+		return fmt.Errorf("LoadFromFile: %w", err)
+	}
+
+	if err := r.conf.ReloadQueues(next); err != nil {
+		return fmt.Errorf("conf.ReloadQueues: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Reloader) reloadWithLog(trigger string) {
+	if err := r.Reload(); err != nil {
+		r.logger.Error("config reload failed", "trigger", trigger, "error", err)
+		return
+	}
+
+	r.logger.Info("config reloaded", "trigger", trigger, "queues", len(r.conf.QueueNames()))
+}
+
+func (r *Reloader) modTime() (time.Time, error) {
+	info, err := os.Stat(r.configPath)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("os.Stat: %w", err)
+	}
+
+	return info.ModTime(), nil
+}
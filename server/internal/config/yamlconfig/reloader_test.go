@@ -0,0 +1,124 @@
+package yamlconfig
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"server/internal/config"
+	"server/internal/domain"
+)
+
+const reloadBaseConfig = `
+db:
+  postgres:
+    host: 127.0.0.1:5432
+    db_name: queue
+    username: user
+    password:
+
+queues:
+`
+
+func writeConfig(t *testing.T, path string, queues string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(reloadBaseConfig+queues), 0o600))
+}
+
+func newTestReloader(t *testing.T, queues string) (*Reloader, *config.Config, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, queues)
+
+	conf, err := LoadFromFile(path)
+	require.NoError(t, err)
+
+	return NewReloader(slog.New(slog.DiscardHandler), path, conf), conf, path
+}
+
+func TestReloader_AddQueue(t *testing.T) {
+	reloader, conf, path := newTestReloader(t, `
+  queue1: { processing_timeout: 5m }
+`)
+
+	writeConfig(t, path, `
+  queue1: { processing_timeout: 10m }
+  queue2: { processing_timeout: 5m, dead_lettering: false }
+`)
+
+	require.NoError(t, reloader.Reload())
+
+	queue1, err := conf.GetQueueConfig(domain.UnsafeQueueName("queue1"))
+	require.NoError(t, err)
+	require.Equal(t, 10*time.Minute, queue1.ProcessingTimeout())
+
+	_, err = conf.GetQueueConfig(domain.UnsafeQueueName("queue2"))
+	require.NoError(t, err)
+
+	require.Len(t, conf.QueueNames(), 3) // queue1, its DLQ and queue2
+}
+
+func TestReloader_OrphanedDLQRejected(t *testing.T) {
+	reloader, conf, path := newTestReloader(t, `
+  queue1: { processing_timeout: 5m }
+`)
+
+	writeConfig(t, path, `
+  queue1: { processing_timeout: 5m, dead_lettering: false }
+`)
+
+	require.ErrorContains(t, reloader.Reload(), `dlq "queue1:dl" would be orphaned`)
+
+	// the current config is kept
+	_, err := conf.GetQueueConfig(domain.UnsafeQueueName("queue1:dl"))
+	require.NoError(t, err)
+}
+
+func TestReloader_RemoveQueueWithDLQRejected(t *testing.T) {
+	reloader, conf, path := newTestReloader(t, `
+  queue1: { processing_timeout: 5m }
+  queue2: { processing_timeout: 5m }
+`)
+
+	writeConfig(t, path, `
+  queue2: { processing_timeout: 5m }
+`)
+
+	require.ErrorContains(t, reloader.Reload(), `dlq "queue1:dl" would be orphaned`)
+
+	// the current config is kept
+	_, err := conf.GetQueueConfig(domain.UnsafeQueueName("queue1"))
+	require.NoError(t, err)
+}
+
+func TestReloader_RemoveQueueWithoutDLQ(t *testing.T) {
+	reloader, conf, path := newTestReloader(t, `
+  queue1: { processing_timeout: 5m, dead_lettering: false }
+  queue2: { processing_timeout: 5m }
+`)
+
+	writeConfig(t, path, `
+  queue2: { processing_timeout: 5m }
+`)
+
+	require.NoError(t, reloader.Reload())
+
+	_, err := conf.GetQueueConfig(domain.UnsafeQueueName("queue1"))
+	require.Error(t, err)
+}
+
+func TestReloader_InvalidFileRejected(t *testing.T) {
+	reloader, conf, path := newTestReloader(t, `
+  queue1: { processing_timeout: 5m }
+`)
+
+	require.NoError(t, os.WriteFile(path, []byte("queues: ["), 0o600))
+
+	require.Error(t, reloader.Reload())
+	require.Len(t, conf.QueueNames(), 2)
+}
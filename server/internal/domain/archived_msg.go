@@ -12,6 +12,7 @@ type ArchivedMsg struct {
 	id          uuid.UUID
 	queue       QueueName
 	payload     string
+	headers     Headers
 	createdAt   time.Time
 	finalizedAt time.Time
 	status      MessageStatus
@@ -59,10 +60,11 @@ func NewArchivedMsg(msg *Message) (*ArchivedMsg, error) {
 		id:          msg.ID(),
 		queue:       msg.Queue(),
 		payload:     msg.Payload(),
+		headers:     msg.Headers(),
 		createdAt:   msg.CreatedAt(),
 		finalizedAt: *finalizedAt,
 		status:      msg.Status(),
-		priority:    msg.Priority(),
+		priority:    msg.Priority().Int(),
 		retries:     msg.Retries(),
 		generation:  msg.Generation(),
 		history:     archChapters,
@@ -72,6 +74,7 @@ func NewArchivedMsg(msg *Message) (*ArchivedMsg, error) {
 func (m *ArchivedMsg) ID() uuid.UUID               { return m.id }
 func (m *ArchivedMsg) Queue() QueueName            { return m.queue }
 func (m *ArchivedMsg) Payload() string             { return m.payload }
+func (m *ArchivedMsg) Headers() Headers            { return m.headers }
 func (m *ArchivedMsg) CreatedAt() time.Time        { return m.createdAt }
 func (m *ArchivedMsg) FinalizedAt() time.Time      { return m.finalizedAt }
 func (m *ArchivedMsg) Status() MessageStatus       { return m.status }
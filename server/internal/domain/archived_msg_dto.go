@@ -11,6 +11,7 @@ type ArchivedMsgDTO struct {
 	ID          uuid.UUID
 	Queue       string
 	Payload     string
+	Headers     map[string]string
 	CreatedAt   time.Time
 	FinalizedAt time.Time
 	Status      MessageStatus
@@ -46,6 +47,7 @@ func ArchivedMsgFromDTO(dto *ArchivedMsgDTO) *ArchivedMsg {
 		id:          dto.ID,
 		queue:       UnsafeQueueName(dto.Queue),
 		payload:     dto.Payload,
+		headers:     UnsafeHeaders(dto.Headers),
 		createdAt:   dto.CreatedAt,
 		finalizedAt: dto.FinalizedAt,
 		status:      dto.Status,
@@ -71,6 +73,7 @@ func (m *ArchivedMsg) ToDTO() *ArchivedMsgDTO {
 		ID:          m.id,
 		Queue:       m.queue.String(),
 		Payload:     m.payload,
+		Headers:     m.headers.Map(),
 		CreatedAt:   m.createdAt,
 		FinalizedAt: m.finalizedAt,
 		Status:      m.status,
@@ -0,0 +1,29 @@
+package domain
+
+import "errors"
+
+// DedupKey identifies a publication of a message within a queue,
+// producers reuse it on retries to avoid duplicates.
+type DedupKey struct {
+	v string
+}
+
+func NewDedupKey(key string) (DedupKey, error) {
+	if key == "" {
+		return DedupKey{}, errors.New("dedup key must not be empty")
+	}
+
+	if len(key) > 255 {
+		return DedupKey{}, errors.New("dedup key too long")
+	}
+
+	return DedupKey{v: key}, nil
+}
+
+func UnsafeDedupKey(key string) DedupKey {
+	return DedupKey{v: key}
+}
+
+func (k DedupKey) String() string {
+	return k.v
+}
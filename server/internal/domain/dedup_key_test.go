@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDedupKey(t *testing.T) {
+	key, err := NewDedupKey("order-42:created")
+	require.NoError(t, err)
+	require.Equal(t, "order-42:created", key.String())
+
+	_, err = NewDedupKey("")
+	require.Error(t, err)
+
+	_, err = NewDedupKey(strings.Repeat("k", 256))
+	require.Error(t, err)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/google/uuid"
 
 	"server/internal/utils"
+	"server/internal/utils/opt"
 	"server/internal/utils/timeutils"
 )
 
@@ -22,20 +23,43 @@ const (
 	MsgStatusDropped    MessageStatus = "DROPPED"
 )
 
+func AllMessageStatuses() []MessageStatus {
+	return []MessageStatus{
+		MsgStatusPrepared,
+		MsgStatusAvailable,
+		MsgStatusProcessing,
+		MsgStatusDelayed,
+		MsgStatusDelivered,
+		MsgStatusDropped,
+	}
+}
+
+var ErrAttemptMismatch = errors.New("attempt id doesn't match the current processing attempt")
+
+// ErrMaxProcessingTimeReached is returned on extending a lease that can't last any longer.
+var ErrMaxProcessingTimeReached = errors.New("processing can't be extended beyond max processing time")
+
 type Message struct {
 	id              uuid.UUID
 	queue           QueueName
 	payload         string
+	headers         Headers
+	group           *MessageGroup
 	createdAt       time.Time
 	finalizedAt     *time.Time
 	status          MessageStatus
 	statusChangedAt time.Time
 	delayedUntil    *time.Time
 	timeoutAt       *time.Time
-	priority        int
+	attemptID       *uuid.UUID
+	priority        Priority
+	agedAt          *time.Time // last time the priority was raised by aging
 	retries         int
 	generation      int
 	history         *MessageHistory
+	traceParent     *string // W3C traceparent of the publisher's span
+	dedupKey        *DedupKey
+	dedupUntil      *time.Time
 
 	version int  // for optimistic locking
 	isNew   bool // to distinguish between insert and update
@@ -46,8 +70,11 @@ func NewMessage(
 	id uuid.UUID,
 	queue QueueName,
 	payload string,
-	priority int,
+	headers Headers,
+	group *MessageGroup,
+	priority Priority,
 	startAt *time.Time,
+	traceParent *string,
 ) (*Message, error) {
 	if startAt != nil && startAt.Before(clock.Now()) {
 		return nil, errors.New("start time must be in the future")
@@ -56,16 +83,21 @@ func NewMessage(
 		id:              id,
 		queue:           queue,
 		payload:         payload,
+		headers:         headers,
+		group:           group,
 		createdAt:       clock.Now(),
 		finalizedAt:     nil,
 		status:          MsgStatusPrepared,
 		statusChangedAt: clock.Now(),
 		delayedUntil:    startAt,
 		timeoutAt:       nil,
+		attemptID:       nil,
 		priority:        priority,
+		agedAt:          nil,
 		retries:         0,
 		generation:      0,
 		history:         newMessageHistory(true),
+		traceParent:     traceParent,
 		version:         0,
 		isNew:           true,
 	}, nil
@@ -74,13 +106,42 @@ func NewMessage(
 func (m *Message) ID() uuid.UUID            { return m.id }
 func (m *Message) Queue() QueueName         { return m.queue }
 func (m *Message) Payload() string          { return m.payload }
+func (m *Message) Headers() Headers         { return m.headers }
 func (m *Message) CreatedAt() time.Time     { return m.createdAt }
 func (m *Message) Status() MessageStatus    { return m.status }
-func (m *Message) Priority() int            { return m.priority }
+func (m *Message) Priority() Priority       { return m.priority }
 func (m *Message) Retries() int             { return m.retries }
 func (m *Message) Generation() int          { return m.generation }
 func (m *Message) History() *MessageHistory { return m.history }
 
+func (m *Message) AttemptID() *uuid.UUID {
+	if m.attemptID == nil {
+		return nil
+	}
+	return utils.P(*m.attemptID)
+}
+
+func (m *Message) TimeoutAt() *time.Time {
+	if m.timeoutAt == nil {
+		return nil
+	}
+	return utils.P(*m.timeoutAt)
+}
+
+func (m *Message) Group() *MessageGroup {
+	if m.group == nil {
+		return nil
+	}
+	return utils.P(*m.group)
+}
+
+func (m *Message) TraceParent() *string {
+	if m.traceParent == nil {
+		return nil
+	}
+	return utils.P(*m.traceParent)
+}
+
 func (m *Message) FinalizedAt() *time.Time {
 	if m.finalizedAt == nil {
 		return nil
@@ -88,6 +149,19 @@ func (m *Message) FinalizedAt() *time.Time {
 	return utils.P(*m.finalizedAt)
 }
 
+// Deduplicate makes publishing of a new message idempotent: until the window expires,
+// publishing another message with the same key to the queue resolves to this message.
+func (m *Message) Deduplicate(clock timeutils.Clock, key DedupKey, window time.Duration) error {
+	if !m.isNew {
+		return errors.New("only a new message can be deduplicated")
+	}
+
+	m.dedupKey = &key
+	m.dedupUntil = utils.P(clock.Now().Add(window))
+
+	return nil
+}
+
 func (m *Message) Release(clock timeutils.Clock, ed EventDispatcher) error {
 	if m.status != MsgStatusPrepared {
 		return errors.New("message must be in PREPARED status")
@@ -103,23 +177,103 @@ func (m *Message) Release(clock timeutils.Clock, ed EventDispatcher) error {
 	return nil
 }
 
+// ProcessingStart is what changes in a message when its processing starts.
+// Starts are computed before the messages are picked, so storage can take
+// available messages and start them in a single statement.
+type ProcessingStart struct {
+	Status    MessageStatus
+	StartedAt time.Time
+	TimeoutAt time.Time
+	AttemptID uuid.UUID
+}
+
+// NewProcessingStarts returns starts for up to `count` messages taken at once, each with its own attempt.
+func NewProcessingStarts(clock timeutils.Clock, timeout time.Duration, count int) []*ProcessingStart {
+	now := clock.Now()
+
+	starts := make([]*ProcessingStart, count)
+	for i := range starts {
+		starts[i] = &ProcessingStart{
+			Status:    MsgStatusProcessing,
+			StartedAt: now,
+			TimeoutAt: now.Add(timeout),
+			AttemptID: uuid.New(),
+		}
+	}
+
+	return starts
+}
+
 func (m *Message) StartProcessing(clock timeutils.Clock, timeout time.Duration) error {
 	if m.status != MsgStatusAvailable {
 		return errors.New("message must be in AVAILABLE status")
 	}
 
-	m.setStatus(clock, MsgStatusProcessing)
-	m.timeoutAt = utils.P(clock.Now().Add(timeout))
+	start := NewProcessingStarts(clock, timeout, 1)[0]
+
+	m.status = start.Status
+	m.statusChangedAt = start.StartedAt
+	m.timeoutAt = utils.P(start.TimeoutAt)
+	m.attemptID = utils.P(start.AttemptID)
+
+	return nil
+}
+
+// ExtendProcessing pushes the processing timeout forward by the given duration.
+// If maxProcessingTime is set, the timeout is capped to that much time since processing started,
+// and ErrMaxProcessingTimeReached is returned once the lease already ends at the cap.
+func (m *Message) ExtendProcessing(
+	clock timeutils.Clock,
+	duration time.Duration,
+	maxProcessingTime opt.Val[time.Duration],
+) error {
+	if m.status != MsgStatusProcessing {
+		return errors.New("message must be in PROCESSING status")
+	}
+
+	if duration <= 0 {
+		return errors.New("extension duration must be positive")
+	}
+
+	timeoutAt := clock.Now().Add(duration)
+
+	if maxTime, isSet := maxProcessingTime.Value(); isSet {
+		deadline := m.statusChangedAt.Add(maxTime)
+		if timeoutAt.After(deadline) {
+			// the consumer must not believe the lease was extended when it wasn't
+			if m.timeoutAt != nil && !deadline.After(*m.timeoutAt) {
+				return ErrMaxProcessingTimeReached
+			}
+			timeoutAt = deadline
+		}
+	}
+
+	// never shorten the lease that was already granted
+	if m.timeoutAt != nil && timeoutAt.Before(*m.timeoutAt) {
+		return nil
+	}
+
+	m.timeoutAt = utils.P(timeoutAt)
 
 	return nil
 }
 
+// CheckAttempt ensures that the caller holds the current processing attempt,
+// so a consumer whose lease has already expired can't finalize a redelivered copy.
+func (m *Message) CheckAttempt(attemptID uuid.UUID) error {
+	if m.status != MsgStatusProcessing || m.attemptID == nil || *m.attemptID != attemptID {
+		return ErrAttemptMismatch
+	}
+	return nil
+}
+
 func (m *Message) delay(clock timeutils.Clock, delayedUntil time.Time) error {
 	if m.status != MsgStatusProcessing {
 		return errors.New("message must be in PROCESSING status")
 	}
 
 	m.timeoutAt = nil // cleanup after PROCESSING status
+	m.attemptID = nil
 	m.retries++
 
 	m.setStatus(clock, MsgStatusDelayed)
@@ -148,10 +302,13 @@ func (m *Message) Resume(clock timeutils.Clock, ed EventDispatcher) error {
 	return nil
 }
 
+// Redirect moves the message to another queue. The message keeps its priority as long as
+// the destination allows it, otherwise it gets the closest priority the destination allows.
 func (m *Message) Redirect(
 	clock timeutils.Clock,
 	ed EventDispatcher,
 	destination QueueName,
+	destinationRange *PriorityRange,
 ) error {
 	if m.queue == destination {
 		return errors.New("redirecting to the same queue is not allowed")
@@ -164,8 +321,40 @@ func (m *Message) Redirect(
 	m.history.addChapter(newChapterFromMessage(clock, m))
 
 	m.timeoutAt = nil // cleanup after PROCESSING status
+	m.attemptID = nil
 
 	m.queue = destination
+	m.priority = destinationRange.Clamp(m.priority)
+	m.retries = 0
+	m.generation++
+
+	m.setStatus(clock, MsgStatusAvailable)
+	ed.Dispatch(NewMsgAvailableEvent(m.queue))
+
+	return nil
+}
+
+// Redrive moves a dead-lettered message back to the queue it was dead-lettered from
+// to give it a fresh set of retries.
+func (m *Message) Redrive(clock timeutils.Clock, ed EventDispatcher) error {
+	if !m.queue.IsDLQ() {
+		return errors.New("only messages in DLQ can be redriven")
+	}
+
+	if m.status != MsgStatusAvailable && m.status != MsgStatusDelayed {
+		return errors.New("message must be in AVAILABLE or DELAYED status")
+	}
+
+	source, err := m.SourceQueue()
+	if err != nil {
+		return err
+	}
+
+	m.history.addChapter(newChapterFromMessage(clock, m))
+
+	m.delayedUntil = nil // cleanup after DELAYED status
+
+	m.queue = source
 	m.retries = 0
 	m.generation++
 
@@ -175,12 +364,27 @@ func (m *Message) Redirect(
 	return nil
 }
 
+// SourceQueue returns the queue the message was in before it was redirected to the current one.
+func (m *Message) SourceQueue() (QueueName, error) {
+	chapters, loaded := m.history.Chapters()
+	if !loaded {
+		return QueueName{}, errors.New("message history not loaded")
+	}
+
+	if len(chapters) == 0 {
+		return QueueName{}, errors.New("message has never been redirected")
+	}
+
+	return chapters[len(chapters)-1].Queue(), nil
+}
+
 func (m *Message) MarkDelivered(clock timeutils.Clock) error {
 	if m.status != MsgStatusProcessing {
 		return errors.New("message must be in PROCESSING status")
 	}
 
 	m.timeoutAt = nil // cleanup after PROCESSING status
+	m.attemptID = nil
 
 	m.setStatus(clock, MsgStatusDelivered)
 	m.finalizedAt = utils.P(clock.Now())
@@ -194,6 +398,24 @@ func (m *Message) markDropped(clock timeutils.Clock) error {
 	}
 
 	m.timeoutAt = nil // cleanup after PROCESSING status
+	m.attemptID = nil
+
+	m.setStatus(clock, MsgStatusDropped)
+	m.finalizedAt = utils.P(clock.Now())
+
+	return nil
+}
+
+// Purge drops the message in any not finalized status, so that it can be archived as DROPPED.
+// A consumer still processing the message fails to ack it afterwards.
+func (m *Message) Purge(clock timeutils.Clock) error {
+	if m.finalizedAt != nil {
+		return errors.New("message is already finalized")
+	}
+
+	m.delayedUntil = nil // cleanup after DELAYED status
+	m.timeoutAt = nil    // cleanup after PROCESSING status
+	m.attemptID = nil
 
 	m.setStatus(clock, MsgStatusDropped)
 	m.finalizedAt = utils.P(clock.Now())
@@ -201,6 +423,33 @@ func (m *Message) markDropped(clock timeutils.Clock) error {
 	return nil
 }
 
+// Age raises the priority of a waiting message by one aging step for every full interval passed
+// since it became available or was aged last time. The raised priority is kept on redelivery.
+func (m *Message) Age(clock timeutils.Clock, aging *PriorityAging) error {
+	if m.status != MsgStatusAvailable {
+		return errors.New("message must be in AVAILABLE status")
+	}
+
+	if m.priority.Int() >= aging.MaxPriority().Int() {
+		return nil
+	}
+
+	since := m.statusChangedAt
+	if m.agedAt != nil && m.agedAt.After(since) {
+		since = *m.agedAt
+	}
+
+	steps := int(clock.Now().Sub(since) / aging.Interval())
+	if steps <= 0 {
+		return nil
+	}
+
+	m.priority = UnsafePriority(min(aging.MaxPriority().Int(), m.priority.Int()+steps*aging.Step()))
+	m.agedAt = utils.P(since.Add(time.Duration(steps) * aging.Interval()))
+
+	return nil
+}
+
 func (m *Message) setStatus(clock timeutils.Clock, newStatus MessageStatus) {
 	m.status = newStatus
 	m.statusChangedAt = clock.Now()
@@ -227,7 +476,7 @@ func (m *Message) Nack(clock timeutils.Clock, ed EventDispatcher, nackPolicy *Na
 			return fmt.Errorf("queue.DLQName: %w", err)
 		}
 
-		if err := m.Redirect(clock, ed, dlQueue); err != nil {
+		if err := m.Redirect(clock, ed, dlQueue, action.DLQPriorityRange); err != nil {
 			return fmt.Errorf("msg.Redirect: %w", err)
 		}
 	}
@@ -11,16 +11,23 @@ type MessageDTO struct {
 	ID              uuid.UUID
 	Queue           string
 	Payload         string
+	Headers         map[string]string
+	Group           *string
 	CreatedAt       time.Time
 	FinalizedAt     *time.Time
 	Status          MessageStatus
 	StatusChangedAt time.Time
 	DelayedUntil    *time.Time
 	TimeoutAt       *time.Time
+	AttemptID       *uuid.UUID
 	Priority        int
+	AgedAt          *time.Time
 	Retries         int
 	Generation      int
 	History         []*MessageChapterDTO
+	TraceParent     *string
+	DedupKey        *string    // written on create only, never loaded
+	DedupUntil      *time.Time // written on create only, never loaded
 	Version         int
 	IsNew           bool
 }
@@ -30,16 +37,21 @@ func FromDTO(dto *MessageDTO) *Message {
 		id:              dto.ID,
 		queue:           UnsafeQueueName(dto.Queue),
 		payload:         dto.Payload,
+		headers:         UnsafeHeaders(dto.Headers),
+		group:           groupFromDTO(dto.Group),
 		createdAt:       dto.CreatedAt,
 		finalizedAt:     dto.FinalizedAt,
 		status:          dto.Status,
 		statusChangedAt: dto.StatusChangedAt,
 		delayedUntil:    dto.DelayedUntil,
 		timeoutAt:       dto.TimeoutAt,
-		priority:        dto.Priority,
+		attemptID:       dto.AttemptID,
+		priority:        UnsafePriority(dto.Priority),
+		agedAt:          dto.AgedAt,
 		retries:         dto.Retries,
 		generation:      dto.Generation,
 		history:         historyFromDTO(dto.History),
+		traceParent:     dto.TraceParent,
 		version:         dto.Version,
 		isNew:           dto.IsNew,
 	}
@@ -50,17 +62,45 @@ func (m *Message) ToDTO() *MessageDTO {
 		ID:              m.id,
 		Queue:           m.queue.String(),
 		Payload:         m.payload,
+		Headers:         m.headers.Map(),
+		Group:           groupToDTO(m.group),
 		CreatedAt:       m.createdAt,
 		FinalizedAt:     m.finalizedAt,
 		Status:          m.status,
 		StatusChangedAt: m.statusChangedAt,
 		DelayedUntil:    m.delayedUntil,
 		TimeoutAt:       m.timeoutAt,
-		Priority:        m.priority,
+		AttemptID:       m.attemptID,
+		Priority:        m.priority.Int(),
+		AgedAt:          m.agedAt,
 		Retries:         m.retries,
 		Generation:      m.generation,
 		History:         m.history.toDTO(),
+		TraceParent:     m.traceParent,
+		DedupKey:        dedupKeyToDTO(m.dedupKey),
+		DedupUntil:      m.dedupUntil,
 		Version:         m.version,
 		IsNew:           m.isNew,
 	}
 }
+
+func dedupKeyToDTO(key *DedupKey) *string {
+	if key == nil {
+		return nil
+	}
+	return &key.v
+}
+
+func groupFromDTO(group *string) *MessageGroup {
+	if group == nil {
+		return nil
+	}
+	return &MessageGroup{v: *group}
+}
+
+func groupToDTO(group *MessageGroup) *string {
+	if group == nil {
+		return nil
+	}
+	return &group.v
+}
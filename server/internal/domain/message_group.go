@@ -0,0 +1,29 @@
+package domain
+
+import "errors"
+
+// MessageGroup keeps messages of one entity in order: messages of a group are consumed
+// one at a time in the order they were published, while other groups proceed in parallel.
+type MessageGroup struct {
+	v string
+}
+
+func NewMessageGroup(group string) (MessageGroup, error) {
+	if group == "" {
+		return MessageGroup{}, errors.New("message group must not be empty")
+	}
+
+	if len(group) > 255 {
+		return MessageGroup{}, errors.New("message group too long")
+	}
+
+	return MessageGroup{v: group}, nil
+}
+
+func UnsafeMessageGroup(group string) MessageGroup {
+	return MessageGroup{v: group}
+}
+
+func (g MessageGroup) String() string {
+	return g.v
+}
@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMessageGroup(t *testing.T) {
+	group, err := NewMessageGroup("customer-42")
+	require.NoError(t, err)
+	require.Equal(t, "customer-42", group.String())
+
+	_, err = NewMessageGroup("")
+	require.Error(t, err)
+
+	_, err = NewMessageGroup(strings.Repeat("g", 256))
+	require.Error(t, err)
+}
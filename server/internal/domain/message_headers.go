@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+)
+
+const (
+	maxHeaders          = 64
+	maxHeaderNameLength = 255
+	maxHeadersSize      = 64 * 1024 // names and values together
+)
+
+// Headers carry metadata of a message (content type, routing info etc.) separately from its payload.
+type Headers struct {
+	m map[string]string
+}
+
+func NewHeaders(headers map[string]string) (Headers, error) {
+	if len(headers) > maxHeaders {
+		return Headers{}, fmt.Errorf("message can't have more than %d headers", maxHeaders)
+	}
+
+	size := 0
+	for name, value := range headers {
+		if name == "" {
+			return Headers{}, errors.New("header name must not be empty")
+		}
+		if len(name) > maxHeaderNameLength {
+			return Headers{}, fmt.Errorf("header name must not be longer than %d bytes", maxHeaderNameLength)
+		}
+		size += len(name) + len(value)
+	}
+
+	if size > maxHeadersSize {
+		return Headers{}, fmt.Errorf("headers must not be larger than %d bytes in total", maxHeadersSize)
+	}
+
+	return Headers{m: maps.Clone(headers)}, nil
+}
+
+func UnsafeHeaders(headers map[string]string) Headers {
+	return Headers{m: maps.Clone(headers)}
+}
+
+func (h Headers) Len() int {
+	return len(h.m)
+}
+
+// Map returns a copy of the headers, never nil.
+func (h Headers) Map() map[string]string {
+	if h.m == nil {
+		return map[string]string{}
+	}
+	return maps.Clone(h.m)
+}
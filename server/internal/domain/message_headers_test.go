@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHeaders(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		source := map[string]string{"content-type": "application/json", "empty": ""}
+
+		headers, err := NewHeaders(source)
+		require.NoError(t, err)
+		require.Equal(t, source, headers.Map())
+
+		// headers are immutable
+		source["content-type"] = "text/plain"
+		headers.Map()["content-type"] = "text/plain"
+		require.Equal(t, "application/json", headers.Map()["content-type"])
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		headers, err := NewHeaders(nil)
+		require.NoError(t, err)
+		require.Equal(t, 0, headers.Len())
+		require.NotNil(t, headers.Map())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		tooMany := map[string]string{}
+		for i := range maxHeaders + 1 {
+			tooMany[strings.Repeat("h", i+1)] = ""
+		}
+
+		for _, invalid := range []map[string]string{
+			{"": "value"},
+			{strings.Repeat("h", maxHeaderNameLength+1): "value"},
+			{"big": strings.Repeat("v", maxHeadersSize)},
+			tooMany,
+		} {
+			_, err := NewHeaders(invalid)
+			require.Error(t, err)
+		}
+	})
+}
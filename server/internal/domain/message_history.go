@@ -48,7 +48,7 @@ func newChapterFromMessage(clock timeutils.Clock, msg *Message) *MessageChapter
 		generation:   msg.generation,
 		queue:        msg.queue,
 		redirectedAt: clock.Now(),
-		priority:     msg.priority,
+		priority:     msg.priority.Int(),
 		retries:      msg.retries,
 		isNew:        true,
 	}
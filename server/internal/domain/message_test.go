@@ -0,0 +1,328 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"server/internal/utils/opt"
+	"server/internal/utils/timeutils"
+)
+
+func newAvailableMessage(t *testing.T, clock timeutils.Clock) *Message {
+	t.Helper()
+
+	msg, err := NewMessage(clock, uuid.New(), UnsafeQueueName("test"), "payload", Headers{}, nil, UnsafePriority(100), nil, nil)
+	require.NoError(t, err)
+
+	msg.setStatus(clock, MsgStatusAvailable)
+
+	return msg
+}
+
+func TestMessage_CheckAttempt(t *testing.T) {
+	clock := timeutils.NewStubClock(time.Date(2025, 6, 12, 12, 0, 0, 0, time.Local))
+
+	t.Run("CurrentAttempt", func(t *testing.T) {
+		msg := newAvailableMessage(t, clock)
+		require.NoError(t, msg.StartProcessing(clock, time.Minute))
+
+		require.NotNil(t, msg.AttemptID())
+		require.NoError(t, msg.CheckAttempt(*msg.AttemptID()))
+	})
+
+	t.Run("UnknownAttempt", func(t *testing.T) {
+		msg := newAvailableMessage(t, clock)
+		require.NoError(t, msg.StartProcessing(clock, time.Minute))
+
+		require.ErrorIs(t, msg.CheckAttempt(uuid.New()), ErrAttemptMismatch)
+	})
+
+	t.Run("NotProcessing", func(t *testing.T) {
+		msg := newAvailableMessage(t, clock)
+
+		require.Nil(t, msg.AttemptID())
+		require.ErrorIs(t, msg.CheckAttempt(uuid.New()), ErrAttemptMismatch)
+	})
+
+	t.Run("AttemptClearedAfterProcessing", func(t *testing.T) {
+		msg := newAvailableMessage(t, clock)
+		require.NoError(t, msg.StartProcessing(clock, time.Minute))
+		attemptID := *msg.AttemptID()
+
+		require.NoError(t, msg.MarkDelivered(clock))
+
+		require.Nil(t, msg.AttemptID())
+		require.ErrorIs(t, msg.CheckAttempt(attemptID), ErrAttemptMismatch)
+	})
+}
+
+func TestNewProcessingStarts(t *testing.T) {
+	clock := timeutils.NewStubClock(time.Date(2025, 6, 12, 12, 0, 0, 0, time.Local))
+
+	starts := NewProcessingStarts(clock, time.Minute, 2)
+
+	require.Len(t, starts, 2)
+	for _, start := range starts {
+		require.Equal(t, MsgStatusProcessing, start.Status)
+		require.Equal(t, clock.Now(), start.StartedAt)
+		require.Equal(t, clock.Now().Add(time.Minute), start.TimeoutAt)
+	}
+	require.NotEqual(t, starts[0].AttemptID, starts[1].AttemptID)
+}
+
+func TestMessage_ExtendProcessing(t *testing.T) {
+	clock := timeutils.NewStubClock(time.Date(2025, 6, 12, 12, 0, 0, 0, time.Local))
+	startedAt := clock.Now()
+
+	t.Run("Uncapped", func(t *testing.T) {
+		clock.Set(startedAt)
+		msg := newAvailableMessage(t, clock)
+		require.NoError(t, msg.StartProcessing(clock, time.Minute))
+
+		clock.Set(startedAt.Add(30 * time.Second))
+		require.NoError(t, msg.ExtendProcessing(clock, time.Hour, opt.None[time.Duration]()))
+
+		require.Equal(t, clock.Now().Add(time.Hour), *msg.TimeoutAt())
+	})
+
+	t.Run("CappedByMaxProcessingTime", func(t *testing.T) {
+		clock.Set(startedAt)
+		msg := newAvailableMessage(t, clock)
+		require.NoError(t, msg.StartProcessing(clock, time.Minute))
+
+		clock.Set(startedAt.Add(30 * time.Second))
+		require.NoError(t, msg.ExtendProcessing(clock, time.Hour, opt.Some(10*time.Minute)))
+
+		require.Equal(t, startedAt.Add(10*time.Minute), *msg.TimeoutAt())
+	})
+
+	t.Run("MaxProcessingTimeReached", func(t *testing.T) {
+		clock.Set(startedAt)
+		msg := newAvailableMessage(t, clock)
+		require.NoError(t, msg.StartProcessing(clock, time.Minute))
+
+		clock.Set(startedAt.Add(30 * time.Second))
+		require.NoError(t, msg.ExtendProcessing(clock, time.Hour, opt.Some(10*time.Minute)))
+
+		err := msg.ExtendProcessing(clock, time.Hour, opt.Some(10*time.Minute))
+
+		require.ErrorIs(t, err, ErrMaxProcessingTimeReached)
+		require.Equal(t, startedAt.Add(10*time.Minute), *msg.TimeoutAt())
+	})
+
+	t.Run("NeverShortens", func(t *testing.T) {
+		clock.Set(startedAt)
+		msg := newAvailableMessage(t, clock)
+		require.NoError(t, msg.StartProcessing(clock, time.Hour))
+
+		require.NoError(t, msg.ExtendProcessing(clock, time.Minute, opt.None[time.Duration]()))
+
+		require.Equal(t, startedAt.Add(time.Hour), *msg.TimeoutAt())
+	})
+
+	t.Run("NotProcessing", func(t *testing.T) {
+		clock.Set(startedAt)
+		msg := newAvailableMessage(t, clock)
+
+		require.Error(t, msg.ExtendProcessing(clock, time.Minute, opt.None[time.Duration]()))
+	})
+}
+
+func TestMessage_Age(t *testing.T) {
+	now := time.Date(2025, 6, 12, 12, 0, 0, 0, time.Local)
+
+	// +2 every 10 minutes, up to 110
+	aging, err := NewPriorityAging(10*time.Minute, 2, UnsafePriority(110))
+	require.NoError(t, err)
+
+	t.Run("NotWaitedLongEnough", func(t *testing.T) {
+		clock := timeutils.NewStubClock(now)
+		msg := newAvailableMessage(t, clock)
+
+		clock.Set(now.Add(9 * time.Minute))
+		require.NoError(t, msg.Age(clock, aging))
+
+		require.Equal(t, 100, msg.Priority().Int())
+	})
+
+	t.Run("StepPerInterval", func(t *testing.T) {
+		clock := timeutils.NewStubClock(now)
+		msg := newAvailableMessage(t, clock)
+
+		clock.Set(now.Add(25 * time.Minute))
+		require.NoError(t, msg.Age(clock, aging))
+		require.Equal(t, 104, msg.Priority().Int())
+
+		// the remainder of 5 minutes isn't lost
+		clock.Set(now.Add(30 * time.Minute))
+		require.NoError(t, msg.Age(clock, aging))
+		require.Equal(t, 106, msg.Priority().Int())
+	})
+
+	t.Run("CappedAtMaxPriority", func(t *testing.T) {
+		clock := timeutils.NewStubClock(now)
+		msg := newAvailableMessage(t, clock)
+
+		clock.Set(now.Add(24 * time.Hour))
+		require.NoError(t, msg.Age(clock, aging))
+
+		require.Equal(t, 110, msg.Priority().Int())
+	})
+
+	t.Run("NeverLowersPriority", func(t *testing.T) {
+		clock := timeutils.NewStubClock(now)
+		msg, err := NewMessage(clock, uuid.New(), UnsafeQueueName("test"), "payload", Headers{}, nil, UnsafePriority(200), nil, nil)
+		require.NoError(t, err)
+		msg.setStatus(clock, MsgStatusAvailable)
+
+		clock.Set(now.Add(time.Hour))
+		require.NoError(t, msg.Age(clock, aging))
+
+		require.Equal(t, 200, msg.Priority().Int())
+	})
+
+	t.Run("NotAvailable", func(t *testing.T) {
+		clock := timeutils.NewStubClock(now)
+		msg := newAvailableMessage(t, clock)
+		require.NoError(t, msg.StartProcessing(clock, time.Minute))
+
+		require.Error(t, msg.Age(clock, aging))
+	})
+}
+
+func TestMessage_Deduplicate(t *testing.T) {
+	now := time.Date(2025, 6, 12, 12, 0, 0, 0, time.Local)
+
+	t.Run("NewMessage", func(t *testing.T) {
+		clock := timeutils.NewStubClock(now)
+		msg, err := NewMessage(clock, uuid.New(), UnsafeQueueName("test"), "payload", Headers{}, nil, UnsafePriority(100), nil, nil)
+		require.NoError(t, err)
+
+		require.NoError(t, msg.Deduplicate(clock, UnsafeDedupKey("order-1"), 5*time.Minute))
+
+		dto := msg.ToDTO()
+		require.Equal(t, "order-1", *dto.DedupKey)
+		require.Equal(t, now.Add(5*time.Minute), *dto.DedupUntil)
+	})
+
+	t.Run("StoredMessage", func(t *testing.T) {
+		clock := timeutils.NewStubClock(now)
+		dto := newAvailableMessage(t, clock).ToDTO()
+		dto.IsNew = false
+		msg := FromDTO(dto)
+
+		require.Error(t, msg.Deduplicate(clock, UnsafeDedupKey("order-1"), 5*time.Minute))
+	})
+}
+
+type recordingDispatcher struct {
+	events []Event
+}
+
+func (d *recordingDispatcher) Dispatch(ev Event) {
+	d.events = append(d.events, ev)
+}
+
+func TestMessage_Redirect(t *testing.T) {
+	clock := timeutils.NewStubClock(time.Date(2025, 6, 12, 12, 0, 0, 0, time.Local))
+
+	newProcessingMessage := func(t *testing.T) *Message {
+		t.Helper()
+
+		msg := newAvailableMessage(t, clock)
+		require.NoError(t, msg.StartProcessing(clock, time.Minute))
+
+		return msg
+	}
+
+	t.Run("KeepsPriorityWithinDestinationRange", func(t *testing.T) {
+		msg := newProcessingMessage(t)
+
+		require.NoError(t, msg.Redirect(clock, &recordingDispatcher{}, UnsafeQueueName("other"), FullPriorityRange()))
+
+		require.Equal(t, "other", msg.Queue().String())
+		require.Equal(t, 100, msg.Priority().Int())
+	})
+
+	t.Run("ClampsPriorityToDestinationRange", func(t *testing.T) {
+		msg := newProcessingMessage(t)
+		destinationRange, err := NewPriorityRange(UnsafePriority(0), UnsafePriority(10))
+		require.NoError(t, err)
+
+		require.NoError(t, msg.Redirect(clock, &recordingDispatcher{}, UnsafeQueueName("other"), destinationRange))
+
+		require.Equal(t, 10, msg.Priority().Int())
+	})
+}
+
+func TestMessage_Redrive(t *testing.T) {
+	clock := timeutils.NewStubClock(time.Date(2025, 6, 12, 12, 0, 0, 0, time.Local))
+
+	newDeadLetter := func(t *testing.T) *Message {
+		t.Helper()
+
+		msg := newAvailableMessage(t, clock)
+		msg.retries = 5
+		require.NoError(t, msg.StartProcessing(clock, time.Minute))
+		require.NoError(t, msg.Redirect(clock, &recordingDispatcher{}, UnsafeQueueName("test:dl"), FullPriorityRange()))
+
+		return msg
+	}
+
+	t.Run("BackToSourceQueue", func(t *testing.T) {
+		msg := newDeadLetter(t)
+		ed := &recordingDispatcher{}
+
+		require.NoError(t, msg.Redrive(clock, ed))
+
+		require.Equal(t, "test", msg.Queue().String())
+		require.Equal(t, MsgStatusAvailable, msg.Status())
+		require.Equal(t, 0, msg.Retries())
+		require.Equal(t, 2, msg.Generation())
+		require.Len(t, ed.events, 1)
+
+		chapters, _ := msg.History().Chapters()
+		require.Len(t, chapters, 2)
+		require.Equal(t, "test:dl", chapters[1].Queue().String())
+	})
+
+	t.Run("NotInDLQ", func(t *testing.T) {
+		msg := newAvailableMessage(t, clock)
+
+		require.Error(t, msg.Redrive(clock, &recordingDispatcher{}))
+	})
+
+	t.Run("Processing", func(t *testing.T) {
+		msg := newDeadLetter(t)
+		require.NoError(t, msg.StartProcessing(clock, time.Minute))
+
+		require.Error(t, msg.Redrive(clock, &recordingDispatcher{}))
+	})
+}
+
+func TestMessage_Purge(t *testing.T) {
+	clock := timeutils.NewStubClock(time.Date(2025, 6, 12, 12, 0, 0, 0, time.Local))
+
+	t.Run("Processing", func(t *testing.T) {
+		msg := newAvailableMessage(t, clock)
+		require.NoError(t, msg.StartProcessing(clock, time.Minute))
+
+		require.NoError(t, msg.Purge(clock))
+
+		require.Equal(t, MsgStatusDropped, msg.Status())
+		require.Equal(t, clock.Now(), *msg.FinalizedAt())
+		require.Nil(t, msg.AttemptID())
+		require.Nil(t, msg.TimeoutAt())
+	})
+
+	t.Run("AlreadyFinalized", func(t *testing.T) {
+		msg := newAvailableMessage(t, clock)
+		require.NoError(t, msg.StartProcessing(clock, time.Minute))
+		require.NoError(t, msg.MarkDelivered(clock))
+
+		require.Error(t, msg.Purge(clock))
+	})
+}
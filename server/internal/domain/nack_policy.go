@@ -19,8 +19,9 @@ const (
 )
 
 type NackAction struct {
-	Type          NackActionKind
-	DelayDuration time.Duration // only valid for NackActionDelay
+	Type             NackActionKind
+	DelayDuration    time.Duration  // only valid for NackActionDelay
+	DLQPriorityRange *PriorityRange // only valid for NackActionDLQ
 }
 
 type NackPolicy struct {
@@ -44,7 +45,23 @@ func (eh *NackPolicy) Decide(msg *Message, redeliveryRequested bool) (*NackActio
 		return nil, err
 	}
 
-	return pureDecide(msg.Retries(), conf, redeliveryRequested), nil
+	action := pureDecide(msg.Retries(), conf, redeliveryRequested)
+
+	if action.Type == NackActionDLQ {
+		dlQueue, err := msg.Queue().DLQName()
+		if err != nil {
+			return nil, err
+		}
+
+		dlqConf, err := eh.configProvider.GetConfig(dlQueue)
+		if err != nil {
+			return nil, err
+		}
+
+		action.DLQPriorityRange = dlqConf.PriorityRange()
+	}
+
+	return action, nil
 }
 
 func pureDecide(msgRetries int, conf *QueueConfig, redeliveryRequested bool) *NackAction {
@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"server/internal/utils/opt"
+	"server/internal/utils/timeutils"
 )
 
 func Test_pureDecide_WithBackoff(t *testing.T) {
@@ -17,7 +18,7 @@ func Test_pureDecide_WithBackoff(t *testing.T) {
 	)
 	require.NoError(t, err)
 
-	conf, err := NewQueueConfig(opt.Some(bConf), time.Minute, false)
+	conf, err := NewQueueConfig(opt.Some(bConf), time.Minute, opt.None[time.Duration](), opt.None[time.Duration](), opt.None[*RateLimit](), opt.None[*PriorityAging](), FullPriorityRange(), UnsafePriority(100), 5*time.Minute, false)
 	require.NoError(t, err)
 
 	t.Run("NotExhaustedWithRedelivery", func(t *testing.T) {
@@ -43,7 +44,7 @@ func Test_pureDecide_WithBackoff(t *testing.T) {
 }
 
 func Test_pureDecide_WithoutBackoff(t *testing.T) {
-	conf, err := NewQueueConfig(opt.None[*BackoffConfig](), time.Minute, false)
+	conf, err := NewQueueConfig(opt.None[*BackoffConfig](), time.Minute, opt.None[time.Duration](), opt.None[time.Duration](), opt.None[*RateLimit](), opt.None[*PriorityAging](), FullPriorityRange(), UnsafePriority(100), 5*time.Minute, false)
 	require.NoError(t, err)
 
 	t.Run("WithRedelivery", func(t *testing.T) {
@@ -57,6 +58,34 @@ func Test_pureDecide_WithoutBackoff(t *testing.T) {
 	})
 }
 
+type stubConfigProvider map[string]*QueueConfig
+
+func (p stubConfigProvider) GetConfig(queue QueueName) (*QueueConfig, error) {
+	return p[queue.String()], nil
+}
+
+func TestNackPolicy_DecideDLQPriorityRange(t *testing.T) {
+	clock := timeutils.NewStubClock(time.Date(2025, 6, 12, 12, 0, 0, 0, time.Local))
+
+	conf, err := NewQueueConfig(opt.None[*BackoffConfig](), time.Minute, opt.None[time.Duration](), opt.None[time.Duration](), opt.None[*RateLimit](), opt.None[*PriorityAging](), FullPriorityRange(), UnsafePriority(100), 5*time.Minute, true)
+	require.NoError(t, err)
+
+	dlqRange, err := NewPriorityRange(UnsafePriority(0), UnsafePriority(10))
+	require.NoError(t, err)
+	dlqConf, err := NewQueueConfig(opt.None[*BackoffConfig](), time.Minute, opt.None[time.Duration](), opt.None[time.Duration](), opt.None[*RateLimit](), opt.None[*PriorityAging](), dlqRange, UnsafePriority(0), 5*time.Minute, false)
+	require.NoError(t, err)
+
+	policy := NewNackPolicy(clock, stubConfigProvider{"test": conf, "test:dl": dlqConf})
+
+	msg := newAvailableMessage(t, clock)
+	require.NoError(t, msg.StartProcessing(clock, time.Minute))
+
+	require.NoError(t, msg.Nack(clock, &recordingDispatcher{}, policy, false))
+
+	require.Equal(t, "test:dl", msg.Queue().String())
+	require.Equal(t, 10, msg.Priority().Int())
+}
+
 func Test_getDelayDuration(t *testing.T) {
 	shape := []time.Duration{
 		1 * time.Minute,
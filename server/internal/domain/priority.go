@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// bounds of the priority column
+const (
+	MinPriority = 0
+	MaxPriority = 255
+)
+
+var ErrPriorityOutOfRange = errors.New("priority out of range")
+
+// Priority defines the order in which available messages are consumed, higher goes first.
+type Priority struct {
+	v int
+}
+
+func NewPriority(priority int) (Priority, error) {
+	if priority < MinPriority || priority > MaxPriority {
+		return Priority{}, fmt.Errorf("%w: must be between %d and %d", ErrPriorityOutOfRange, MinPriority, MaxPriority)
+	}
+	return Priority{v: priority}, nil
+}
+
+func UnsafePriority(priority int) Priority {
+	return Priority{v: priority}
+}
+
+func (p Priority) Int() int {
+	return p.v
+}
+
+// PriorityRange limits priorities that can be assigned on publishing to a queue.
+type PriorityRange struct {
+	min Priority
+	max Priority
+}
+
+func NewPriorityRange(min Priority, max Priority) (*PriorityRange, error) {
+	if min.v > max.v {
+		return nil, errors.New("min priority must not be greater than max priority")
+	}
+	return &PriorityRange{min: min, max: max}, nil
+}
+
+// FullPriorityRange allows every priority that can be stored.
+func FullPriorityRange() *PriorityRange {
+	return &PriorityRange{min: Priority{v: MinPriority}, max: Priority{v: MaxPriority}}
+}
+
+func (r *PriorityRange) Min() Priority { return r.min }
+func (r *PriorityRange) Max() Priority { return r.max }
+
+func (r *PriorityRange) Contains(priority Priority) bool {
+	return priority.v >= r.min.v && priority.v <= r.max.v
+}
+
+// Clamp returns the closest allowed priority.
+func (r *PriorityRange) Clamp(priority Priority) Priority {
+	return Priority{v: min(max(priority.v, r.min.v), r.max.v)}
+}
+
+// Check returns ErrPriorityOutOfRange if the priority isn't allowed.
+func (r *PriorityRange) Check(priority Priority) error {
+	if !r.Contains(priority) {
+		return fmt.Errorf("%w: queue allows priorities between %d and %d", ErrPriorityOutOfRange, r.min.v, r.max.v)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPriority(t *testing.T) {
+	for _, valid := range []int{0, 100, 255} {
+		priority, err := NewPriority(valid)
+		require.NoError(t, err)
+		require.Equal(t, valid, priority.Int())
+	}
+
+	for _, invalid := range []int{-1, 256} {
+		_, err := NewPriority(invalid)
+		require.ErrorIs(t, err, ErrPriorityOutOfRange)
+	}
+}
+
+func TestPriorityRange(t *testing.T) {
+	priorityRange, err := NewPriorityRange(UnsafePriority(10), UnsafePriority(20))
+	require.NoError(t, err)
+
+	require.NoError(t, priorityRange.Check(UnsafePriority(10)))
+	require.NoError(t, priorityRange.Check(UnsafePriority(20)))
+	require.ErrorIs(t, priorityRange.Check(UnsafePriority(9)), ErrPriorityOutOfRange)
+	require.ErrorIs(t, priorityRange.Check(UnsafePriority(21)), ErrPriorityOutOfRange)
+
+	require.Equal(t, 10, priorityRange.Clamp(UnsafePriority(5)).Int())
+	require.Equal(t, 15, priorityRange.Clamp(UnsafePriority(15)).Int())
+	require.Equal(t, 20, priorityRange.Clamp(UnsafePriority(200)).Int())
+
+	_, err = NewPriorityRange(UnsafePriority(20), UnsafePriority(10))
+	require.Error(t, err)
+}
@@ -11,28 +11,142 @@ import (
 type QueueConfig struct {
 	backoff           opt.Val[*BackoffConfig]
 	processingTimeout time.Duration
+	maxProcessingTime opt.Val[time.Duration]
+	retention         opt.Val[time.Duration]
+	rateLimit         opt.Val[*RateLimit]
+	priorityAging     opt.Val[*PriorityAging]
+	priorityRange     *PriorityRange
+	defaultPriority   Priority
+	dedupWindow       time.Duration
 	deadLetteringOn   bool
 }
 
 func NewQueueConfig(
 	backoff opt.Val[*BackoffConfig],
 	processingTimeout time.Duration,
+	maxProcessingTime opt.Val[time.Duration],
+	retention opt.Val[time.Duration],
+	rateLimit opt.Val[*RateLimit],
+	priorityAging opt.Val[*PriorityAging],
+	priorityRange *PriorityRange,
+	defaultPriority Priority,
+	dedupWindow time.Duration,
 	deadLetteringOn bool,
 ) (*QueueConfig, error) {
 	if processingTimeout < time.Second {
 		return nil, errors.New("processing timeout must be at least 1 second")
 	}
 
+	if value, isSet := maxProcessingTime.Value(); isSet && value < processingTimeout {
+		return nil, errors.New("max processing time must not be less than processing timeout")
+	}
+
+	if value, isSet := retention.Value(); isSet && value <= 0 {
+		return nil, errors.New("retention must be greater than zero if provided")
+	}
+
+	if !priorityRange.Contains(defaultPriority) {
+		return nil, errors.New("default priority must be within the priority range")
+	}
+
+	// aged messages must stay within the range, like published ones
+	if aging, isSet := priorityAging.Value(); isSet && aging.MaxPriority().Int() > priorityRange.Max().Int() {
+		return nil, errors.New("priority aging max priority must not exceed the priority range")
+	}
+
+	if dedupWindow < time.Second {
+		return nil, errors.New("dedup window must be at least 1 second")
+	}
+
 	return &QueueConfig{
 		backoff:           backoff,
 		processingTimeout: processingTimeout,
+		maxProcessingTime: maxProcessingTime,
+		retention:         retention,
+		rateLimit:         rateLimit,
+		priorityAging:     priorityAging,
+		priorityRange:     priorityRange,
+		defaultPriority:   defaultPriority,
+		dedupWindow:       dedupWindow,
 		deadLetteringOn:   deadLetteringOn,
 	}, nil
 }
 
-func (c *QueueConfig) Backoff() opt.Val[*BackoffConfig] { return c.backoff }
-func (c *QueueConfig) ProcessingTimeout() time.Duration { return c.processingTimeout }
-func (c *QueueConfig) IsDeadLetteringOn() bool          { return c.deadLetteringOn }
+func (c *QueueConfig) Backoff() opt.Val[*BackoffConfig]          { return c.backoff }
+func (c *QueueConfig) ProcessingTimeout() time.Duration          { return c.processingTimeout }
+func (c *QueueConfig) MaxProcessingTime() opt.Val[time.Duration] { return c.maxProcessingTime }
+func (c *QueueConfig) Retention() opt.Val[time.Duration]         { return c.retention }
+func (c *QueueConfig) RateLimit() opt.Val[*RateLimit]            { return c.rateLimit }
+func (c *QueueConfig) PriorityAging() opt.Val[*PriorityAging]    { return c.priorityAging }
+func (c *QueueConfig) PriorityRange() *PriorityRange             { return c.priorityRange }
+func (c *QueueConfig) DefaultPriority() Priority                 { return c.defaultPriority }
+func (c *QueueConfig) DedupWindow() time.Duration                { return c.dedupWindow }
+func (c *QueueConfig) IsDeadLetteringOn() bool                   { return c.deadLetteringOn }
+
+// RateLimit allows to hand out `messages` per `interval` on average
+// and up to `burst` messages at once after a period of inactivity.
+type RateLimit struct {
+	messages int
+	interval time.Duration
+	burst    int
+}
+
+func NewRateLimit(messages int, interval time.Duration, burst int) (*RateLimit, error) {
+	if messages <= 0 {
+		return nil, errors.New("messages must be greater than zero")
+	}
+
+	if interval <= 0 {
+		return nil, errors.New("interval must be greater than zero")
+	}
+
+	if burst <= 0 {
+		return nil, errors.New("burst must be greater than zero")
+	}
+
+	return &RateLimit{
+		messages: messages,
+		interval: interval,
+		burst:    burst,
+	}, nil
+}
+
+func (l *RateLimit) Messages() int           { return l.messages }
+func (l *RateLimit) Interval() time.Duration { return l.interval }
+func (l *RateLimit) Burst() int              { return l.burst }
+
+// tokensPerSecond is the refill rate of the token bucket.
+func (l *RateLimit) tokensPerSecond() float64 {
+	return float64(l.messages) / l.interval.Seconds()
+}
+
+// PriorityAging raises the priority of available messages by `step` for every `interval`
+// they wait in the queue, up to `maxPriority`, so low-priority messages can't starve.
+type PriorityAging struct {
+	interval    time.Duration
+	step        int
+	maxPriority Priority
+}
+
+func NewPriorityAging(interval time.Duration, step int, maxPriority Priority) (*PriorityAging, error) {
+	if interval < time.Second {
+		return nil, errors.New("interval must be at least 1 second")
+	}
+
+	if step <= 0 {
+		return nil, errors.New("step must be greater than zero")
+	}
+
+	return &PriorityAging{
+		interval:    interval,
+		step:        step,
+		maxPriority: maxPriority,
+	}, nil
+}
+
+func (a *PriorityAging) Interval() time.Duration { return a.interval }
+func (a *PriorityAging) Step() int               { return a.step }
+func (a *PriorityAging) MaxPriority() Priority   { return a.maxPriority }
 
 type BackoffConfig struct {
 	shape       []time.Duration
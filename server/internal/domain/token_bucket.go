@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"math"
+	"time"
+
+	"server/internal/utils/timeutils"
+)
+
+// TokenBucket holds the shared rate limiting state of a queue: every handed out message costs a token,
+// tokens are refilled continuously according to the queue's RateLimit.
+type TokenBucket struct {
+	queue     QueueName
+	tokens    float64
+	updatedAt time.Time
+}
+
+// NewTokenBucket creates a full bucket, so the queue can burst right away.
+func NewTokenBucket(clock timeutils.Clock, queue QueueName, limit *RateLimit) *TokenBucket {
+	return &TokenBucket{
+		queue:     queue,
+		tokens:    float64(limit.Burst()),
+		updatedAt: clock.Now(),
+	}
+}
+
+func (b *TokenBucket) Queue() QueueName { return b.queue }
+
+// Take grants up to wanted tokens and returns how many were granted.
+// If nothing can be granted, it also returns how long to wait for the next token.
+func (b *TokenBucket) Take(clock timeutils.Clock, limit *RateLimit, wanted int) (int, time.Duration) {
+	b.refill(clock, limit)
+
+	granted := min(wanted, int(math.Floor(b.tokens)))
+	if granted > 0 {
+		b.tokens -= float64(granted)
+		return granted, 0
+	}
+
+	missing := 1 - b.tokens
+	wait := time.Duration(missing / limit.tokensPerSecond() * float64(time.Second))
+
+	return 0, wait
+}
+
+// GiveBack returns tokens that were granted but not used, e.g. when the queue had fewer messages.
+func (b *TokenBucket) GiveBack(limit *RateLimit, unused int) {
+	b.tokens = min(float64(limit.Burst()), b.tokens+float64(unused))
+}
+
+func (b *TokenBucket) refill(clock timeutils.Clock, limit *RateLimit) {
+	now := clock.Now()
+
+	elapsed := now.Sub(b.updatedAt)
+	if elapsed > 0 {
+		b.tokens = min(float64(limit.Burst()), b.tokens+elapsed.Seconds()*limit.tokensPerSecond())
+	}
+
+	b.updatedAt = now
+}
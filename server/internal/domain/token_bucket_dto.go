@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// TokenBucketDTO supposed to be used only for storage, don't change values manually
+type TokenBucketDTO struct {
+	Queue     string
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+func TokenBucketFromDTO(dto *TokenBucketDTO) *TokenBucket {
+	return &TokenBucket{
+		queue:     UnsafeQueueName(dto.Queue),
+		tokens:    dto.Tokens,
+		updatedAt: dto.UpdatedAt,
+	}
+}
+
+func (b *TokenBucket) ToDTO() *TokenBucketDTO {
+	return &TokenBucketDTO{
+		Queue:     b.queue.String(),
+		Tokens:    b.tokens,
+		UpdatedAt: b.updatedAt,
+	}
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"server/internal/utils/timeutils"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Date(2025, 6, 12, 12, 0, 0, 0, time.Local)
+
+	// 2 messages per second, up to 4 at once
+	limit, err := NewRateLimit(2, time.Second, 4)
+	require.NoError(t, err)
+
+	t.Run("StartsFull", func(t *testing.T) {
+		clock := timeutils.NewStubClock(now)
+		bucket := NewTokenBucket(clock, UnsafeQueueName("test"), limit)
+
+		granted, wait := bucket.Take(clock, limit, 10)
+		require.Equal(t, 4, granted)
+		require.Zero(t, wait)
+	})
+
+	t.Run("EmptyReturnsWait", func(t *testing.T) {
+		clock := timeutils.NewStubClock(now)
+		bucket := NewTokenBucket(clock, UnsafeQueueName("test"), limit)
+
+		granted, _ := bucket.Take(clock, limit, 4)
+		require.Equal(t, 4, granted)
+
+		granted, wait := bucket.Take(clock, limit, 1)
+		require.Zero(t, granted)
+		require.Equal(t, 500*time.Millisecond, wait)
+	})
+
+	t.Run("Refills", func(t *testing.T) {
+		clock := timeutils.NewStubClock(now)
+		bucket := NewTokenBucket(clock, UnsafeQueueName("test"), limit)
+
+		granted, _ := bucket.Take(clock, limit, 4)
+		require.Equal(t, 4, granted)
+
+		clock.Set(now.Add(time.Second))
+
+		granted, _ = bucket.Take(clock, limit, 4)
+		require.Equal(t, 2, granted)
+	})
+
+	t.Run("RefillCappedAtBurst", func(t *testing.T) {
+		clock := timeutils.NewStubClock(now)
+		bucket := NewTokenBucket(clock, UnsafeQueueName("test"), limit)
+
+		clock.Set(now.Add(time.Hour))
+
+		granted, _ := bucket.Take(clock, limit, 10)
+		require.Equal(t, 4, granted)
+	})
+
+	t.Run("GiveBack", func(t *testing.T) {
+		clock := timeutils.NewStubClock(now)
+		bucket := NewTokenBucket(clock, UnsafeQueueName("test"), limit)
+
+		granted, _ := bucket.Take(clock, limit, 3)
+		require.Equal(t, 3, granted)
+
+		bucket.GiveBack(limit, 2)
+
+		granted, _ = bucket.Take(clock, limit, 10)
+		require.Equal(t, 3, granted)
+	})
+}
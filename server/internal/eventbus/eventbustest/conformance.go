@@ -0,0 +1,155 @@
+// Package eventbustest holds the behavior every eventbus.PubSubDriver must have,
+// so that the event bus works the same way on top of any of them.
+package eventbustest
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"server/internal/eventbus"
+)
+
+// listenerStartDelay gives listeners time to subscribe before publishing,
+// drivers have no way to report that they are listening.
+const listenerStartDelay = 50 * time.Millisecond
+
+const listenDuration = 150 * time.Millisecond
+
+type received struct {
+	channel string
+	message string
+}
+
+// RunDriverSuite checks the driver against the PubSubDriver contract. Every check uses its own channels.
+func RunDriverSuite(t *testing.T, newDriver func(t *testing.T) eventbus.PubSubDriver) {
+	t.Run("messages are received in order", func(t *testing.T) {
+		driver := newDriver(t)
+
+		publishErr := publishLater(driver, "conformance_order", "hello world", "abacaba")
+
+		got, err := listen(driver, []string{"conformance_order"})
+
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Equal(t, []received{
+			{channel: "conformance_order", message: "hello world"},
+			{channel: "conformance_order", message: "abacaba"},
+		}, got)
+		require.NoError(t, <-publishErr)
+	})
+
+	t.Run("other channels are not received", func(t *testing.T) {
+		driver := newDriver(t)
+
+		// listen to the other channel first, to check that the listener is removed on return
+		_, err := listen(driver, []string{"conformance_other"})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		publishErr := publishLater(driver, "conformance_other", "hello world")
+
+		got, err := listen(driver, []string{"conformance_wanted"})
+
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Empty(t, got)
+		require.NoError(t, <-publishErr)
+	})
+
+	t.Run("several channels are received by one listener", func(t *testing.T) {
+		driver := newDriver(t)
+
+		publishErr := make(chan error, 2)
+		go func() {
+			time.Sleep(listenerStartDelay)
+			publishErr <- driver.Publish("conformance_first", "one")
+			publishErr <- driver.Publish("conformance_second", "two")
+		}()
+
+		got, err := listen(driver, []string{"conformance_first", "conformance_second"})
+
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Equal(t, []received{
+			{channel: "conformance_first", message: "one"},
+			{channel: "conformance_second", message: "two"},
+		}, got)
+		require.NoError(t, <-publishErr)
+		require.NoError(t, <-publishErr)
+	})
+
+	t.Run("every listener receives the message", func(t *testing.T) {
+		driver := newDriver(t)
+
+		publishErr := publishLater(driver, "conformance_fanout", "hello world")
+
+		var wg sync.WaitGroup
+		results := make([][]received, 2)
+		errs := make([]error, 2)
+
+		for i := range results {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i], errs[i] = listen(driver, []string{"conformance_fanout"})
+			}()
+		}
+		wg.Wait()
+
+		for i := range results {
+			require.ErrorIs(t, errs[i], context.DeadlineExceeded)
+			require.Equal(t, []received{{channel: "conformance_fanout", message: "hello world"}}, results[i])
+		}
+		require.NoError(t, <-publishErr)
+	})
+
+	t.Run("listen stops on context cancel", func(t *testing.T) {
+		driver := newDriver(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(listenerStartDelay, cancel)
+
+		err := driver.Listen(ctx, []string{"conformance_cancel"}, func(channel, message string) {})
+
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("publish without listeners succeeds", func(t *testing.T) {
+		driver := newDriver(t)
+
+		require.NoError(t, driver.Publish("conformance_nobody", "hello world"))
+	})
+}
+
+func publishLater(driver eventbus.PubSubDriver, channel string, messages ...string) <-chan error {
+	errCh := make(chan error, 1)
+
+	go func() {
+		time.Sleep(listenerStartDelay)
+
+		for _, message := range messages {
+			if err := driver.Publish(channel, message); err != nil {
+				errCh <- err
+				return
+			}
+		}
+
+		errCh <- nil
+	}()
+
+	return errCh
+}
+
+// listen collects notifications for listenDuration.
+func listen(driver eventbus.PubSubDriver, channels []string) ([]received, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), listenDuration)
+	defer cancel()
+
+	var got []received
+
+	err := driver.Listen(ctx, channels, func(channel, message string) {
+		got = append(got, received{channel: channel, message: message})
+	})
+
+	return got, err
+}
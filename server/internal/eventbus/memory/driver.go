@@ -0,0 +1,127 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"server/internal/eventbus"
+)
+
+var _ eventbus.PubSubDriver = (*PubSubDriver)(nil)
+
+// PubSubDriver delivers notifications within the process, so it fits a single instance only
+// (e.g. the run command or tests). Like LISTEN/NOTIFY, a notification reaches listeners
+// which are listening at the moment of publishing, and nobody else.
+type PubSubDriver struct {
+	mu        sync.Mutex
+	listeners map[string]map[*listener]struct{}
+}
+
+func NewPubSubDriver() *PubSubDriver {
+	return &PubSubDriver{
+		listeners: make(map[string]map[*listener]struct{}),
+	}
+}
+
+func (d *PubSubDriver) Listen(ctx context.Context, channels []string, h eventbus.DriverEventHandler) error {
+	l := newListener()
+
+	d.subscribe(l, channels)
+	defer d.unsubscribe(l, channels)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-l.wake:
+			for _, n := range l.take() {
+				h(n.channel, n.message)
+			}
+		}
+	}
+}
+
+// Publish never blocks on slow listeners, notifications are queued for each of them.
+// Like LISTEN/NOTIFY, a notification already pending for a listener isn't queued again,
+// and notifications are dropped when the queue of a listener is full.
+func (d *PubSubDriver) Publish(channel string, message string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for l := range d.listeners[channel] {
+		l.push(notification{channel: channel, message: message})
+	}
+
+	return nil
+}
+
+func (d *PubSubDriver) subscribe(l *listener, channels []string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, channel := range channels {
+		if _, exist := d.listeners[channel]; !exist {
+			d.listeners[channel] = make(map[*listener]struct{})
+		}
+		d.listeners[channel][l] = struct{}{}
+	}
+}
+
+func (d *PubSubDriver) unsubscribe(l *listener, channels []string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, channel := range channels {
+		delete(d.listeners[channel], l)
+		if len(d.listeners[channel]) == 0 {
+			delete(d.listeners, channel)
+		}
+	}
+}
+
+// listenerCapacity is how many distinct notifications may wait for a listener.
+const listenerCapacity = 1024
+
+type notification struct {
+	channel string
+	message string
+}
+
+type listener struct {
+	mu      sync.Mutex
+	queue   []notification
+	pending map[notification]struct{}
+	wake    chan struct{}
+}
+
+func newListener() *listener {
+	return &listener{
+		pending: make(map[notification]struct{}),
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+func (l *listener) push(n notification) {
+	l.mu.Lock()
+	if _, exist := l.pending[n]; !exist && len(l.queue) < listenerCapacity {
+		l.queue = append(l.queue, n)
+		l.pending[n] = struct{}{}
+	}
+	l.mu.Unlock()
+
+	// a pending wake-up is enough, the listener takes the whole queue
+	select {
+	case l.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (l *listener) take() []notification {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	queue := l.queue
+	l.queue = nil
+	clear(l.pending)
+	return queue
+}
@@ -0,0 +1,15 @@
+package memory_test
+
+import (
+	"testing"
+
+	"server/internal/eventbus"
+	"server/internal/eventbus/eventbustest"
+	"server/internal/eventbus/memory"
+)
+
+func TestPubSubDriverConformance(t *testing.T) {
+	eventbustest.RunDriverSuite(t, func(t *testing.T) eventbus.PubSubDriver {
+		return memory.NewPubSubDriver()
+	})
+}
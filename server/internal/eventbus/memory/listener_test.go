@@ -0,0 +1,37 @@
+package memory
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListenerCoalescesPendingNotifications(t *testing.T) {
+	l := newListener()
+
+	l.push(notification{channel: "msg_available", message: "test"})
+	l.push(notification{channel: "msg_available", message: "test"})
+	l.push(notification{channel: "msg_available", message: "other"})
+
+	require.Equal(t, []notification{
+		{channel: "msg_available", message: "test"},
+		{channel: "msg_available", message: "other"},
+	}, l.take())
+
+	// taken notifications can be queued again
+	l.push(notification{channel: "msg_available", message: "test"})
+	require.Len(t, l.take(), 1)
+}
+
+func TestListenerDropsNotificationsWhenFull(t *testing.T) {
+	l := newListener()
+
+	for i := range listenerCapacity + 10 {
+		l.push(notification{channel: "msg_available", message: fmt.Sprintf("queue-%d", i)})
+	}
+
+	queue := l.take()
+	require.Len(t, queue, listenerCapacity)
+	require.Equal(t, "queue-0", queue[0].message)
+}
@@ -5,6 +5,8 @@ import (
 	"testing"
 	"time"
 
+	"server/internal/eventbus"
+	"server/internal/eventbus/eventbustest"
 	"server/internal/eventbus/postgres"
 	"server/internal/utils/testutils"
 
@@ -18,6 +20,7 @@ const testMessage2 = "abacaba"
 
 func TestPubSubDriverTwoMessages(t *testing.T) {
 	testutils.SkipIfNotInTestEnv(t)
+	testutils.SkipIfSQLite(t)
 
 	db, err := testutils.OpenDB()
 	require.NoError(t, err)
@@ -59,6 +62,7 @@ func TestPubSubDriverTwoMessages(t *testing.T) {
 
 func TestPubSubDriverUnwantedMessage(t *testing.T) {
 	testutils.SkipIfNotInTestEnv(t)
+	testutils.SkipIfSQLite(t)
 
 	db, err := testutils.OpenDB()
 	require.NoError(t, err)
@@ -99,3 +103,15 @@ func TestPubSubDriverUnwantedMessage(t *testing.T) {
 
 	require.NoError(t, <-publishErr)
 }
+
+func TestPubSubDriverConformance(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+	testutils.SkipIfSQLite(t)
+
+	db, err := testutils.OpenDB()
+	require.NoError(t, err)
+
+	eventbustest.RunDriverSuite(t, func(t *testing.T) eventbus.PubSubDriver {
+		return postgres.NewPubSubDriver(db)
+	})
+}
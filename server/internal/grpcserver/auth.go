@@ -0,0 +1,73 @@
+package grpcserver
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"server/internal/auth"
+)
+
+const bearerPrefix = "Bearer "
+
+// authenticate resolves the principal from the "authorization" metadata,
+// which carries the API key the same way as the HTTP Authorization header.
+func authenticate(ctx context.Context, authenticator *auth.Authenticator) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	values := md.Get("authorization")
+	if len(values) == 0 || !strings.HasPrefix(values[0], bearerPrefix) {
+		return nil, status.Error(codes.Unauthenticated, "bearer api key expected in authorization metadata")
+	}
+
+	principal, ok := authenticator.Authenticate(strings.TrimPrefix(values[0], bearerPrefix))
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "invalid api key")
+	}
+
+	return auth.WithPrincipal(ctx, principal), nil
+}
+
+func newAuthUnaryInterceptor(authenticator *auth.Authenticator) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		_ *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		ctx, err := authenticate(ctx, authenticator)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func newAuthStreamInterceptor(authenticator *auth.Authenticator) grpc.StreamServerInterceptor {
+	return func(
+		srv any,
+		stream grpc.ServerStream,
+		_ *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		ctx, err := authenticate(stream.Context(), authenticator)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// contextStream replaces the context of the stream, so interceptors can pass values to handlers.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
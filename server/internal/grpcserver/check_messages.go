@@ -0,0 +1,59 @@
+package grpcserver
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"server/pkg/grpcapi"
+	"server/pkg/httpmodels"
+)
+
+func (s *Server) Check(ctx context.Context, req *grpcapi.CheckRequest) (*grpcapi.CheckResponse, error) {
+	ids := httpmodels.CheckRequest(req.GetIds())
+	if err := ids.Validate(); err != nil {
+		return nil, invalidRequest(err.Error())
+	}
+
+	result, err := s.checkMessages.Do(ctx, ids)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &grpcapi.CheckResponse{Messages: make([]*grpcapi.Message, 0, len(result))}
+
+	for _, msg := range result {
+		history := make([]*grpcapi.MessageChapter, 0, len(msg.History))
+
+		for _, chap := range msg.History {
+			history = append(history, &grpcapi.MessageChapter{
+				Generation:   int32(chap.Generation),
+				Queue:        chap.Queue.String(),
+				RedirectedAt: timestamppb.New(chap.RedirectedAt),
+				Priority:     int32(chap.Priority),
+				Retries:      int32(chap.Retries),
+			})
+		}
+
+		var finalizedAt *timestamppb.Timestamp
+		if msg.FinalizedAt != nil {
+			finalizedAt = timestamppb.New(*msg.FinalizedAt)
+		}
+
+		resp.Messages = append(resp.Messages, &grpcapi.Message{
+			Id:          msg.ID,
+			Queue:       msg.Queue.String(),
+			CreatedAt:   timestamppb.New(msg.CreatedAt),
+			FinalizedAt: finalizedAt,
+			Status:      msg.Status,
+			Priority:    int32(msg.Priority),
+			Retries:     int32(msg.Retries),
+			Generation:  int32(msg.Generation),
+			History:     history,
+			Payload:     msg.Payload,
+			Headers:     msg.Headers,
+		})
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,112 @@
+package grpcserver
+
+import (
+	"context"
+	"time"
+
+	"server/internal/domain"
+	"server/internal/usecases"
+	"server/internal/utils"
+	"server/pkg/grpcapi"
+	"server/pkg/httpmodels"
+)
+
+// streamPollTimeout is how long a streaming consumer waits for messages in a single poll,
+// the stream keeps polling until the client cancels it.
+const streamPollTimeout = 30 * time.Second
+
+func (s *Server) Consume(ctx context.Context, req *grpcapi.ConsumeRequest) (*grpcapi.ConsumeResponse, error) {
+	consumeReq := httpmodels.ConsumeRequest{Queue: req.GetQueue()}
+	if req.Limit != nil {
+		consumeReq.Limit = utils.P(int(req.GetLimit()))
+	}
+	if req.Poll != nil {
+		consumeReq.Poll = utils.P(int(req.GetPoll()))
+	}
+
+	if err := consumeReq.Validate(); err != nil {
+		return nil, invalidRequest(err.Error())
+	}
+
+	queue, err := domain.NewQueueName(consumeReq.Queue)
+	if err != nil {
+		return nil, invalidRequest(err.Error())
+	}
+
+	limit := 1
+	if consumeReq.Limit != nil {
+		limit = *consumeReq.Limit
+	}
+
+	poll := time.Duration(0)
+	if consumeReq.Poll != nil {
+		poll = time.Duration(*consumeReq.Poll) * time.Second
+	}
+
+	messages, err := s.consumeMessages.Do(ctx, queue, limit, poll)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &grpcapi.ConsumeResponse{Messages: make([]*grpcapi.ConsumedMessage, 0, len(messages))}
+	for _, msg := range messages {
+		resp.Messages = append(resp.Messages, toConsumedMessage(msg))
+	}
+
+	return resp, nil
+}
+
+func (s *Server) ConsumeStream(
+	req *grpcapi.ConsumeStreamRequest,
+	stream grpcapi.QueueService_ConsumeStreamServer,
+) error {
+	ctx := stream.Context()
+
+	consumeReq := httpmodels.ConsumeRequest{Queue: req.GetQueue()}
+	if req.BatchSize != nil {
+		consumeReq.Limit = utils.P(int(req.GetBatchSize()))
+	}
+
+	if err := consumeReq.Validate(); err != nil {
+		return invalidRequest(err.Error())
+	}
+
+	queue, err := domain.NewQueueName(consumeReq.Queue)
+	if err != nil {
+		return invalidRequest(err.Error())
+	}
+
+	batchSize := 1
+	if consumeReq.Limit != nil {
+		batchSize = *consumeReq.Limit
+	}
+
+	for ctx.Err() == nil {
+		messages, err := s.consumeMessages.Do(ctx, queue, batchSize, streamPollTimeout)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return toStatus(err)
+		}
+
+		for _, msg := range messages {
+			// messages that failed to be sent will be redelivered after the processing timeout
+			if err := stream.Send(toConsumedMessage(msg)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func toConsumedMessage(msg usecases.MessageToConsume) *grpcapi.ConsumedMessage {
+	return &grpcapi.ConsumedMessage{
+		Id:          msg.ID,
+		AttemptId:   msg.AttemptID,
+		Payload:     msg.Payload,
+		Headers:     msg.Headers,
+		TraceParent: msg.TraceParent,
+	}
+}
@@ -0,0 +1,45 @@
+package grpcserver
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"server/internal/apierrors"
+	"server/pkg/grpcapi"
+	"server/pkg/httpmodels"
+)
+
+func invalidRequest(message string) error {
+	return status.Error(codes.InvalidArgument, message)
+}
+
+// toStatus converts a usecase error to a gRPC status, known errors get the same
+// classification as in the HTTP API.
+func toStatus(err error) error {
+	apiErr := apierrors.ExtractKnownErrors(err)
+	return status.Error(mapErrorCodeToStatusCode(apiErr.Code()), apiErr.Error())
+}
+
+func toErrorModel(apiErr *httpmodels.Error) *grpcapi.Error {
+	return &grpcapi.Error{
+		Code:    string(apiErr.Code()),
+		Message: apiErr.Error(),
+	}
+}
+
+func mapErrorCodeToStatusCode(code httpmodels.ErrorCode) codes.Code {
+	switch code {
+	case httpmodels.ErrorCodeRequestInvalid, httpmodels.ErrorCodeBatchSizeTooBig, httpmodels.ErrorCodeQueueNotWritable:
+		return codes.InvalidArgument
+	case httpmodels.ErrorCodeMessageNotFound, httpmodels.ErrorCodeQueueNotFound:
+		return codes.NotFound
+	case httpmodels.ErrorCodeAttemptMismatch, httpmodels.ErrorCodeMaxLeaseReached:
+		return codes.FailedPrecondition
+	case httpmodels.ErrorCodeUnauthorized:
+		return codes.Unauthenticated
+	case httpmodels.ErrorCodeForbidden:
+		return codes.PermissionDenied
+	default:
+		return codes.Internal
+	}
+}
@@ -0,0 +1,185 @@
+package grpcserver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"server/internal/domain"
+	"server/internal/usecases"
+	"server/internal/utils"
+	"server/internal/utils/opt"
+	"server/pkg/grpcapi"
+	"server/pkg/httpmodels"
+)
+
+func (s *Server) Ack(ctx context.Context, req *grpcapi.AckRequest) (*grpcapi.OkResponse, error) {
+	items := make(httpmodels.AckRequest, 0, len(req.GetMessages()))
+	for _, msg := range req.GetMessages() {
+		items = append(items, httpmodels.AckRequestItem{
+			ID:        msg.GetId(),
+			AttemptID: msg.GetAttemptId(),
+			Release:   msg.GetRelease(),
+		})
+	}
+
+	if err := items.Validate(); err != nil {
+		return nil, invalidRequest(err.Error())
+	}
+
+	ackParams := make([]usecases.AckParams, 0, len(items))
+	for _, item := range items {
+		attemptID, err := parseAttemptID(item.AttemptID)
+		if err != nil {
+			return nil, err
+		}
+
+		ackParams = append(ackParams, usecases.AckParams{
+			ID:        item.ID,
+			AttemptID: attemptID,
+			Release:   item.Release,
+		})
+	}
+
+	if err := s.ackMessages.Do(ctx, ackParams); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &grpcapi.OkResponse{}, nil
+}
+
+func (s *Server) Nack(ctx context.Context, req *grpcapi.NackRequest) (*grpcapi.OkResponse, error) {
+	items := make(httpmodels.NackRequest, 0, len(req.GetMessages()))
+	for _, msg := range req.GetMessages() {
+		item := httpmodels.NackRequestItem{
+			ID:        msg.GetId(),
+			AttemptID: msg.GetAttemptId(),
+		}
+		if msg.Redeliver != nil {
+			item.Redeliver = utils.P(msg.GetRedeliver())
+		}
+		items = append(items, item)
+	}
+
+	if err := items.Validate(); err != nil {
+		return nil, invalidRequest(err.Error())
+	}
+
+	nackParams := make([]usecases.NackParams, 0, len(items))
+	for _, item := range items {
+		redeliver := true
+		if item.Redeliver != nil {
+			redeliver = *item.Redeliver
+		}
+
+		attemptID, err := parseAttemptID(item.AttemptID)
+		if err != nil {
+			return nil, err
+		}
+
+		nackParams = append(nackParams, usecases.NackParams{
+			ID:        item.ID,
+			AttemptID: attemptID,
+			Redeliver: redeliver,
+		})
+	}
+
+	if err := s.nackMessages.Do(ctx, nackParams); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &grpcapi.OkResponse{}, nil
+}
+
+func (s *Server) Redirect(ctx context.Context, req *grpcapi.RedirectRequest) (*grpcapi.OkResponse, error) {
+	items := make(httpmodels.RedirectRequest, 0, len(req.GetMessages()))
+	for _, msg := range req.GetMessages() {
+		items = append(items, httpmodels.RedirectRequestItem{
+			ID:          msg.GetId(),
+			AttemptID:   msg.GetAttemptId(),
+			Destination: msg.GetDestination(),
+		})
+	}
+
+	if err := items.Validate(); err != nil {
+		return nil, invalidRequest(err.Error())
+	}
+
+	redirectParams := make([]usecases.RedirectParams, 0, len(items))
+	for _, item := range items {
+		destination, err := domain.NewQueueName(item.Destination)
+		if err != nil {
+			return nil, invalidRequest(fmt.Sprintf("domain.NewQueueName(%s): %v", item.Destination, err))
+		}
+
+		attemptID, err := parseAttemptID(item.AttemptID)
+		if err != nil {
+			return nil, err
+		}
+
+		redirectParams = append(redirectParams, usecases.RedirectParams{
+			ID:          item.ID,
+			AttemptID:   attemptID,
+			Destination: destination,
+		})
+	}
+
+	if err := s.redirectMessages.Do(ctx, redirectParams); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &grpcapi.OkResponse{}, nil
+}
+
+func (s *Server) Extend(ctx context.Context, req *grpcapi.ExtendRequest) (*grpcapi.OkResponse, error) {
+	items := make(httpmodels.ExtendRequest, 0, len(req.GetMessages()))
+	for _, msg := range req.GetMessages() {
+		item := httpmodels.ExtendRequestItem{
+			ID:        msg.GetId(),
+			AttemptID: msg.GetAttemptId(),
+		}
+		if msg.Duration != nil {
+			item.Duration = utils.P(int(msg.GetDuration()))
+		}
+		items = append(items, item)
+	}
+
+	if err := items.Validate(); err != nil {
+		return nil, invalidRequest(err.Error())
+	}
+
+	extendParams := make([]usecases.ExtendParams, 0, len(items))
+	for _, item := range items {
+		attemptID, err := parseAttemptID(item.AttemptID)
+		if err != nil {
+			return nil, err
+		}
+
+		duration := opt.None[time.Duration]()
+		if item.Duration != nil {
+			duration = opt.Some(time.Duration(*item.Duration) * time.Second)
+		}
+
+		extendParams = append(extendParams, usecases.ExtendParams{
+			ID:        item.ID,
+			AttemptID: attemptID,
+			Duration:  duration,
+		})
+	}
+
+	if err := s.extendMessages.Do(ctx, extendParams); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &grpcapi.OkResponse{}, nil
+}
+
+func parseAttemptID(attemptID string) (uuid.UUID, error) {
+	parsed, err := uuid.Parse(attemptID)
+	if err != nil {
+		return uuid.UUID{}, invalidRequest(fmt.Sprintf("uuid.Parse(%s): %v", attemptID, err))
+	}
+	return parsed, nil
+}
@@ -0,0 +1,138 @@
+package grpcserver
+
+import (
+	"context"
+
+	"server/internal/apibatch"
+	"server/internal/domain"
+	"server/internal/usecases"
+	"server/internal/utils"
+	"server/internal/utils/opt"
+	"server/pkg/grpcapi"
+	"server/pkg/httpmodels"
+)
+
+func (s *Server) Publish(ctx context.Context, req *grpcapi.PublishRequest) (*grpcapi.PublishResponse, error) {
+	return s.publish(ctx, req, true)
+}
+
+func (s *Server) Prepare(ctx context.Context, req *grpcapi.PublishRequest) (*grpcapi.PublishResponse, error) {
+	return s.publish(ctx, req, false)
+}
+
+func (s *Server) publish(
+	ctx context.Context,
+	req *grpcapi.PublishRequest,
+	autoRelease bool,
+) (*grpcapi.PublishResponse, error) {
+	items := make(httpmodels.PublishRequest, 0, len(req.GetMessages()))
+	for _, msg := range req.GetMessages() {
+		item := httpmodels.PublishRequestItem{
+			Queue:    msg.GetQueue(),
+			Payload:  msg.GetPayload(),
+			Headers:  msg.GetHeaders(),
+			Group:    msg.Group,
+			DedupKey: msg.DedupKey,
+		}
+		if msg.Priority != nil {
+			item.Priority = utils.P(int(msg.GetPriority()))
+		}
+		if msg.GetStartAt() != nil {
+			item.StartAt = utils.P(msg.GetStartAt().AsTime())
+		}
+		items = append(items, item)
+	}
+
+	if err := items.Validate(); err != nil {
+		return nil, invalidRequest(err.Error())
+	}
+
+	mappedItems, mapItemErrors := apibatch.MapBatchRequestItems(items, mapPublishRequestItem)
+
+	results, err := s.publishMessages.Do(ctx, mappedItems, autoRelease)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	batch := apibatch.MapBatchResults(mapItemErrors, results, func(result *usecases.NewMessageResult) *grpcapi.PublishedMessage {
+		return &grpcapi.PublishedMessage{Id: result.ID, Duplicate: result.Duplicate}
+	})
+
+	resp := &grpcapi.PublishResponse{Results: make([]*grpcapi.PublishResult, 0, len(batch))}
+	for _, result := range batch {
+		if result.Error != nil {
+			resp.Results = append(resp.Results, &grpcapi.PublishResult{
+				Result: &grpcapi.PublishResult_Error{Error: toErrorModel(result.Error)},
+			})
+			continue
+		}
+
+		resp.Results = append(resp.Results, &grpcapi.PublishResult{
+			Result: &grpcapi.PublishResult_Message{Message: result.Data},
+		})
+	}
+
+	return resp, nil
+}
+
+func mapPublishRequestItem(params httpmodels.PublishRequestItem) (usecases.NewMessageParams, *httpmodels.Error) {
+	queue, err := domain.NewQueueName(params.Queue)
+	if err != nil {
+		return usecases.NewMessageParams{}, httpmodels.NewError(httpmodels.ErrorCodeRequestInvalid, err.Error())
+	}
+
+	priority := opt.None[domain.Priority]()
+	if params.Priority != nil {
+		tmp, err := domain.NewPriority(*params.Priority)
+		if err != nil {
+			return usecases.NewMessageParams{}, httpmodels.NewError(httpmodels.ErrorCodeRequestInvalid, err.Error())
+		}
+		priority = opt.Some(tmp)
+	}
+
+	headers, err := domain.NewHeaders(params.Headers)
+	if err != nil {
+		return usecases.NewMessageParams{}, httpmodels.NewError(httpmodels.ErrorCodeRequestInvalid, err.Error())
+	}
+
+	var group *domain.MessageGroup
+	if params.Group != nil {
+		tmp, err := domain.NewMessageGroup(*params.Group)
+		if err != nil {
+			return usecases.NewMessageParams{}, httpmodels.NewError(httpmodels.ErrorCodeRequestInvalid, err.Error())
+		}
+		group = &tmp
+	}
+
+	dedupKey := opt.None[domain.DedupKey]()
+	if params.DedupKey != nil {
+		tmp, err := domain.NewDedupKey(*params.DedupKey)
+		if err != nil {
+			return usecases.NewMessageParams{}, httpmodels.NewError(httpmodels.ErrorCodeRequestInvalid, err.Error())
+		}
+		dedupKey = opt.Some(tmp)
+	}
+
+	return usecases.NewMessageParams{
+		Queue:    queue,
+		Payload:  params.Payload,
+		Headers:  headers,
+		Group:    group,
+		Priority: priority,
+		StartAt:  params.StartAt,
+		DedupKey: dedupKey,
+	}, nil
+}
+
+func (s *Server) Release(ctx context.Context, req *grpcapi.ReleaseRequest) (*grpcapi.OkResponse, error) {
+	ids := httpmodels.ReleaseRequest(req.GetIds())
+	if err := ids.Validate(); err != nil {
+		return nil, invalidRequest(err.Error())
+	}
+
+	if err := s.releaseMessages.Do(ctx, ids); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &grpcapi.OkResponse{}, nil
+}
@@ -0,0 +1,70 @@
+package grpcserver
+
+import (
+	"log/slog"
+
+	"google.golang.org/grpc"
+
+	"server/internal/auth"
+	"server/internal/tracing"
+	"server/internal/usecases"
+	"server/pkg/grpcapi"
+)
+
+// Server implements the gRPC API on top of the same usecases as the HTTP routes.
+type Server struct {
+	grpcapi.UnimplementedQueueServiceServer
+
+	logger           *slog.Logger
+	publishMessages  *usecases.PublishMessages
+	releaseMessages  *usecases.ReleaseMessages
+	consumeMessages  *usecases.ConsumeMessages
+	ackMessages      *usecases.AckMessages
+	nackMessages     *usecases.NackMessages
+	redirectMessages *usecases.RedirectMessages
+	extendMessages   *usecases.ExtendMessages
+	checkMessages    *usecases.CheckMessages
+}
+
+func NewServer(
+	logger *slog.Logger,
+	publishMessages *usecases.PublishMessages,
+	releaseMessages *usecases.ReleaseMessages,
+	consumeMessages *usecases.ConsumeMessages,
+	ackMessages *usecases.AckMessages,
+	nackMessages *usecases.NackMessages,
+	redirectMessages *usecases.RedirectMessages,
+	extendMessages *usecases.ExtendMessages,
+	checkMessages *usecases.CheckMessages,
+) *Server {
+	return &Server{
+		logger:           logger,
+		publishMessages:  publishMessages,
+		releaseMessages:  releaseMessages,
+		consumeMessages:  consumeMessages,
+		ackMessages:      ackMessages,
+		nackMessages:     nackMessages,
+		redirectMessages: redirectMessages,
+		extendMessages:   extendMessages,
+		checkMessages:    checkMessages,
+	}
+}
+
+// NewGRPCServer creates a grpc.Server with the queue service registered.
+// If authenticator is not nil, every call must carry a valid API key.
+func NewGRPCServer(srv *Server, authenticator *auth.Authenticator, tracer *tracing.Tracer) *grpc.Server {
+	unaryInterceptors := []grpc.UnaryServerInterceptor{newTracingUnaryInterceptor(tracer)}
+	streamInterceptors := []grpc.StreamServerInterceptor{newTracingStreamInterceptor(tracer)}
+	if authenticator != nil {
+		unaryInterceptors = append(unaryInterceptors, newAuthUnaryInterceptor(authenticator))
+		streamInterceptors = append(streamInterceptors, newAuthStreamInterceptor(authenticator))
+	}
+
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryInterceptors...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
+	)
+	grpcapi.RegisterQueueServiceServer(grpcServer, srv)
+
+	return grpcServer
+}
@@ -0,0 +1,91 @@
+package grpcserver
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"server/internal/tracing"
+)
+
+// startSpan continues the trace from the "traceparent" metadata, the same way as HTTP headers.
+func startSpan(ctx context.Context, tracer *tracing.Tracer, method string) (context.Context, trace.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = tracing.Extract(ctx, metadataCarrier(md))
+
+	return tracer.Start(ctx, method, trace.WithSpanKind(trace.SpanKindServer))
+}
+
+// endSpan marks the span as failed only for server faults, client mistakes are expected, as with HTTP 4xx.
+func endSpan(span trace.Span, err error) {
+	code := status.Code(err)
+	span.SetAttributes(attribute.String("rpc.grpc.status_code", code.String()))
+
+	switch code {
+	case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented, codes.Internal, codes.Unavailable, codes.DataLoss:
+		span.SetStatus(otelcodes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+func newTracingUnaryInterceptor(tracer *tracing.Tracer) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		ctx, span := startSpan(ctx, tracer, info.FullMethod)
+
+		resp, err := handler(ctx, req)
+		endSpan(span, err)
+
+		return resp, err
+	}
+}
+
+func newTracingStreamInterceptor(tracer *tracing.Tracer) grpc.StreamServerInterceptor {
+	return func(
+		srv any,
+		stream grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		ctx, span := startSpan(stream.Context(), tracer, info.FullMethod)
+
+		err := handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+		endSpan(span, err)
+
+		return err
+	}
+}
+
+// metadataCarrier adapts incoming metadata to propagation.TextMapCarrier, keys are lowercase there.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,150 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"server/internal/domain"
+)
+
+const namespace = "mq"
+
+// Metrics owns a dedicated registry, so several apps can live in one process (e.g. in tests).
+type Metrics struct {
+	registry *prometheus.Registry
+
+	messagesPublished    *prometheus.CounterVec
+	messagesConsumed     *prometheus.CounterVec
+	messagesAcked        *prometheus.CounterVec
+	messagesNacked       *prometheus.CounterVec
+	messagesRedirected   *prometheus.CounterVec
+	messagesExpired      *prometheus.CounterVec
+	messagesDeadLettered *prometheus.CounterVec
+
+	webhookDeliveries *prometheus.CounterVec
+
+	httpRequestDuration *prometheus.HistogramVec
+
+	workerBatchDuration *prometheus.HistogramVec
+	workerBatchSize     *prometheus.HistogramVec
+}
+
+func New() *Metrics {
+	newMsgCounter := func(name, help string) *prometheus.CounterVec {
+		return prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      name,
+			Help:      help,
+		}, []string{"queue"})
+	}
+
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+
+		messagesPublished:    newMsgCounter("messages_published_total", "Messages published to the queue."),
+		messagesConsumed:     newMsgCounter("messages_consumed_total", "Messages taken for processing from the queue."),
+		messagesAcked:        newMsgCounter("messages_acked_total", "Messages acknowledged as delivered."),
+		messagesNacked:       newMsgCounter("messages_nacked_total", "Messages negatively acknowledged by consumers."),
+		messagesRedirected:   newMsgCounter("messages_redirected_total", "Messages redirected from the queue by consumers."),
+		messagesExpired:      newMsgCounter("messages_expired_total", "Messages whose processing timed out."),
+		messagesDeadLettered: newMsgCounter("messages_dead_lettered_total", "Messages moved from the queue to its DLQ."),
+
+		webhookDeliveries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "webhook_deliveries_total",
+			Help:      "Webhook delivery attempts by outcome.",
+		}, []string{"queue", "outcome"}),
+
+		httpRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Latency of API requests by route and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "code"}),
+
+		workerBatchDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "worker_batch_duration_seconds",
+			Help:      "Time spent on a single batch by a background worker.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"worker"}),
+		workerBatchSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "worker_batch_size",
+			Help:      "Number of messages handled in a single batch by a background worker.",
+			Buckets:   []float64{0, 1, 5, 10, 25, 50, 100},
+		}, []string{"worker"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.messagesPublished,
+		m.messagesConsumed,
+		m.messagesAcked,
+		m.messagesNacked,
+		m.messagesRedirected,
+		m.messagesExpired,
+		m.messagesDeadLettered,
+		m.webhookDeliveries,
+		m.httpRequestDuration,
+		m.workerBatchDuration,
+		m.workerBatchSize,
+	)
+
+	return m
+}
+
+func (m *Metrics) MustRegister(collectors ...prometheus.Collector) {
+	m.registry.MustRegister(collectors...)
+}
+
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+func (m *Metrics) MsgPublished(queue domain.QueueName) {
+	m.messagesPublished.WithLabelValues(queue.String()).Inc()
+}
+
+func (m *Metrics) MsgsConsumed(queue domain.QueueName, count int) {
+	m.messagesConsumed.WithLabelValues(queue.String()).Add(float64(count))
+}
+
+func (m *Metrics) MsgAcked(queue domain.QueueName) {
+	m.messagesAcked.WithLabelValues(queue.String()).Inc()
+}
+
+func (m *Metrics) MsgNacked(queue domain.QueueName) {
+	m.messagesNacked.WithLabelValues(queue.String()).Inc()
+}
+
+func (m *Metrics) MsgRedirected(queue domain.QueueName) {
+	m.messagesRedirected.WithLabelValues(queue.String()).Inc()
+}
+
+func (m *Metrics) MsgExpired(queue domain.QueueName) {
+	m.messagesExpired.WithLabelValues(queue.String()).Inc()
+}
+
+func (m *Metrics) MsgDeadLettered(queue domain.QueueName) {
+	m.messagesDeadLettered.WithLabelValues(queue.String()).Inc()
+}
+
+func (m *Metrics) WebhookDelivery(queue domain.QueueName, outcome string) {
+	m.webhookDeliveries.WithLabelValues(queue.String(), outcome).Inc()
+}
+
+func (m *Metrics) ObserveHTTPRequest(route string, statusCode int, duration time.Duration) {
+	m.httpRequestDuration.WithLabelValues(route, strconv.Itoa(statusCode)).Observe(duration.Seconds())
+}
+
+func (m *Metrics) ObserveWorkerBatch(worker string, size int, duration time.Duration) {
+	m.workerBatchDuration.WithLabelValues(worker).Observe(duration.Seconds())
+	m.workerBatchSize.WithLabelValues(worker).Observe(float64(size))
+}
@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"server/internal/config"
+	"server/internal/domain"
+	"server/internal/storage"
+)
+
+const collectTimeout = 5 * time.Second
+
+// Statuses reported by the queue depth gauge. Finalized messages are not interesting
+// here as they are waiting for archivation only, so they aren't counted on scrapes either.
+var reportedStatuses = []domain.MessageStatus{
+	domain.MsgStatusPrepared,
+	domain.MsgStatusAvailable,
+	domain.MsgStatusProcessing,
+	domain.MsgStatusDelayed,
+}
+
+var _ prometheus.Collector = (*QueueCollector)(nil)
+
+// QueueCollector reports the number of messages per queue and status, querying the database on every scrape.
+type QueueCollector struct {
+	logger  *slog.Logger
+	db      *sql.DB
+	msgRepo *storage.MessageRepository
+	conf    *config.Config
+
+	messagesDesc *prometheus.Desc
+}
+
+func NewQueueCollector(
+	logger *slog.Logger,
+	db *sql.DB,
+	msgRepo *storage.MessageRepository,
+	conf *config.Config,
+) *QueueCollector {
+	return &QueueCollector{
+		logger:  logger,
+		db:      db,
+		msgRepo: msgRepo,
+		conf:    conf,
+
+		messagesDesc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "queue_messages"),
+			"Current number of messages in the queue by status.",
+			[]string{"queue", "status"},
+			nil,
+		),
+	}
+}
+
+func (c *QueueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.messagesDesc
+}
+
+func (c *QueueCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+
+	for _, status := range reportedStatuses {
+		counts, err := c.msgRepo.CountByQueue(ctx, c.db, status)
+		if err != nil {
+			c.logger.Error("msgRepo.CountByQueue", "error", err)
+			ch <- prometheus.NewInvalidMetric(c.messagesDesc, err)
+			return
+		}
+
+		// report zeros for known queues, so that absent series don't look like missing data
+		for _, queue := range c.conf.QueueNames() {
+			ch <- prometheus.MustNewConstMetric(
+				c.messagesDesc,
+				prometheus.GaugeValue,
+				float64(counts[queue]),
+				queue.String(),
+				string(status),
+			)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+// Package migrations keeps versioned schema changes embedded in the binary.
+//
+// Every file in sql/ is a migration named <version>_<name>.sql, versions go one by one from 1.
+// Applied versions are recorded in the schema_migrations table. Migrations are never edited
+// after release, a schema change is always a new file.
+//
+// SQLite has its own migrations in sqlite/, a schema change is a new file in both directories.
+// SQLite versions are independent, its baseline is the Postgres schema at version 11.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+
+	"server/internal/utils/dbutils"
+)
+
+//go:embed sql/*.sql sqlite/*.sql
+var files embed.FS
+
+var dirs = map[dbutils.Dialect]string{
+	dbutils.DialectPostgres: "sql",
+	dbutils.DialectSQLite:   "sqlite",
+}
+
+type Migration struct {
+	Version int
+	Name    string
+	SQL     string
+}
+
+// All returns the embedded migrations of the dialect ordered by version.
+func All(dialect dbutils.Dialect) ([]Migration, error) {
+	dir, found := dirs[dialect]
+	if !found {
+		return nil, fmt.Errorf("no migrations for dialect %q", dialect)
+	}
+
+	entries, err := fs.ReadDir(files, dir)
+	if err != nil {
+		return nil, fmt.Errorf("fs.ReadDir: %w", err)
+	}
+
+	// ReadDir returns entries sorted by file name, zero-padded versions keep them in order
+	migrations := make([]Migration, 0, len(entries))
+
+	for i, entry := range entries {
+		versionStr, name, found := strings.Cut(strings.TrimSuffix(entry.Name(), ".sql"), "_")
+		if !found {
+			return nil, fmt.Errorf("migration %q: name must be <version>_<name>.sql", entry.Name())
+		}
+
+		version, err := strconv.Atoi(versionStr)
+		if err != nil {
+			return nil, fmt.Errorf("migration %q: invalid version: %w", entry.Name(), err)
+		}
+
+		if version != i+1 {
+			return nil, fmt.Errorf("migration %q: expected version %d", entry.Name(), i+1)
+		}
+
+		content, err := fs.ReadFile(files, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("fs.ReadFile: %w", err)
+		}
+
+		migrations = append(migrations, Migration{
+			Version: version,
+			Name:    name,
+			SQL:     string(content),
+		})
+	}
+
+	return migrations, nil
+}
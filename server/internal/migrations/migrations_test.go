@@ -0,0 +1,54 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+
+	"server/internal/utils/dbutils"
+)
+
+func TestAll(t *testing.T) {
+	for _, dialect := range []dbutils.Dialect{dbutils.DialectPostgres, dbutils.DialectSQLite} {
+		migrations, err := All(dialect)
+		require.NoError(t, err)
+		require.NotEmpty(t, migrations)
+
+		for i, migration := range migrations {
+			require.Equal(t, i+1, migration.Version)
+			require.NotEmpty(t, migration.Name)
+			require.NotEmpty(t, migration.SQL)
+		}
+
+		require.Equal(t, "baseline", migrations[0].Name)
+	}
+}
+
+func TestMigrator_UpSQLite(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:"+filepath.Join(t.TempDir(), "queue.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := NewMigrator(slog.Default(), db, dbutils.DialectSQLite)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	require.ErrorIs(t, migrator.CheckVersion(ctx), ErrSchemaVersionMismatch)
+
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, applied)
+
+	require.NoError(t, migrator.CheckVersion(ctx))
+
+	// nothing is left to apply
+	applied, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	require.Empty(t, applied)
+}
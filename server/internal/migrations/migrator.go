@@ -0,0 +1,243 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"server/internal/utils/dbutils"
+)
+
+// advisoryLockKey serializes migrations of concurrently starting instances, it's an arbitrary constant.
+const advisoryLockKey = 7_362_010_251
+
+var ErrSchemaVersionMismatch = errors.New("schema version mismatch")
+
+type Status struct {
+	Current int // 0 if no migration has been applied
+	Latest  int
+	Pending []Migration
+}
+
+type Migrator struct {
+	logger     *slog.Logger
+	db         *sql.DB
+	dialect    dbutils.Dialect
+	migrations []Migration
+}
+
+func NewMigrator(logger *slog.Logger, db *sql.DB, dialect dbutils.Dialect) (*Migrator, error) {
+	migrations, err := All(dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		logger:     logger,
+		db:         db,
+		dialect:    dialect,
+		migrations: migrations,
+	}, nil
+}
+
+// Up applies pending migrations, each one in its own transaction, and returns the applied ones.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		current, err := m.currentVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		if current == 0 {
+			exists, err := m.tableExists(ctx, conn, "messages")
+			if err != nil {
+				return err
+			}
+			if exists {
+				return errors.New("schema exists, but has no migration history: record its version with 'migrate baseline <version>'")
+			}
+		}
+
+		for _, migration := range m.pending(current) {
+			if err := m.apply(ctx, conn, migration); err != nil {
+				return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+
+			m.logger.Info("migration applied", "version", migration.Version, "name", migration.Name)
+			applied = append(applied, migration)
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+// Baseline records migrations up to the version as applied without running them.
+// It's meant for databases created from initdb/queue.sql before migrations were introduced.
+func (m *Migrator) Baseline(ctx context.Context, version int) error {
+	if version < 1 || version > len(m.migrations) {
+		return fmt.Errorf("version must be between 1 and %d", len(m.migrations))
+	}
+
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		current, err := m.currentVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		if current != 0 {
+			return fmt.Errorf("schema already has migration history up to version %d", current)
+		}
+
+		for _, migration := range m.migrations[:version] {
+			if err := recordVersion(ctx, conn, migration); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (m *Migrator) Status(ctx context.Context) (*Status, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("db.Conn: %w", err)
+	}
+	defer conn.Close()
+
+	current, err := m.currentVersion(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Status{
+		Current: current,
+		Latest:  len(m.migrations),
+		Pending: m.pending(current),
+	}, nil
+}
+
+// CheckVersion fails with ErrSchemaVersionMismatch unless the schema is at the latest version.
+func (m *Migrator) CheckVersion(ctx context.Context) error {
+	status, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	if status.Current != status.Latest {
+		return fmt.Errorf("%w: database is at version %d, expected %d", ErrSchemaVersionMismatch, status.Current, status.Latest)
+	}
+
+	return nil
+}
+
+func (m *Migrator) pending(current int) []Migration {
+	if current >= len(m.migrations) {
+		return nil
+	}
+	return m.migrations[current:]
+}
+
+// withLock runs fn holding the advisory lock, session level locks need a dedicated connection.
+// SQLite has no advisory locks, it serves a single instance, so there is no one to wait for.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("db.Conn: %w", err)
+	}
+	defer conn.Close()
+
+	if m.dialect == dbutils.DialectPostgres {
+		if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, advisoryLockKey); err != nil {
+			return fmt.Errorf("pg_advisory_lock: %w", err)
+		}
+
+		defer func() {
+			// the context may be already canceled, the lock must be released anyway
+			if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, advisoryLockKey); err != nil {
+				m.logger.Error("pg_advisory_unlock", "error", err)
+			}
+		}()
+	}
+
+	query := `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version int PRIMARY KEY,
+			name varchar(255) NOT NULL,
+			applied_at timestamptz NOT NULL
+		)
+	`
+	if _, err := conn.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	return fn(conn)
+}
+
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("conn.BeginTx: %w", err)
+	}
+	defer dbutils.RollbackWithLog(tx, m.logger)
+
+	if _, err := tx.ExecContext(ctx, migration.SQL); err != nil {
+		return err
+	}
+
+	if err := recordVersion(ctx, tx, migration); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("tx.Commit: %w", err)
+	}
+
+	return nil
+}
+
+func (m *Migrator) currentVersion(ctx context.Context, conn dbutils.Querier) (int, error) {
+	exists, err := m.tableExists(ctx, conn, "schema_migrations")
+	if err != nil {
+		return 0, err
+	}
+
+	if !exists {
+		return 0, nil
+	}
+
+	var version int
+	if err := conn.QueryRowContext(ctx, `SELECT coalesce(max(version), 0) FROM schema_migrations`).Scan(&version); err != nil {
+		return 0, fmt.Errorf("select schema version: %w", err)
+	}
+
+	return version, nil
+}
+
+func recordVersion(ctx context.Context, conn dbutils.Querier, migration Migration) error {
+	query := `INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3)`
+	if _, err := conn.ExecContext(ctx, query, migration.Version, migration.Name, time.Now()); err != nil {
+		return fmt.Errorf("record schema version: %w", err)
+	}
+	return nil
+}
+
+func (m *Migrator) tableExists(ctx context.Context, conn dbutils.Querier, table string) (bool, error) {
+	query := `SELECT to_regclass($1) IS NOT NULL`
+	if m.dialect == dbutils.DialectSQLite {
+		query = `SELECT EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = $1)`
+	}
+
+	var exists bool
+	if err := conn.QueryRowContext(ctx, query, table).Scan(&exists); err != nil {
+		return false, fmt.Errorf("check table %s: %w", table, err)
+	}
+	return exists, nil
+}
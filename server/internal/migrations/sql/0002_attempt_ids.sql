@@ -0,0 +1 @@
+ALTER TABLE messages ADD COLUMN attempt_id uuid NULL;
@@ -0,0 +1 @@
+CREATE INDEX ON archived_messages (queue, finalized_at);
@@ -0,0 +1,5 @@
+CREATE TABLE queue_token_buckets (
+    queue varchar(255) PRIMARY KEY,
+    tokens double precision NOT NULL,
+    updated_at timestamptz NOT NULL
+);
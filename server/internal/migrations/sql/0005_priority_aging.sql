@@ -0,0 +1 @@
+ALTER TABLE messages ADD COLUMN aged_at timestamptz NULL;
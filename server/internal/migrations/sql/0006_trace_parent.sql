@@ -0,0 +1 @@
+ALTER TABLE messages ADD COLUMN trace_parent varchar(55) NULL;
@@ -0,0 +1,9 @@
+-- only messages with headers have a row here
+CREATE TABLE message_headers (
+    msg_id uuid PRIMARY KEY,
+    headers jsonb NOT NULL
+);
+
+-- messages archived before headers existed have none
+ALTER TABLE archived_messages ADD COLUMN headers jsonb NOT NULL DEFAULT '{}';
+ALTER TABLE archived_messages ALTER COLUMN headers DROP DEFAULT;
@@ -0,0 +1,10 @@
+-- a dedup key outlives its message until the dedup window expires
+CREATE TABLE message_dedup_keys (
+    queue varchar(255) NOT NULL,
+    dedup_key varchar(255) NOT NULL,
+    msg_id uuid NOT NULL,
+    expires_at timestamptz NOT NULL,
+    PRIMARY KEY (queue, dedup_key)
+);
+
+CREATE INDEX ON message_dedup_keys (expires_at);
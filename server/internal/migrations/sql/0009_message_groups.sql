@@ -0,0 +1,3 @@
+ALTER TABLE messages ADD COLUMN group_key varchar(255) NULL;
+
+CREATE INDEX ON messages (queue, group_key, created_at) WHERE group_key IS NOT NULL;
@@ -0,0 +1,5 @@
+-- a queue is paused while it has a row here, consumers get no messages from it
+CREATE TABLE queue_pauses (
+    queue varchar(255) PRIMARY KEY,
+    paused_at timestamptz NOT NULL
+);
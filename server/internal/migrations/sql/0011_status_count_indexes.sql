@@ -0,0 +1,12 @@
+-- every status has a partial index covering the queue, so that stats and metrics count messages
+-- per queue with index-only scans instead of scanning the whole table
+CREATE INDEX ON messages (queue) WHERE status = 'PREPARED';
+
+DROP INDEX messages_status_delayed_until_idx;
+CREATE INDEX ON messages (status, delayed_until) INCLUDE (queue) WHERE status = 'DELAYED';
+
+DROP INDEX messages_status_timeout_at_idx;
+CREATE INDEX ON messages (status, timeout_at) INCLUDE (queue) WHERE status = 'PROCESSING';
+
+DROP INDEX messages_status_finalized_at_idx;
+CREATE INDEX ON messages (status, finalized_at) INCLUDE (queue) WHERE status IN ('DELIVERED', 'DROPPED');
@@ -0,0 +1,89 @@
+-- SQLite has no uuid, enum or jsonb types: ids and statuses are text and json is stored as text.
+-- Timestamps are declared as TIMESTAMP, so the driver reads them back as time values.
+
+CREATE TABLE messages (
+    id text PRIMARY KEY,
+    queue varchar(255) NOT NULL,
+    group_key varchar(255) NULL,
+    created_at timestamp NOT NULL,
+    finalized_at timestamp NULL,
+    status text NOT NULL CHECK (status IN ('PREPARED', 'AVAILABLE', 'PROCESSING', 'DELAYED', 'DELIVERED', 'DROPPED')),
+    status_changed_at timestamp NOT NULL,
+    delayed_until timestamp NULL,
+    timeout_at timestamp NULL,
+    attempt_id text NULL,
+    priority smallint NOT NULL,
+    aged_at timestamp NULL,
+    retries int NOT NULL,
+    generation int NOT NULL,
+    trace_parent varchar(55) NULL,
+    version int NOT NULL
+);
+
+CREATE INDEX messages_prepared_idx ON messages (queue) WHERE status = 'PREPARED';
+CREATE INDEX messages_available_idx ON messages (queue, status, priority DESC, status_changed_at ASC) WHERE status = 'AVAILABLE';
+CREATE INDEX messages_delayed_idx ON messages (status, delayed_until, queue) WHERE status = 'DELAYED';
+CREATE INDEX messages_processing_idx ON messages (status, timeout_at, queue) WHERE status = 'PROCESSING';
+CREATE INDEX messages_finalized_idx ON messages (status, finalized_at, queue) WHERE status IN ('DELIVERED', 'DROPPED');
+CREATE INDEX messages_created_at_idx ON messages (created_at);
+CREATE INDEX messages_group_idx ON messages (queue, group_key, created_at) WHERE group_key IS NOT NULL;
+
+CREATE TABLE message_payloads (
+    msg_id text PRIMARY KEY,
+    payload text NOT NULL
+);
+
+-- only messages with headers have a row here
+CREATE TABLE message_headers (
+    msg_id text PRIMARY KEY,
+    headers text NOT NULL
+);
+
+-- a dedup key outlives its message until the dedup window expires
+CREATE TABLE message_dedup_keys (
+    queue varchar(255) NOT NULL,
+    dedup_key varchar(255) NOT NULL,
+    msg_id text NOT NULL,
+    expires_at timestamp NOT NULL,
+    PRIMARY KEY (queue, dedup_key)
+);
+
+CREATE INDEX message_dedup_keys_expires_at_idx ON message_dedup_keys (expires_at);
+
+CREATE TABLE message_history (
+    msg_id text NOT NULL,
+    generation int NOT NULL,
+    queue varchar(255) NOT NULL,
+    redirected_at timestamp NOT NULL,
+    priority smallint NOT NULL,
+    retries int NOT NULL,
+    PRIMARY KEY (msg_id, generation)
+);
+
+CREATE TABLE archived_messages (
+    id text PRIMARY KEY,
+    queue varchar(255) NOT NULL,
+    created_at timestamp NOT NULL,
+    finalized_at timestamp NOT NULL,
+    status text NOT NULL,
+    priority smallint NOT NULL,
+    retries int NOT NULL,
+    generation int NOT NULL,
+    payload text NOT NULL,
+    headers text NOT NULL,
+    history text NOT NULL
+);
+
+CREATE INDEX archived_messages_queue_finalized_at_idx ON archived_messages (queue, finalized_at);
+
+CREATE TABLE queue_token_buckets (
+    queue varchar(255) PRIMARY KEY,
+    tokens double precision NOT NULL,
+    updated_at timestamp NOT NULL
+);
+
+-- a queue is paused while it has a row here, consumers get no messages from it
+CREATE TABLE queue_pauses (
+    queue varchar(255) PRIMARY KEY,
+    paused_at timestamp NOT NULL
+);
@@ -42,6 +42,18 @@ func (p *Poller) WaitForNextAttempt(ctx context.Context) {
 	}
 }
 
+// WaitForThrottle sleeps while the queue is rate limited,
+// new messages don't matter here, so availability events are ignored.
+func (p *Poller) WaitForThrottle(ctx context.Context, wait time.Duration) {
+	select {
+	case <-ctx.Done():
+		p.timedOut = true
+	case <-p.pollTimeout:
+		p.timedOut = true
+	case <-time.After(wait):
+	}
+}
+
 func (p *Poller) IsTimedOut() bool {
 	return p.timedOut
 }
@@ -2,9 +2,13 @@ package routes
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"net/http"
 
+	"github.com/google/uuid"
+
+	"server/internal/apierrors"
 	"server/internal/routes/base"
 	"server/internal/usecases"
 	"server/pkg/httpmodels"
@@ -35,14 +39,23 @@ func (a *AckMessages) handler(
 ) (*httpmodels.OkResponse, *httpmodels.Error) {
 	var ackParams []usecases.AckParams
 	for _, param := range req {
+		attemptID, err := uuid.Parse(param.AttemptID)
+		if err != nil {
+			return nil, httpmodels.NewError(
+				httpmodels.ErrorCodeRequestInvalid,
+				fmt.Sprintf("uuid.Parse(%s): %v", param.AttemptID, err),
+			)
+		}
+
 		ackParams = append(ackParams, usecases.AckParams{
-			ID:      param.ID,
-			Release: param.Release,
+			ID:        param.ID,
+			AttemptID: attemptID,
+			Release:   param.Release,
 		})
 	}
 
 	if err := a.useCase.Do(ctx, ackParams); err != nil {
-		return nil, base.ExtractKnownErrors(err)
+		return nil, apierrors.ExtractKnownErrors(err)
 	}
 
 	return &httpmodels.OkResponse{Ok: true}, nil
@@ -0,0 +1,51 @@
+package base
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"server/internal/auth"
+	"server/pkg/httpmodels"
+)
+
+const bearerPrefix = "Bearer "
+
+type AuthMiddleware struct {
+	logger        *slog.Logger
+	authenticator *auth.Authenticator
+	next          http.Handler
+}
+
+// NewAuthMiddleware rejects requests without a valid API key and attaches
+// the authenticated principal to the request context for usecase-level authorization.
+func NewAuthMiddleware(
+	logger *slog.Logger,
+	authenticator *auth.Authenticator,
+	next http.Handler,
+) *AuthMiddleware {
+	return &AuthMiddleware{
+		logger:        logger,
+		authenticator: authenticator,
+		next:          next,
+	}
+}
+
+func (m *AuthMiddleware) ServeHTTP(writer http.ResponseWriter, req *http.Request) {
+	header := req.Header.Get("Authorization")
+	if !strings.HasPrefix(header, bearerPrefix) {
+		writeError(m.logger, writer, httpmodels.NewError(
+			httpmodels.ErrorCodeUnauthorized,
+			"bearer api key expected in Authorization header",
+		))
+		return
+	}
+
+	principal, ok := m.authenticator.Authenticate(strings.TrimPrefix(header, bearerPrefix))
+	if !ok {
+		writeError(m.logger, writer, httpmodels.NewError(httpmodels.ErrorCodeUnauthorized, "invalid api key"))
+		return
+	}
+
+	m.next.ServeHTTP(writer, req.WithContext(auth.WithPrincipal(req.Context(), principal)))
+}
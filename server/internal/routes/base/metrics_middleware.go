@@ -0,0 +1,56 @@
+package base
+
+import (
+	"net/http"
+	"time"
+
+	"server/internal/metrics"
+)
+
+type MetricsMiddleware struct {
+	metrics *metrics.Metrics
+	mux     *http.ServeMux
+	next    http.Handler
+}
+
+// NewMetricsMiddleware measures latency of requests to next, labeling them with the route
+// pattern that mux resolves for the request, so unknown paths don't blow up the label cardinality.
+func NewMetricsMiddleware(
+	metrics *metrics.Metrics,
+	mux *http.ServeMux,
+	next http.Handler,
+) *MetricsMiddleware {
+	return &MetricsMiddleware{
+		metrics: metrics,
+		mux:     mux,
+		next:    next,
+	}
+}
+
+func (m *MetricsMiddleware) ServeHTTP(writer http.ResponseWriter, req *http.Request) {
+	startedAt := time.Now()
+
+	recorder := &statusRecorder{ResponseWriter: writer, statusCode: http.StatusOK}
+	m.next.ServeHTTP(recorder, req)
+
+	_, route := m.mux.Handler(req)
+	if route == "" {
+		route = "unmatched"
+	}
+
+	m.metrics.ObserveHTTPRequest(route, recorder.statusCode, time.Since(startedAt))
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (r *statusRecorder) WriteHeader(statusCode int) {
+	r.statusCode = statusCode
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
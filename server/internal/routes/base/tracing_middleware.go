@@ -0,0 +1,54 @@
+package base
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"server/internal/tracing"
+)
+
+type TracingMiddleware struct {
+	tracer *tracing.Tracer
+	mux    *http.ServeMux
+	next   http.Handler
+}
+
+// NewTracingMiddleware wraps requests to next in server spans named after the route pattern
+// that mux resolves for the request. The trace is continued from the traceparent header if present.
+func NewTracingMiddleware(
+	tracer *tracing.Tracer,
+	mux *http.ServeMux,
+	next http.Handler,
+) *TracingMiddleware {
+	return &TracingMiddleware{
+		tracer: tracer,
+		mux:    mux,
+		next:   next,
+	}
+}
+
+func (m *TracingMiddleware) ServeHTTP(writer http.ResponseWriter, req *http.Request) {
+	_, route := m.mux.Handler(req)
+	if route == "" {
+		route = "unmatched"
+	}
+
+	ctx := tracing.Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+	ctx, span := m.tracer.Start(ctx, route, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(
+		attribute.String("http.request.method", req.Method),
+		attribute.String("http.route", route),
+	))
+	defer span.End()
+
+	recorder := &statusRecorder{ResponseWriter: writer, statusCode: http.StatusOK}
+	m.next.ServeHTTP(recorder, req.WithContext(ctx))
+
+	span.SetAttributes(attribute.Int("http.response.status_code", recorder.statusCode))
+	if recorder.statusCode >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, http.StatusText(recorder.statusCode))
+	}
+}
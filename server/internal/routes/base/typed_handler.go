@@ -36,7 +36,7 @@ func NewTypedHandler[TI ValidatableDTO, TO any](
 func (a *TypedHandler[TI, TO]) ServeHTTP(writer http.ResponseWriter, req *http.Request) {
 	respDTO, err := a.handleRequest(req)
 	if err != nil {
-		a.writeError(writer, err)
+		writeError(a.logger, writer, err)
 		return
 	}
 	a.writeSuccess(writer, respDTO)
@@ -82,10 +82,19 @@ func (a *TypedHandler[TI, TO]) handleRequest(req *http.Request) (TO, *httpmodels
 	return a.handlerFunc(req.Context(), reqDTO)
 }
 
-func (a *TypedHandler[TI, TO]) writeError(writer http.ResponseWriter, apiErr *httpmodels.Error) {
+func (a *TypedHandler[TI, TO]) writeSuccess(writer http.ResponseWriter, respDTO TO) {
+	writer.Header().Add("Content-Type", "application/json")
+
+	err := json.NewEncoder(writer).Encode(respDTO)
+	if err != nil {
+		a.logger.Error("json encode of success response failed", "error", err)
+	}
+}
+
+func writeError(logger *slog.Logger, writer http.ResponseWriter, apiErr *httpmodels.Error) {
 	statusCode := MapErrorCodeToStatusCode(apiErr.Code())
 	if statusCode >= http.StatusInternalServerError {
-		a.logger.Error("request failed", "error", apiErr.Error())
+		logger.Error("request failed", "error", apiErr.Error())
 	}
 
 	writer.Header().Add("Content-Type", "application/json")
@@ -95,15 +104,6 @@ func (a *TypedHandler[TI, TO]) writeError(writer http.ResponseWriter, apiErr *ht
 		Error: apiErr,
 	})
 	if err != nil {
-		a.logger.Error("json encode of error response failed", "error", err)
-	}
-}
-
-func (a *TypedHandler[TI, TO]) writeSuccess(writer http.ResponseWriter, respDTO TO) {
-	writer.Header().Add("Content-Type", "application/json")
-
-	err := json.NewEncoder(writer).Encode(respDTO)
-	if err != nil {
-		a.logger.Error("json encode of success response failed", "error", err)
+		logger.Error("json encode of error response failed", "error", err)
 	}
 }
@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"net/http"
 
+	"server/internal/apierrors"
 	"server/internal/routes/base"
 	"server/internal/usecases"
 	"server/pkg/httpmodels"
@@ -35,37 +36,40 @@ func (a *CheckMessages) handler(
 ) (httpmodels.CheckResponse, *httpmodels.Error) {
 	result, err := a.useCase.Do(ctx, req)
 	if err != nil {
-		return nil, base.ExtractKnownErrors(err)
+		return nil, apierrors.ExtractKnownErrors(err)
 	}
 
 	response := make([]httpmodels.Message, 0, len(result))
-
 	for _, msg := range result {
-		history := make([]httpmodels.MessageChapter, 0, len(msg.History))
+		response = append(response, mapCheckMsgResult(msg))
+	}
 
-		for _, chap := range msg.History {
-			history = append(history, httpmodels.MessageChapter{
-				Generation:   chap.Generation,
-				Queue:        chap.Queue.String(),
-				RedirectedAt: chap.RedirectedAt,
-				Priority:     chap.Priority,
-				Retries:      chap.Retries,
-			})
-		}
+	return response, nil
+}
 
-		response = append(response, httpmodels.Message{
-			ID:          msg.ID,
-			Queue:       msg.Queue.String(),
-			CreatedAt:   msg.CreatedAt,
-			FinalizedAt: msg.FinalizedAt,
-			Status:      httpmodels.MessageStatus(msg.Status),
-			Priority:    msg.Priority,
-			Retries:     msg.Retries,
-			Generation:  msg.Generation,
-			History:     history,
-			Payload:     msg.Payload,
+func mapCheckMsgResult(msg usecases.CheckMsgResult) httpmodels.Message {
+	history := make([]httpmodels.MessageChapter, 0, len(msg.History))
+	for _, chap := range msg.History {
+		history = append(history, httpmodels.MessageChapter{
+			Generation:   chap.Generation,
+			Queue:        chap.Queue.String(),
+			RedirectedAt: chap.RedirectedAt,
+			Priority:     chap.Priority,
+			Retries:      chap.Retries,
 		})
 	}
 
-	return response, nil
+	return httpmodels.Message{
+		ID:          msg.ID,
+		Queue:       msg.Queue.String(),
+		CreatedAt:   msg.CreatedAt,
+		FinalizedAt: msg.FinalizedAt,
+		Status:      httpmodels.MessageStatus(msg.Status),
+		Priority:    msg.Priority,
+		Retries:     msg.Retries,
+		Generation:  msg.Generation,
+		History:     history,
+		Payload:     msg.Payload,
+		Headers:     msg.Headers,
+	}
 }
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"time"
 
+	"server/internal/apierrors"
 	"server/internal/domain"
 	"server/internal/routes/base"
 	"server/internal/usecases"
@@ -52,14 +53,17 @@ func (a *ConsumeMessages) handler(
 
 	messages, err := a.useCase.Do(ctx, queue, limit, poll)
 	if err != nil {
-		return nil, base.ExtractKnownErrors(err)
+		return nil, apierrors.ExtractKnownErrors(err)
 	}
 
 	resp := make([]httpmodels.ConsumeResponseItem, 0, len(messages))
 	for _, msg := range messages {
 		resp = append(resp, httpmodels.ConsumeResponseItem{
-			ID:      msg.ID,
-			Payload: msg.Payload,
+			ID:          msg.ID,
+			AttemptID:   msg.AttemptID,
+			Payload:     msg.Payload,
+			Headers:     msg.Headers,
+			TraceParent: msg.TraceParent,
 		})
 	}
 
@@ -0,0 +1,70 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"server/internal/apierrors"
+	"server/internal/routes/base"
+	"server/internal/usecases"
+	"server/internal/utils/opt"
+	"server/pkg/httpmodels"
+)
+
+type ExtendMessages struct {
+	logger  *slog.Logger
+	useCase *usecases.ExtendMessages
+}
+
+func NewExtendMessages(
+	logger *slog.Logger,
+	useCase *usecases.ExtendMessages,
+) *ExtendMessages {
+	return &ExtendMessages{
+		logger:  logger,
+		useCase: useCase,
+	}
+}
+
+func (a *ExtendMessages) Mount(srv *http.ServeMux) {
+	srv.Handle("/messages/extend", base.NewTypedHandler(a.logger, a.handler))
+}
+
+func (a *ExtendMessages) handler(
+	ctx context.Context,
+	req httpmodels.ExtendRequest,
+) (*httpmodels.OkResponse, *httpmodels.Error) {
+	var extendParams []usecases.ExtendParams
+
+	for _, param := range req {
+		attemptID, err := uuid.Parse(param.AttemptID)
+		if err != nil {
+			return nil, httpmodels.NewError(
+				httpmodels.ErrorCodeRequestInvalid,
+				fmt.Sprintf("uuid.Parse(%s): %v", param.AttemptID, err),
+			)
+		}
+
+		duration := opt.None[time.Duration]()
+		if param.Duration != nil {
+			duration = opt.Some(time.Duration(*param.Duration) * time.Second)
+		}
+
+		extendParams = append(extendParams, usecases.ExtendParams{
+			ID:        param.ID,
+			AttemptID: attemptID,
+			Duration:  duration,
+		})
+	}
+
+	if err := a.useCase.Do(ctx, extendParams); err != nil {
+		return nil, apierrors.ExtractKnownErrors(err)
+	}
+
+	return &httpmodels.OkResponse{Ok: true}, nil
+}
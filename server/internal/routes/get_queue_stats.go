@@ -0,0 +1,85 @@
+package routes
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"server/internal/apierrors"
+	"server/internal/domain"
+	"server/internal/routes/base"
+	"server/internal/usecases"
+	"server/pkg/httpmodels"
+)
+
+type GetQueueStats struct {
+	logger  *slog.Logger
+	useCase *usecases.GetQueueStats
+}
+
+func NewGetQueueStats(
+	logger *slog.Logger,
+	useCase *usecases.GetQueueStats,
+) *GetQueueStats {
+	return &GetQueueStats{
+		logger:  logger,
+		useCase: useCase,
+	}
+}
+
+func (a *GetQueueStats) Mount(srv *http.ServeMux) {
+	srv.Handle("/queues/stats", base.NewTypedHandler(a.logger, a.handler))
+}
+
+func (a *GetQueueStats) handler(
+	ctx context.Context,
+	req httpmodels.StatsRequest,
+) (*httpmodels.StatsResponse, *httpmodels.Error) {
+	queues := make([]domain.QueueName, 0, len(req.Queues))
+	for _, queueStr := range req.Queues {
+		queue, err := domain.NewQueueName(queueStr)
+		if err != nil {
+			return nil, httpmodels.NewError(httpmodels.ErrorCodeRequestInvalid, err.Error())
+		}
+		queues = append(queues, queue)
+	}
+
+	result, err := a.useCase.Do(ctx, usecases.GetQueueStatsParams{Queues: queues})
+	if err != nil {
+		return nil, apierrors.ExtractKnownErrors(err)
+	}
+
+	stats := make([]httpmodels.QueueStats, 0, len(result.Queues))
+	for _, queueStats := range result.Queues {
+		stats = append(stats, mapQueueStats(queueStats))
+	}
+
+	return &httpmodels.StatsResponse{
+		CollectedAt: result.CollectedAt,
+		Queues:      stats,
+	}, nil
+}
+
+func mapQueueStats(stats usecases.QueueStats) httpmodels.QueueStats {
+	messages := make(map[httpmodels.MessageStatus]int, len(stats.Counts))
+	for status, count := range stats.Counts {
+		messages[httpmodels.MessageStatus(status)] = count
+	}
+
+	dto := httpmodels.QueueStats{
+		Queue:          stats.Queue.String(),
+		Messages:       messages,
+		DelayedDueSoon: stats.DelayedDueSoon,
+	}
+
+	if age, isSet := stats.OldestAvailableAge.Value(); isSet {
+		seconds := int(age.Seconds())
+		dto.OldestAvailableAge = &seconds
+	}
+
+	if size, isSet := stats.DLQSize.Value(); isSet {
+		dto.DLQSize = &size
+	}
+
+	return dto
+}
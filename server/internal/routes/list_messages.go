@@ -0,0 +1,74 @@
+package routes
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"server/internal/apierrors"
+	"server/internal/domain"
+	"server/internal/routes/base"
+	"server/internal/usecases"
+	"server/internal/utils/opt"
+	"server/pkg/httpmodels"
+)
+
+type ListMessages struct {
+	logger  *slog.Logger
+	useCase *usecases.ListMessages
+}
+
+func NewListMessages(
+	logger *slog.Logger,
+	useCase *usecases.ListMessages,
+) *ListMessages {
+	return &ListMessages{
+		logger:  logger,
+		useCase: useCase,
+	}
+}
+
+func (a *ListMessages) Mount(srv *http.ServeMux) {
+	srv.Handle("/messages/list", base.NewTypedHandler(a.logger, a.handler))
+}
+
+func (a *ListMessages) handler(
+	ctx context.Context,
+	req httpmodels.ListRequest,
+) (*httpmodels.ListResponse, *httpmodels.Error) {
+	queue, err := domain.NewQueueName(req.Queue)
+	if err != nil {
+		return nil, httpmodels.NewError(httpmodels.ErrorCodeRequestInvalid, err.Error())
+	}
+
+	statuses := make([]domain.MessageStatus, 0, len(req.Statuses))
+	for _, status := range req.Statuses {
+		statuses = append(statuses, domain.MessageStatus(status))
+	}
+
+	result, err := a.useCase.Do(ctx, usecases.ListMessagesParams{
+		Queue:       queue,
+		Statuses:    statuses,
+		MinPriority: req.MinPriority,
+		MaxPriority: req.MaxPriority,
+		CreatedFrom: req.CreatedFrom,
+		CreatedTo:   req.CreatedTo,
+		MinRetries:  req.MinRetries,
+		MaxRetries:  req.MaxRetries,
+		Cursor:      req.Cursor,
+		Limit:       opt.FromRef(req.Limit),
+	})
+	if err != nil {
+		return nil, apierrors.ExtractKnownErrors(err)
+	}
+
+	messages := make([]httpmodels.Message, 0, len(result.Messages))
+	for _, msg := range result.Messages {
+		messages = append(messages, mapCheckMsgResult(msg))
+	}
+
+	return &httpmodels.ListResponse{
+		Messages:   messages,
+		NextCursor: result.NextCursor,
+	}, nil
+}
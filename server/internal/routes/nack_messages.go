@@ -2,9 +2,13 @@ package routes
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"net/http"
 
+	"github.com/google/uuid"
+
+	"server/internal/apierrors"
 	"server/internal/routes/base"
 	"server/internal/usecases"
 	"server/pkg/httpmodels"
@@ -41,14 +45,23 @@ func (a *NackMessages) handler(
 			redeliver = *param.Redeliver
 		}
 
+		attemptID, err := uuid.Parse(param.AttemptID)
+		if err != nil {
+			return nil, httpmodels.NewError(
+				httpmodels.ErrorCodeRequestInvalid,
+				fmt.Sprintf("uuid.Parse(%s): %v", param.AttemptID, err),
+			)
+		}
+
 		nackParams = append(nackParams, usecases.NackParams{
 			ID:        param.ID,
+			AttemptID: attemptID,
 			Redeliver: redeliver,
 		})
 	}
 
 	if err := a.useCase.Do(ctx, nackParams); err != nil {
-		return nil, base.ExtractKnownErrors(err)
+		return nil, apierrors.ExtractKnownErrors(err)
 	}
 
 	return &httpmodels.OkResponse{Ok: true}, nil
@@ -0,0 +1,48 @@
+package routes
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"server/internal/apierrors"
+	"server/internal/domain"
+	"server/internal/routes/base"
+	"server/internal/usecases"
+	"server/pkg/httpmodels"
+)
+
+type PauseQueue struct {
+	logger  *slog.Logger
+	useCase *usecases.PauseQueue
+}
+
+func NewPauseQueue(
+	logger *slog.Logger,
+	useCase *usecases.PauseQueue,
+) *PauseQueue {
+	return &PauseQueue{
+		logger:  logger,
+		useCase: useCase,
+	}
+}
+
+func (a *PauseQueue) Mount(srv *http.ServeMux) {
+	srv.Handle("/queues/pause", base.NewTypedHandler(a.logger, a.handler))
+}
+
+func (a *PauseQueue) handler(
+	ctx context.Context,
+	req httpmodels.PauseRequest,
+) (*httpmodels.OkResponse, *httpmodels.Error) {
+	queue, err := domain.NewQueueName(req.Queue)
+	if err != nil {
+		return nil, httpmodels.NewError(httpmodels.ErrorCodeRequestInvalid, err.Error())
+	}
+
+	if err := a.useCase.Do(ctx, queue); err != nil {
+		return nil, apierrors.ExtractKnownErrors(err)
+	}
+
+	return &httpmodels.OkResponse{Ok: true}, nil
+}
@@ -5,9 +5,12 @@ import (
 	"log/slog"
 	"net/http"
 
+	"server/internal/apibatch"
+	"server/internal/apierrors"
 	"server/internal/domain"
 	"server/internal/routes/base"
 	"server/internal/usecases"
+	"server/internal/utils/opt"
 	"server/pkg/httpmodels"
 )
 
@@ -50,41 +53,72 @@ func (a *PublishMessages) handler(
 	req httpmodels.PublishRequest,
 	autoRelease bool,
 ) (*httpmodels.PublishResponse, *httpmodels.Error) {
-	mappedItems, mapItemErrors := base.MapBatchRequestItems(req, a.mapRequestItem)
+	mappedItems, mapItemErrors := apibatch.MapBatchRequestItems(req, a.mapRequestItem)
 
 	results, err := a.useCase.Do(ctx, mappedItems, autoRelease)
 	if err != nil {
-		return nil, base.ExtractKnownErrors(err)
+		return nil, apierrors.ExtractKnownErrors(err)
 	}
 
 	return &httpmodels.PublishResponse{
-		Results: base.MapBatchResults(mapItemErrors, results, a.mapResult),
+		Results: apibatch.MapBatchResults(mapItemErrors, results, a.mapResult),
 	}, nil
 }
 
 func (a *PublishMessages) mapRequestItem(
 	params httpmodels.PublishRequestItem,
 ) (usecases.NewMessageParams, *httpmodels.Error) {
-	priority := 100
+	queue, err := domain.NewQueueName(params.Queue)
+	if err != nil {
+		return usecases.NewMessageParams{}, httpmodels.NewError(httpmodels.ErrorCodeRequestInvalid, err.Error())
+	}
+
+	priority := opt.None[domain.Priority]()
 	if params.Priority != nil {
-		priority = *params.Priority
+		tmp, err := domain.NewPriority(*params.Priority)
+		if err != nil {
+			return usecases.NewMessageParams{}, httpmodels.NewError(httpmodels.ErrorCodeRequestInvalid, err.Error())
+		}
+		priority = opt.Some(tmp)
 	}
 
-	queue, err := domain.NewQueueName(params.Queue)
+	headers, err := domain.NewHeaders(params.Headers)
 	if err != nil {
 		return usecases.NewMessageParams{}, httpmodels.NewError(httpmodels.ErrorCodeRequestInvalid, err.Error())
 	}
 
+	var group *domain.MessageGroup
+	if params.Group != nil {
+		tmp, err := domain.NewMessageGroup(*params.Group)
+		if err != nil {
+			return usecases.NewMessageParams{}, httpmodels.NewError(httpmodels.ErrorCodeRequestInvalid, err.Error())
+		}
+		group = &tmp
+	}
+
+	dedupKey := opt.None[domain.DedupKey]()
+	if params.DedupKey != nil {
+		tmp, err := domain.NewDedupKey(*params.DedupKey)
+		if err != nil {
+			return usecases.NewMessageParams{}, httpmodels.NewError(httpmodels.ErrorCodeRequestInvalid, err.Error())
+		}
+		dedupKey = opt.Some(tmp)
+	}
+
 	return usecases.NewMessageParams{
 		Queue:    queue,
 		Payload:  params.Payload,
+		Headers:  headers,
+		Group:    group,
 		Priority: priority,
 		StartAt:  params.StartAt,
+		DedupKey: dedupKey,
 	}, nil
 }
 
 func (a *PublishMessages) mapResult(result *usecases.NewMessageResult) *httpmodels.PublishedMessage {
 	return &httpmodels.PublishedMessage{
-		ID: result.ID,
+		ID:        result.ID,
+		Duplicate: result.Duplicate,
 	}
 }
@@ -0,0 +1,58 @@
+package routes
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"server/internal/apierrors"
+	"server/internal/domain"
+	"server/internal/routes/base"
+	"server/internal/usecases"
+	"server/pkg/httpmodels"
+)
+
+type PurgeQueue struct {
+	logger  *slog.Logger
+	useCase *usecases.PurgeQueue
+}
+
+func NewPurgeQueue(
+	logger *slog.Logger,
+	useCase *usecases.PurgeQueue,
+) *PurgeQueue {
+	return &PurgeQueue{
+		logger:  logger,
+		useCase: useCase,
+	}
+}
+
+func (a *PurgeQueue) Mount(srv *http.ServeMux) {
+	srv.Handle("/queues/purge", base.NewTypedHandler(a.logger, a.handler))
+}
+
+func (a *PurgeQueue) handler(
+	ctx context.Context,
+	req httpmodels.PurgeRequest,
+) (*httpmodels.PurgeResponse, *httpmodels.Error) {
+	queue, err := domain.NewQueueName(req.Queue)
+	if err != nil {
+		return nil, httpmodels.NewError(httpmodels.ErrorCodeRequestInvalid, err.Error())
+	}
+
+	statuses := make([]domain.MessageStatus, 0, len(req.Statuses))
+	for _, status := range req.Statuses {
+		statuses = append(statuses, domain.MessageStatus(status))
+	}
+
+	purged, err := a.useCase.Do(ctx, usecases.PurgeQueueParams{
+		Queue:    queue,
+		Statuses: statuses,
+		Archive:  req.Archive,
+	})
+	if err != nil {
+		return nil, apierrors.ExtractKnownErrors(err)
+	}
+
+	return &httpmodels.PurgeResponse{Purged: purged}, nil
+}
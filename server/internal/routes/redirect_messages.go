@@ -6,6 +6,9 @@ import (
 	"log/slog"
 	"net/http"
 
+	"github.com/google/uuid"
+
+	"server/internal/apierrors"
 	"server/internal/domain"
 	"server/internal/routes/base"
 	"server/internal/usecases"
@@ -46,14 +49,23 @@ func (a *RedirectMessages) handler(
 			)
 		}
 
+		attemptID, err := uuid.Parse(param.AttemptID)
+		if err != nil {
+			return nil, httpmodels.NewError(
+				httpmodels.ErrorCodeRequestInvalid,
+				fmt.Sprintf("uuid.Parse(%s): %v", param.AttemptID, err),
+			)
+		}
+
 		redirectParams = append(redirectParams, usecases.RedirectParams{
 			ID:          param.ID,
+			AttemptID:   attemptID,
 			Destination: destination,
 		})
 	}
 
 	if err := a.useCase.Do(ctx, redirectParams); err != nil {
-		return nil, base.ExtractKnownErrors(err)
+		return nil, apierrors.ExtractKnownErrors(err)
 	}
 
 	return &httpmodels.OkResponse{Ok: true}, nil
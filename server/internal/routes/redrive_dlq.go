@@ -0,0 +1,60 @@
+package routes
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"server/internal/apierrors"
+	"server/internal/domain"
+	"server/internal/routes/base"
+	"server/internal/usecases"
+	"server/internal/utils/opt"
+	"server/pkg/httpmodels"
+)
+
+type RedriveDLQ struct {
+	logger  *slog.Logger
+	useCase *usecases.RedriveDLQ
+}
+
+func NewRedriveDLQ(
+	logger *slog.Logger,
+	useCase *usecases.RedriveDLQ,
+) *RedriveDLQ {
+	return &RedriveDLQ{
+		logger:  logger,
+		useCase: useCase,
+	}
+}
+
+func (a *RedriveDLQ) Mount(srv *http.ServeMux) {
+	srv.Handle("/messages/redrive", base.NewTypedHandler(a.logger, a.handler))
+}
+
+func (a *RedriveDLQ) handler(
+	ctx context.Context,
+	req httpmodels.RedriveRequest,
+) (*httpmodels.RedriveResponse, *httpmodels.Error) {
+	queue, err := domain.NewQueueName(req.Queue)
+	if err != nil {
+		return nil, httpmodels.NewError(httpmodels.ErrorCodeRequestInvalid, err.Error())
+	}
+
+	result, err := a.useCase.Do(ctx, usecases.RedriveDLQParams{
+		Queue:       queue,
+		MinPriority: req.MinPriority,
+		MaxPriority: req.MaxPriority,
+		CreatedFrom: req.CreatedFrom,
+		CreatedTo:   req.CreatedTo,
+		Limit:       opt.FromRef(req.Limit),
+	}, nil)
+	if err != nil {
+		return nil, apierrors.ExtractKnownErrors(err)
+	}
+
+	return &httpmodels.RedriveResponse{
+		Redriven: result.Redriven,
+		Skipped:  result.Skipped,
+	}, nil
+}
@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"net/http"
 
+	"server/internal/apierrors"
 	"server/internal/routes/base"
 	"server/internal/usecases"
 	"server/pkg/httpmodels"
@@ -34,7 +35,7 @@ func (a *ReleaseMessages) handler(
 	req httpmodels.ReleaseRequest,
 ) (*httpmodels.OkResponse, *httpmodels.Error) {
 	if err := a.useCase.Do(ctx, req); err != nil {
-		return nil, base.ExtractKnownErrors(err)
+		return nil, apierrors.ExtractKnownErrors(err)
 	}
 
 	return &httpmodels.OkResponse{Ok: true}, nil
@@ -0,0 +1,48 @@
+package routes
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"server/internal/apierrors"
+	"server/internal/domain"
+	"server/internal/routes/base"
+	"server/internal/usecases"
+	"server/pkg/httpmodels"
+)
+
+type ResumeQueue struct {
+	logger  *slog.Logger
+	useCase *usecases.ResumeQueue
+}
+
+func NewResumeQueue(
+	logger *slog.Logger,
+	useCase *usecases.ResumeQueue,
+) *ResumeQueue {
+	return &ResumeQueue{
+		logger:  logger,
+		useCase: useCase,
+	}
+}
+
+func (a *ResumeQueue) Mount(srv *http.ServeMux) {
+	srv.Handle("/queues/resume", base.NewTypedHandler(a.logger, a.handler))
+}
+
+func (a *ResumeQueue) handler(
+	ctx context.Context,
+	req httpmodels.ResumeRequest,
+) (*httpmodels.OkResponse, *httpmodels.Error) {
+	queue, err := domain.NewQueueName(req.Queue)
+	if err != nil {
+		return nil, httpmodels.NewError(httpmodels.ErrorCodeRequestInvalid, err.Error())
+	}
+
+	if err := a.useCase.Do(ctx, queue); err != nil {
+		return nil, apierrors.ExtractKnownErrors(err)
+	}
+
+	return &httpmodels.OkResponse{Ok: true}, nil
+}
@@ -2,9 +2,11 @@ package storage
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"server/internal/domain"
 	"server/internal/utils/dbutils"
@@ -30,11 +32,16 @@ func (r *ArchivedMsgRepository) Upsert(
 		return fmt.Errorf("json.Marshal: %w", err)
 	}
 
+	headersJSON, err := json.Marshal(msgDTO.Headers)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+
 	query := `
 		INSERT INTO archived_messages (
-			id, queue, created_at, finalized_at, status, priority, retries, generation, payload, history
+			id, queue, created_at, finalized_at, status, priority, retries, generation, payload, headers, history
    		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		ON CONFLICT (id) DO UPDATE SET
 		    queue = $2,
 		    created_at = $3,
@@ -44,7 +51,8 @@ func (r *ArchivedMsgRepository) Upsert(
 		    retries = $7,
 		    generation = $8,
 		    payload = $9,
-		    history = $10
+		    headers = $10,
+		    history = $11
     `
 	if _, err := conn.ExecContext(
 		ctx,
@@ -58,6 +66,7 @@ func (r *ArchivedMsgRepository) Upsert(
 		msgDTO.Retries,
 		msgDTO.Generation,
 		msgDTO.Payload,
+		headersJSON,
 		historyJSON,
 	); err != nil {
 		return err
@@ -72,11 +81,42 @@ func (r *ArchivedMsgRepository) GetByID(
 	id string,
 ) (*domain.ArchivedMsg, error) {
 	query := `
-		SELECT id, queue, created_at, finalized_at, status, priority, retries, generation, payload, history
+		SELECT id, queue, created_at, finalized_at, status, priority, retries, generation, payload, headers, history
 		FROM archived_messages
 		WHERE id = $1
 	`
-	rows, err := conn.QueryContext(ctx, query, id)
+	result, err := scanArchivedRows(conn.QueryContext(ctx, query, id))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result) == 0 {
+		return nil, ErrArchivedMsgNotFound
+	}
+
+	return result[0], nil
+}
+
+// List returns up to `limit` archived messages matching the filter.
+func (r *ArchivedMsgRepository) List(
+	ctx context.Context,
+	conn dbutils.Querier,
+	filter *MessageFilter,
+	limit int,
+) ([]*domain.ArchivedMsg, error) {
+	where, args := filter.toSQL("a")
+	query := fmt.Sprintf(`
+		SELECT id, queue, created_at, finalized_at, status, priority, retries, generation, payload, headers, history
+		FROM archived_messages a
+		WHERE %s
+		ORDER BY a.created_at ASC, a.id ASC
+		LIMIT %d
+	`, where, limit)
+
+	return scanArchivedRows(conn.QueryContext(ctx, query, args...))
+}
+
+func scanArchivedRows(rows *sql.Rows, err error) ([]*domain.ArchivedMsg, error) {
 	if err != nil {
 		return nil, err
 	}
@@ -87,7 +127,7 @@ func (r *ArchivedMsgRepository) GetByID(
 
 	for rows.Next() {
 		var msg domain.ArchivedMsgDTO
-		var historyJSON json.RawMessage
+		var headersJSON, historyJSON json.RawMessage
 
 		if err := rows.Scan(
 			&msg.ID,
@@ -99,11 +139,18 @@ func (r *ArchivedMsgRepository) GetByID(
 			&msg.Retries,
 			&msg.Generation,
 			&msg.Payload,
+			&headersJSON,
 			&historyJSON,
 		); err != nil {
 			return nil, err
 		}
 
+		localTimes(&msg.CreatedAt, &msg.FinalizedAt)
+
+		if err := json.Unmarshal(headersJSON, &msg.Headers); err != nil {
+			return nil, err
+		}
+
 		var historyDTO []domain.ArchivedChapterDTO
 		if err := json.Unmarshal(historyJSON, &historyDTO); err != nil {
 			return nil, err
@@ -117,11 +164,7 @@ func (r *ArchivedMsgRepository) GetByID(
 		return nil, err
 	}
 
-	if len(result) == 0 {
-		return nil, ErrArchivedMsgNotFound
-	}
-
-	return result[0], nil
+	return result, nil
 }
 
 func (r *ArchivedMsgRepository) Delete(
@@ -136,3 +179,34 @@ func (r *ArchivedMsgRepository) Delete(
 
 	return nil
 }
+
+// DeleteFinalizedBefore removes up to limit archived messages of the queue
+// finalized before the given time and returns the number of deleted rows.
+func (r *ArchivedMsgRepository) DeleteFinalizedBefore(
+	ctx context.Context,
+	conn dbutils.Querier,
+	queue domain.QueueName,
+	before time.Time,
+	limit int,
+) (int, error) {
+	query := `
+		DELETE FROM archived_messages
+		WHERE id IN (
+			SELECT id FROM archived_messages
+			WHERE queue = $1 AND finalized_at < $2
+			ORDER BY finalized_at ASC
+			LIMIT $3
+		)
+	`
+	result, err := conn.ExecContext(ctx, query, queue.String(), before, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("result.RowsAffected: %w", err)
+	}
+
+	return int(affected), nil
+}
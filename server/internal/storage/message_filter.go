@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"server/internal/domain"
+)
+
+// MessageFilter selects messages of a queue for listing, unset fields don't restrict the selection.
+// Messages are listed in the order of (created_at, id), which stays the same after archiving.
+type MessageFilter struct {
+	Queue       domain.QueueName
+	Statuses    []domain.MessageStatus
+	MinPriority *int
+	MaxPriority *int
+	CreatedFrom *time.Time // inclusive
+	CreatedTo   *time.Time // exclusive
+	MinRetries  *int
+	MaxRetries  *int
+	After       *MessageCursor // continues the listing after this message
+}
+
+// MessageCursor is the position of a message in the listing order.
+type MessageCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// IncludesStatus reports whether messages in any of the statuses can match the filter.
+func (f *MessageFilter) IncludesStatus(statuses ...domain.MessageStatus) bool {
+	if len(f.Statuses) == 0 {
+		return true
+	}
+
+	for _, status := range statuses {
+		if slices.Contains(f.Statuses, status) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// toSQL returns the WHERE conditions for columns of the given table alias and their arguments.
+func (f *MessageFilter) toSQL(alias string) (string, []any) {
+	var conditions []string
+	var args []any
+
+	addCondition := func(format string, values ...any) {
+		placeholders := make([]any, 0, len(values))
+		for _, value := range values {
+			args = append(args, value)
+			placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+		}
+		conditions = append(conditions, fmt.Sprintf(format, placeholders...))
+	}
+
+	addCondition(alias+".queue = %s", f.Queue.String())
+
+	if len(f.Statuses) > 0 {
+		placeholders := make([]string, 0, len(f.Statuses))
+		for _, status := range f.Statuses {
+			args = append(args, status)
+			placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+		}
+		conditions = append(conditions, fmt.Sprintf("%s.status IN (%s)", alias, strings.Join(placeholders, ", ")))
+	}
+
+	if f.MinPriority != nil {
+		addCondition(alias+".priority >= %s", *f.MinPriority)
+	}
+
+	if f.MaxPriority != nil {
+		addCondition(alias+".priority <= %s", *f.MaxPriority)
+	}
+
+	if f.CreatedFrom != nil {
+		addCondition(alias+".created_at >= %s", *f.CreatedFrom)
+	}
+
+	if f.CreatedTo != nil {
+		addCondition(alias+".created_at < %s", *f.CreatedTo)
+	}
+
+	if f.MinRetries != nil {
+		addCondition(alias+".retries >= %s", *f.MinRetries)
+	}
+
+	if f.MaxRetries != nil {
+		addCondition(alias+".retries <= %s", *f.MaxRetries)
+	}
+
+	if f.After != nil {
+		addCondition("("+alias+".created_at, "+alias+".id) > (%s, %s)", f.After.CreatedAt, f.After.ID)
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
@@ -3,10 +3,15 @@ package storage
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
+	"time"
+
+	"github.com/google/uuid"
 
 	"server/internal/domain"
 	"server/internal/utils/dbutils"
@@ -15,15 +20,26 @@ import (
 
 const selectAll = `
 	SELECT 
-		m.id, m.queue, m.created_at, m.finalized_at, m.status, m.status_changed_at,
-		m.delayed_until, m.timeout_at, m.priority, m.retries, m.generation ,m.version,
-		p.payload
+		m.id, m.queue, m.group_key, m.created_at, m.finalized_at, m.status, m.status_changed_at,
+		m.delayed_until, m.timeout_at, m.attempt_id, m.priority, m.aged_at, m.retries, m.generation, m.trace_parent, m.version,
+		p.payload, h.headers
 	FROM messages m
 	LEFT JOIN message_payloads p ON p.msg_id = m.id
+	LEFT JOIN message_headers h ON h.msg_id = m.id
 `
 
 var ErrMsgNotFound = errors.New("message not found")
 
+// DuplicateMsgError is returned on creating a message with a dedup key
+// that is already taken by another message of the queue.
+type DuplicateMsgError struct {
+	OriginalID uuid.UUID
+}
+
+func (e *DuplicateMsgError) Error() string {
+	return fmt.Sprintf("message %s has the same dedup key", e.OriginalID)
+}
+
 func scanRows(rows *sql.Rows) ([]*domain.MessageDTO, error) {
 	defer rows.Close()
 
@@ -31,25 +47,40 @@ func scanRows(rows *sql.Rows) ([]*domain.MessageDTO, error) {
 
 	for rows.Next() {
 		var dto domain.MessageDTO
+		var headersJSON []byte
 
 		if err := rows.Scan(
 			&dto.ID,
 			&dto.Queue,
+			&dto.Group,
 			&dto.CreatedAt,
 			&dto.FinalizedAt,
 			&dto.Status,
 			&dto.StatusChangedAt,
 			&dto.DelayedUntil,
 			&dto.TimeoutAt,
+			&dto.AttemptID,
 			&dto.Priority,
+			&dto.AgedAt,
 			&dto.Retries,
 			&dto.Generation,
+			&dto.TraceParent,
 			&dto.Version,
 			&dto.Payload,
+			&headersJSON,
 		); err != nil {
 			return nil, err
 		}
 
+		localTimes(&dto.CreatedAt, dto.FinalizedAt, &dto.StatusChangedAt, dto.DelayedUntil, dto.TimeoutAt, dto.AgedAt)
+
+		// messages without headers have no row in message_headers
+		if headersJSON != nil {
+			if err := json.Unmarshal(headersJSON, &dto.Headers); err != nil {
+				return nil, fmt.Errorf("json.Unmarshal: %w", err)
+			}
+		}
+
 		result = append(result, &dto)
 	}
 	if err := rows.Err(); err != nil {
@@ -60,14 +91,16 @@ func scanRows(rows *sql.Rows) ([]*domain.MessageDTO, error) {
 }
 
 type MessageRepository struct {
-	clock  timeutils.Clock
-	logger *slog.Logger
+	clock   timeutils.Clock
+	logger  *slog.Logger
+	dialect dbutils.Dialect
 }
 
-func NewMessageRepository(clock timeutils.Clock, logger *slog.Logger) *MessageRepository {
+func NewMessageRepository(clock timeutils.Clock, logger *slog.Logger, dialect dbutils.Dialect) *MessageRepository {
 	return &MessageRepository{
-		clock:  clock,
-		logger: logger,
+		clock:   clock,
+		logger:  logger,
+		dialect: dialect,
 	}
 }
 
@@ -122,18 +155,78 @@ func (r *MessageRepository) Save(
 	return nil
 }
 
+// SaveAll is Save for a batch: messages that already exist are updated with a single statement
+// and new history chapters are inserted with another one. Like Save, it fails if any message
+// was changed concurrently.
+func (r *MessageRepository) SaveAll(
+	ctx context.Context,
+	tx *sql.Tx,
+	msgs []*domain.Message,
+) error {
+	var updated []*domain.MessageDTO
+	var chapters []*domain.MessageChapterDTO
+
+	seen := make(map[uuid.UUID]struct{}, len(msgs))
+
+	for _, msg := range msgs {
+		msgDTO := msg.ToDTO()
+
+		// a message may come several times in a batch, it's the same object each time
+		if _, exist := seen[msgDTO.ID]; exist {
+			continue
+		}
+		seen[msgDTO.ID] = struct{}{}
+
+		if msgDTO.IsNew {
+			if err := r.create(ctx, tx, msgDTO); err != nil {
+				return err
+			}
+		} else {
+			updated = append(updated, msgDTO)
+		}
+
+		for _, chapter := range msgDTO.History {
+			if chapter.IsNew {
+				chapters = append(chapters, chapter)
+			}
+		}
+	}
+
+	if err := r.updateAll(ctx, tx, updated); err != nil {
+		return err
+	}
+
+	if err := r.createHistoryChapters(ctx, tx, chapters); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (r *MessageRepository) create(
 	ctx context.Context,
 	tx *sql.Tx,
 	msgDTO *domain.MessageDTO,
 ) error {
+	// the key is claimed first, so a duplicate doesn't write anything else
+	if msgDTO.DedupKey != nil {
+		originalID, err := r.claimDedupKey(ctx, tx, msgDTO)
+		if err != nil {
+			return fmt.Errorf("claimDedupKey: %w", err)
+		}
+
+		if originalID != msgDTO.ID {
+			return &DuplicateMsgError{OriginalID: originalID}
+		}
+	}
+
 	query := `
 		INSERT INTO messages (
-			id, queue, created_at, finalized_at, status, status_changed_at, 
-		    delayed_until, timeout_at, priority, retries, generation, version
+			id, queue, group_key, created_at, finalized_at, status, status_changed_at, 
+		    delayed_until, timeout_at, attempt_id, priority, aged_at, retries, generation, trace_parent, version
    		) VALUES (
-			$1, $2, $3, $4, $5, $6, 
-			$7, $8, $9, $10, $11, $12
+			$1, $2, $3, $4, $5, $6, $7, 
+			$8, $9, $10, $11, $12, $13, $14, $15, $16
 		)
     `
 	if _, err := tx.ExecContext(
@@ -141,15 +234,19 @@ func (r *MessageRepository) create(
 		query,
 		msgDTO.ID,
 		msgDTO.Queue,
+		msgDTO.Group,
 		msgDTO.CreatedAt,
 		msgDTO.FinalizedAt,
 		msgDTO.Status,
 		msgDTO.StatusChangedAt,
 		msgDTO.DelayedUntil,
 		msgDTO.TimeoutAt,
+		msgDTO.AttemptID,
 		msgDTO.Priority,
+		msgDTO.AgedAt,
 		msgDTO.Retries,
 		msgDTO.Generation,
+		msgDTO.TraceParent,
 		msgDTO.Version,
 	); err != nil {
 		return err
@@ -165,9 +262,92 @@ func (r *MessageRepository) create(
 		return err
 	}
 
+	// headers never change, so they are written only once
+	if len(msgDTO.Headers) > 0 {
+		headersJSON, err := json.Marshal(msgDTO.Headers)
+		if err != nil {
+			return fmt.Errorf("json.Marshal: %w", err)
+		}
+
+		query = `INSERT INTO message_headers (msg_id, headers) VALUES ($1, $2)`
+		if _, err := tx.ExecContext(ctx, query, msgDTO.ID, headersJSON); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// claimDedupKey returns the ID of the message holding the dedup key.
+// An expired key is taken over by the new message. If a concurrent transaction
+// claims the same key, the insert waits for it and then reads its message ID.
+func (r *MessageRepository) claimDedupKey(
+	ctx context.Context,
+	tx *sql.Tx,
+	msgDTO *domain.MessageDTO,
+) (uuid.UUID, error) {
+	query := `
+		INSERT INTO message_dedup_keys (queue, dedup_key, msg_id, expires_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (queue, dedup_key) DO UPDATE
+		SET msg_id = EXCLUDED.msg_id, expires_at = EXCLUDED.expires_at
+		WHERE message_dedup_keys.expires_at <= $5
+		RETURNING msg_id
+	`
+	var msgID uuid.UUID
+	err := tx.QueryRowContext(
+		ctx,
+		query,
+		msgDTO.Queue,
+		*msgDTO.DedupKey,
+		msgDTO.ID,
+		msgDTO.DedupUntil,
+		r.clock.Now(),
+	).Scan(&msgID)
+	if err == nil {
+		return msgID, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return uuid.Nil, err
+	}
+
+	// the key is held by another message within its dedup window
+	query = `SELECT msg_id FROM message_dedup_keys WHERE queue = $1 AND dedup_key = $2`
+	if err := tx.QueryRowContext(ctx, query, msgDTO.Queue, *msgDTO.DedupKey).Scan(&msgID); err != nil {
+		return uuid.Nil, err
+	}
+
+	return msgID, nil
+}
+
+// DeleteExpiredDedupKeys removes up to `limit` dedup keys expired before `expiredBefore`.
+func (r *MessageRepository) DeleteExpiredDedupKeys(
+	ctx context.Context,
+	conn dbutils.Querier,
+	expiredBefore time.Time,
+	limit int,
+) (int, error) {
+	query := `
+		DELETE FROM message_dedup_keys
+		WHERE (queue, dedup_key) IN (
+			SELECT queue, dedup_key FROM message_dedup_keys
+			WHERE expires_at <= $1
+			LIMIT $2
+		)
+	`
+	result, err := conn.ExecContext(ctx, query, expiredBefore, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("result.RowsAffected: %w", err)
+	}
+
+	return int(affected), nil
+}
+
 func (r *MessageRepository) update(
 	ctx context.Context,
 	conn dbutils.Querier,
@@ -182,11 +362,13 @@ func (r *MessageRepository) update(
 			status_changed_at = $5,
 			delayed_until = $6,
 			timeout_at = $7,
-			priority = $8,
-			retries = $9,
-			generation = $10,
+			attempt_id = $8,
+			priority = $9,
+			aged_at = $10,
+			retries = $11,
+			generation = $12,
 			version = version + 1
-		WHERE id = $1 AND version = $11
+		WHERE id = $1 AND version = $13
 	`
 	result, err := conn.ExecContext(
 		ctx,
@@ -198,7 +380,9 @@ func (r *MessageRepository) update(
 		msgDTO.StatusChangedAt,
 		msgDTO.DelayedUntil,
 		msgDTO.TimeoutAt,
+		msgDTO.AttemptID,
 		msgDTO.Priority,
+		msgDTO.AgedAt,
 		msgDTO.Retries,
 		msgDTO.Generation,
 		msgDTO.Version,
@@ -214,6 +398,129 @@ func (r *MessageRepository) update(
 	return nil
 }
 
+// updateAll is update for a batch, the rows are matched by id and version in one statement.
+func (r *MessageRepository) updateAll(
+	ctx context.Context,
+	conn dbutils.Querier,
+	msgDTOs []*domain.MessageDTO,
+) error {
+	if len(msgDTOs) == 0 {
+		return nil
+	}
+
+	// parameters in VALUES have no type to infer from, so each of them is cast
+	columnTypes := []string{
+		"uuid", "varchar", "timestamptz", "message_status", "timestamptz", "timestamptz",
+		"timestamptz", "uuid", "smallint", "timestamptz", "int", "int", "int",
+	}
+
+	rows := make([]string, 0, len(msgDTOs))
+	args := make([]any, 0, len(msgDTOs)*len(columnTypes))
+
+	for _, msgDTO := range msgDTOs {
+		rows = append(rows, valuesRow(r.dialect, len(args), columnTypes))
+		args = append(args,
+			msgDTO.ID,
+			msgDTO.Queue,
+			msgDTO.FinalizedAt,
+			msgDTO.Status,
+			msgDTO.StatusChangedAt,
+			msgDTO.DelayedUntil,
+			msgDTO.TimeoutAt,
+			msgDTO.AttemptID,
+			msgDTO.Priority,
+			msgDTO.AgedAt,
+			msgDTO.Retries,
+			msgDTO.Generation,
+			msgDTO.Version,
+		)
+	}
+
+	// Field `created_at` never change, there is no need to update it.
+	// The rows are a CTE rather than a VALUES list in FROM, SQLite only accepts the former.
+	query := fmt.Sprintf(`
+		WITH v (
+			id, queue, finalized_at, status, status_changed_at, delayed_until,
+			timeout_at, attempt_id, priority, aged_at, retries, generation, version
+		) AS (VALUES %s)
+		UPDATE messages AS m
+		SET queue = v.queue,
+		    finalized_at = v.finalized_at,
+		    status = v.status,
+			status_changed_at = v.status_changed_at,
+			delayed_until = v.delayed_until,
+			timeout_at = v.timeout_at,
+			attempt_id = v.attempt_id,
+			priority = v.priority,
+			aged_at = v.aged_at,
+			retries = v.retries,
+			generation = v.generation,
+			version = m.version + 1
+		FROM v
+		WHERE m.id = v.id AND m.version = v.version
+	`, strings.Join(rows, ", "))
+
+	result, err := conn.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if count, err := result.RowsAffected(); err != nil || count != int64(len(msgDTOs)) {
+		return errors.New("update had no effect")
+	}
+
+	return nil
+}
+
+func (r *MessageRepository) createHistoryChapters(
+	ctx context.Context,
+	tx *sql.Tx,
+	chapterDTOs []*domain.MessageChapterDTO,
+) error {
+	if len(chapterDTOs) == 0 {
+		return nil
+	}
+
+	columnTypes := []string{"uuid", "int", "varchar", "timestamptz", "smallint", "int"}
+
+	rows := make([]string, 0, len(chapterDTOs))
+	args := make([]any, 0, len(chapterDTOs)*len(columnTypes))
+
+	for _, chapterDTO := range chapterDTOs {
+		rows = append(rows, valuesRow(r.dialect, len(args), columnTypes))
+		args = append(args,
+			chapterDTO.MsgID,
+			chapterDTO.Generation,
+			chapterDTO.Queue,
+			chapterDTO.RedirectedAt,
+			chapterDTO.Priority,
+			chapterDTO.Retries,
+		)
+	}
+
+	query := `
+		INSERT INTO message_history (
+			msg_id, generation, queue, redirected_at, priority, retries
+		) VALUES ` + strings.Join(rows, ", ")
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// valuesRow returns a row of placeholders for a VALUES list, e.g. ($3::uuid, $4::int)
+// for offset 2 on Postgres. Placeholders are numbered from offset+1, on SQLite they're anonymous,
+// so the VALUES list must come after all other placeholders of the statement.
+func valuesRow(dialect dbutils.Dialect, offset int, columnTypes []string) string {
+	placeholders := make([]string, len(columnTypes))
+	for i, columnType := range columnTypes {
+		placeholders[i] = dialect.Cast(dialect.Placeholder(offset+i+1), columnType)
+	}
+	return "(" + strings.Join(placeholders, ", ") + ")"
+}
+
 func (r *MessageRepository) createHistoryChapter(
 	ctx context.Context,
 	tx *sql.Tx,
@@ -250,6 +557,68 @@ func (r *MessageRepository) GetByID(
 	return r.getByID(ctx, conn, id, false)
 }
 
+// GetByIDsWithLock returns messages by IDs locked for update until the end of the transaction,
+// the result is keyed by the requested IDs. It waits for messages locked by concurrent transactions
+// and fails with ErrMsgNotFound if any message is missing. Rows are locked in the order of IDs,
+// so that concurrent batches don't deadlock.
+func (r *MessageRepository) GetByIDsWithLock(
+	ctx context.Context,
+	tx *sql.Tx,
+	ids []string,
+) (map[string]*domain.Message, error) {
+	result := make(map[string]*domain.Message, len(ids))
+
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	// the same message may be requested several times, and written differently
+	parsed := make(map[string]uuid.UUID, len(ids))
+	placeholders := make([]string, 0, len(ids))
+	args := make([]any, 0, len(ids))
+	for _, id := range ids {
+		msgID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, ErrMsgNotFound
+		}
+		parsed[id] = msgID
+
+		args = append(args, msgID)
+		placeholders = append(placeholders, r.dialect.Placeholder(len(args)))
+	}
+
+	query := selectAll + fmt.Sprintf(`
+		WHERE m.id IN (%s)
+		ORDER BY m.id
+		%s
+	`, strings.Join(placeholders, ", "), r.dialect.LockingClause("FOR UPDATE OF m"))
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos, err := scanRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	loaded := make(map[uuid.UUID]*domain.Message, len(dtos))
+	for _, dto := range dtos {
+		loaded[dto.ID] = domain.FromDTO(dto)
+	}
+
+	for id, msgID := range parsed {
+		message, found := loaded[msgID]
+		if !found {
+			return nil, ErrMsgNotFound
+		}
+		result[id] = message
+	}
+
+	return result, nil
+}
+
 func (r *MessageRepository) GetByIDWithHistory(
 	ctx context.Context,
 	db *sql.DB,
@@ -316,7 +685,7 @@ func (r *MessageRepository) getHistory(
 	placeholders := make([]string, len(msgIDs))
 	args := make([]interface{}, len(msgIDs))
 	for i, msgID := range msgIDs {
-		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		placeholders[i] = r.dialect.Placeholder(i + 1)
 		args[i] = msgID
 	}
 
@@ -351,6 +720,8 @@ func (r *MessageRepository) getHistory(
 			return nil, err
 		}
 
+		localTimes(&dto.RedirectedAt)
+
 		msgIDStr := dto.MsgID.String()
 		result[msgIDStr] = append(result[msgIDStr], &dto)
 	}
@@ -361,19 +732,185 @@ func (r *MessageRepository) getHistory(
 	return result, nil
 }
 
-func (r *MessageRepository) GetNextAvailableWithLock(
+// TakeNextAvailable starts processing of available messages of the queue in a single statement
+// and returns them in the order they should be consumed: the n-th message gets the n-th start,
+// so at most one message is taken per start. A paused queue gives no messages.
+// A message of a group is taken only if it's the oldest pending message of the group
+// and no message of the group is being processed, so a group never has two messages in flight.
+func (r *MessageRepository) TakeNextAvailable(
+	ctx context.Context,
+	conn dbutils.Querier,
+	queue domain.QueueName,
+	starts []*domain.ProcessingStart,
+) ([]*domain.Message, error) {
+	if len(starts) == 0 {
+		return nil, nil
+	}
+
+	args := []any{
+		queue.String(),
+		domain.MsgStatusAvailable,
+		domain.MsgStatusProcessing,
+		domain.MsgStatusDelayed,
+		len(starts),
+	}
+
+	columnTypes := []string{"int", "message_status", "timestamptz", "timestamptz", "uuid"}
+	rows := make([]string, 0, len(starts))
+
+	for i, start := range starts {
+		rows = append(rows, valuesRow(r.dialect, len(args), columnTypes))
+		args = append(args, i+1, start.Status, start.StartedAt, start.TimeoutAt, start.AttemptID)
+	}
+
+	// delayed messages awaiting a retry keep their place in the group;
+	// the updated table isn't aliased, SQLite doesn't accept an alias in RETURNING
+	query := fmt.Sprintf(`
+		WITH picked AS (
+			SELECT m.id, m.priority, m.status_changed_at
+			FROM messages m
+			WHERE m.queue = $1 AND m.status = $2
+				AND NOT EXISTS (SELECT 1 FROM queue_pauses qp WHERE qp.queue = $1)
+				AND (m.group_key IS NULL OR NOT EXISTS (
+					SELECT 1 FROM messages g
+					WHERE g.queue = m.queue AND g.group_key = m.group_key AND g.id <> m.id
+						AND (
+							g.status = $3
+							OR g.status IN ($2, $4) AND (g.created_at, g.id) < (m.created_at, m.id)
+						)
+				))
+			ORDER BY m.priority DESC, m.status_changed_at ASC
+			LIMIT $5
+			%s
+		), ranked AS (
+			SELECT id, row_number() OVER (ORDER BY priority DESC, status_changed_at ASC) AS n
+			FROM picked
+		), starts (n, status, status_changed_at, timeout_at, attempt_id) AS (
+			VALUES %s
+		)
+		UPDATE messages
+		SET
+			status = starts.status,
+			status_changed_at = starts.status_changed_at,
+			timeout_at = starts.timeout_at,
+			attempt_id = starts.attempt_id,
+			version = messages.version + 1
+		FROM ranked
+		JOIN starts ON starts.n = ranked.n
+		WHERE messages.id = ranked.id
+		RETURNING
+			messages.id, messages.queue, messages.group_key, messages.created_at, messages.finalized_at,
+			messages.status, messages.status_changed_at, messages.delayed_until, messages.timeout_at,
+			messages.attempt_id, messages.priority, messages.aged_at, messages.retries, messages.generation,
+			messages.trace_parent, messages.version,
+			(SELECT p.payload FROM message_payloads p WHERE p.msg_id = messages.id),
+			(SELECT h.headers FROM message_headers h WHERE h.msg_id = messages.id)
+	`, r.dialect.LockingClause("FOR UPDATE OF m SKIP LOCKED"), strings.Join(rows, ", "))
+
+	result, err := conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := mapToMessages(scanRows(result))
+	if err != nil {
+		return nil, err
+	}
+
+	// updated rows come back in no particular order, the attempt tells which start a message got
+	order := make(map[uuid.UUID]int, len(starts))
+	for i, start := range starts {
+		order[start.AttemptID] = i
+	}
+
+	slices.SortFunc(messages, func(a, b *domain.Message) int {
+		return order[*a.AttemptID()] - order[*b.AttemptID()]
+	})
+
+	return messages, nil
+}
+
+// List returns up to `limit` messages matching the filter with their history.
+// Run it in REPEATABLE READ, otherwise history could change between the queries.
+func (r *MessageRepository) List(
 	ctx context.Context,
 	conn dbutils.Querier,
+	filter *MessageFilter,
+	limit int,
+) ([]*domain.Message, error) {
+	return r.list(ctx, conn, filter, limit, "")
+}
+
+// ListWithLock is like List, but locks the returned messages and skips already locked ones.
+func (r *MessageRepository) ListWithLock(
+	ctx context.Context,
+	tx *sql.Tx,
+	filter *MessageFilter,
+	limit int,
+) ([]*domain.Message, error) {
+	return r.list(ctx, tx, filter, limit, r.dialect.LockingClause("FOR UPDATE OF m SKIP LOCKED"))
+}
+
+func (r *MessageRepository) list(
+	ctx context.Context,
+	conn dbutils.Querier,
+	filter *MessageFilter,
+	limit int,
+	locking string,
+) ([]*domain.Message, error) {
+	where, args := filter.toSQL("m")
+	query := selectAll + fmt.Sprintf(`
+		WHERE %s
+		ORDER BY m.created_at ASC, m.id ASC
+		LIMIT %d
+		%s
+	`, where, limit, locking)
+	rows, err := conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos, err := scanRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	msgIDs := make([]string, 0, len(dtos))
+	for _, dto := range dtos {
+		msgIDs = append(msgIDs, dto.ID.String())
+	}
+
+	history, err := r.getHistory(ctx, conn, msgIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dto := range dtos {
+		dto.History = history[dto.ID.String()]
+	}
+
+	return mapToMessages(dtos, nil)
+}
+
+// GetAvailableToAgeWithLock returns available messages below maxPriority that have been waiting
+// without aging since agedBefore, locked messages are being consumed right now and are skipped.
+func (r *MessageRepository) GetAvailableToAgeWithLock(
+	ctx context.Context,
+	tx *sql.Tx,
 	queue domain.QueueName,
+	maxPriority domain.Priority,
+	agedBefore time.Time,
 	limit int,
 ) ([]*domain.Message, error) {
-	query := selectAll + `
-		WHERE queue = $1 AND status = $2
-		ORDER BY priority DESC, status_changed_at ASC
-		LIMIT $3
-		FOR UPDATE OF m SKIP LOCKED
-	`
-	rows, err := conn.QueryContext(ctx, query, queue, domain.MsgStatusAvailable, limit)
+	// never aged messages are compared by status_changed_at, a comparison with NULL is false
+	query := selectAll + fmt.Sprintf(`
+		WHERE queue = $1 AND status = $2 AND priority < $3
+			AND CASE WHEN aged_at > status_changed_at THEN aged_at ELSE status_changed_at END <= $4
+		ORDER BY status_changed_at ASC
+		LIMIT $5
+		%s
+	`, r.dialect.LockingClause("FOR UPDATE OF m SKIP LOCKED"))
+	rows, err := tx.QueryContext(ctx, query, queue.String(), domain.MsgStatusAvailable, maxPriority.Int(), agedBefore, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -381,17 +918,24 @@ func (r *MessageRepository) GetNextAvailableWithLock(
 	return mapToMessages(scanRows(rows))
 }
 
+// GetProcessingToExpire returns timed out messages of the given queues, the ones timed out first go first.
 func (r *MessageRepository) GetProcessingToExpire(
 	ctx context.Context,
 	conn dbutils.Querier,
+	queues []domain.QueueName,
 	limit int,
 ) ([]*domain.Message, error) {
-	query := selectAll + `
-		WHERE status = $1 AND timeout_at < $2
+	if len(queues) == 0 {
+		return nil, nil
+	}
+
+	args := []any{domain.MsgStatusProcessing, r.clock.Now(), limit}
+	query := selectAll + fmt.Sprintf(`
+		WHERE status = $1 AND timeout_at < $2 AND queue IN (%s)
 		ORDER BY timeout_at ASC
 		LIMIT $3
-	`
-	rows, err := conn.QueryContext(ctx, query, domain.MsgStatusProcessing, r.clock.Now(), limit)
+	`, inList(&args, queues))
+	rows, err := conn.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -399,17 +943,24 @@ func (r *MessageRepository) GetProcessingToExpire(
 	return mapToMessages(scanRows(rows))
 }
 
+// GetDelayedReadyToResume returns delayed messages of the given queues which are due, the earliest first.
 func (r *MessageRepository) GetDelayedReadyToResume(
 	ctx context.Context,
 	conn dbutils.Querier,
+	queues []domain.QueueName,
 	limit int,
 ) ([]*domain.Message, error) {
-	query := selectAll + `
-		WHERE status = $1 AND delayed_until < $2
+	if len(queues) == 0 {
+		return nil, nil
+	}
+
+	args := []any{domain.MsgStatusDelayed, r.clock.Now(), limit}
+	query := selectAll + fmt.Sprintf(`
+		WHERE status = $1 AND delayed_until < $2 AND queue IN (%s)
 		ORDER BY delayed_until ASC
 		LIMIT $3
-	`
-	rows, err := conn.QueryContext(ctx, query, domain.MsgStatusDelayed, r.clock.Now(), limit)
+	`, inList(&args, queues))
+	rows, err := conn.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -417,6 +968,17 @@ func (r *MessageRepository) GetDelayedReadyToResume(
 	return mapToMessages(scanRows(rows))
 }
 
+// inList appends the values to args and returns their placeholders for an IN list.
+// Values are passed as strings, database/sql drivers don't know value objects of the domain.
+func inList[T fmt.Stringer](args *[]any, values []T) string {
+	placeholders := make([]string, 0, len(values))
+	for _, value := range values {
+		*args = append(*args, value.String())
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(*args)))
+	}
+	return strings.Join(placeholders, ", ")
+}
+
 func (r *MessageRepository) GetFinalizedToArchive(
 	ctx context.Context,
 	conn dbutils.Querier,
@@ -463,6 +1025,151 @@ func (r *MessageRepository) GetFinalizedToArchive(
 	return mapToMessages(dtos, nil)
 }
 
+// CountByQueue returns the number of non-archived messages with the given status per queue.
+// Queues without such messages are omitted.
+func (r *MessageRepository) CountByQueue(
+	ctx context.Context,
+	conn dbutils.Querier,
+	status domain.MessageStatus,
+) (map[domain.QueueName]int, error) {
+	rows, err := conn.QueryContext(ctx, countByQueueQuery(status))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[domain.QueueName]int)
+
+	for rows.Next() {
+		var (
+			queue string
+			count int
+		)
+
+		if err := rows.Scan(&queue, &count); err != nil {
+			return nil, err
+		}
+
+		result[domain.UnsafeQueueName(queue)] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// countByQueueQuery has the status inlined rather than passed as a parameter,
+// otherwise a generic plan couldn't use the partial index of the status.
+func countByQueueQuery(status domain.MessageStatus) string {
+	return fmt.Sprintf(`
+		SELECT queue, count(*)
+		FROM messages
+		WHERE status = '%s'
+		GROUP BY queue
+	`, status)
+}
+
+// GetOldestAvailableByQueue returns when the longest waiting AVAILABLE message of each queue became available.
+// Queues without AVAILABLE messages are omitted.
+func (r *MessageRepository) GetOldestAvailableByQueue(
+	ctx context.Context,
+	conn dbutils.Querier,
+) (map[domain.QueueName]time.Time, error) {
+	query := `
+		SELECT queue, min(status_changed_at)
+		FROM messages
+		WHERE status = 'AVAILABLE'
+		GROUP BY queue
+	`
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[domain.QueueName]time.Time)
+
+	for rows.Next() {
+		var (
+			queue       string
+			availableAt aggregatedTime
+		)
+
+		if err := rows.Scan(&queue, &availableAt); err != nil {
+			return nil, err
+		}
+
+		result[domain.UnsafeQueueName(queue)] = availableAt.Time
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// aggregatedTime scans min or max of a timestamp column. SQLite loses the declared type of the column
+// in aggregates, so the value comes as unix microseconds the times are stored in.
+type aggregatedTime struct {
+	time.Time
+}
+
+func (t *aggregatedTime) Scan(src any) error {
+	switch value := src.(type) {
+	case time.Time:
+		t.Time = value.Local()
+	case int64:
+		t.Time = time.UnixMicro(value)
+	default:
+		return fmt.Errorf("unsupported time value of type %T", src)
+	}
+	return nil
+}
+
+// CountDelayedDueByQueue returns the number of DELAYED messages per queue which become available before the given time.
+// Queues without such messages are omitted.
+func (r *MessageRepository) CountDelayedDueByQueue(
+	ctx context.Context,
+	conn dbutils.Querier,
+	dueBefore time.Time,
+) (map[domain.QueueName]int, error) {
+	query := `
+		SELECT queue, count(*)
+		FROM messages
+		WHERE status = 'DELAYED' AND delayed_until < $1
+		GROUP BY queue
+	`
+	rows, err := conn.QueryContext(ctx, query, dueBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[domain.QueueName]int)
+
+	for rows.Next() {
+		var (
+			queue string
+			count int
+		)
+
+		if err := rows.Scan(&queue, &count); err != nil {
+			return nil, err
+		}
+
+		result[domain.UnsafeQueueName(queue)] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
 func (r *MessageRepository) DeleteInNewTransaction(
 	ctx context.Context,
 	db *sql.DB,
@@ -500,6 +1207,11 @@ func (r *MessageRepository) Delete(
 		return err
 	}
 
+	query = `DELETE FROM message_headers WHERE msg_id = $1`
+	if _, err := tx.ExecContext(ctx, query, msg.ID()); err != nil {
+		return err
+	}
+
 	query = `DELETE FROM message_history WHERE msg_id = $1`
 	if _, err := tx.ExecContext(ctx, query, msg.ID()); err != nil {
 		return err
@@ -508,6 +1220,16 @@ func (r *MessageRepository) Delete(
 	return nil
 }
 
+// localTimes converts scanned times to the local time zone. Postgres returns times in it,
+// SQLite returns them in UTC, the conversion keeps the API output the same for both.
+func localTimes(times ...*time.Time) {
+	for _, t := range times {
+		if t != nil {
+			*t = t.Local()
+		}
+	}
+}
+
 func mapToMessages(dtos []*domain.MessageDTO, err error) ([]*domain.Message, error) {
 	if err != nil {
 		return nil, err
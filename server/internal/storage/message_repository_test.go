@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/stretchr/testify/require"
+
+	"server/internal/domain"
+	"server/internal/utils/testutils"
+)
+
+func TestCountByQueueUsesPartialIndexes(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+	testutils.SkipIfSQLite(t)
+
+	db, err := testutils.OpenDB()
+	require.NoError(t, err)
+	defer db.Close()
+
+	for _, status := range domain.AllMessageStatuses() {
+		t.Run(string(status), func(t *testing.T) {
+			tx, err := db.BeginTx(context.Background(), nil)
+			require.NoError(t, err)
+			defer func() { _ = tx.Rollback() }()
+
+			// the table is small in tests, so the planner has to be kept from preferring a seq scan
+			_, err = tx.Exec("SET LOCAL enable_seqscan = off")
+			require.NoError(t, err)
+
+			rows, err := tx.Query("EXPLAIN " + countByQueueQuery(status))
+			require.NoError(t, err)
+			defer rows.Close()
+
+			var plan []string
+			for rows.Next() {
+				var line string
+				require.NoError(t, rows.Scan(&line))
+				plan = append(plan, line)
+			}
+			require.NoError(t, rows.Err())
+
+			require.Contains(t, strings.Join(plan, "\n"), "Index Only Scan")
+		})
+	}
+}
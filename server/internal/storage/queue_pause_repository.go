@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"server/internal/domain"
+	"server/internal/utils/dbutils"
+)
+
+// QueuePauseRepository keeps the paused state of queues. It's stored in the database,
+// so pausing takes effect on all instances without a config change.
+type QueuePauseRepository struct{}
+
+func NewQueuePauseRepository() *QueuePauseRepository {
+	return &QueuePauseRepository{}
+}
+
+// Pause marks the queue as paused, it returns false if the queue is already paused.
+func (r *QueuePauseRepository) Pause(
+	ctx context.Context,
+	conn dbutils.Querier,
+	queue domain.QueueName,
+	pausedAt time.Time,
+) (bool, error) {
+	query := `
+		INSERT INTO queue_pauses (queue, paused_at)
+		VALUES ($1, $2)
+		ON CONFLICT (queue) DO NOTHING
+	`
+	result, err := conn.ExecContext(ctx, query, queue.String(), pausedAt)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("result.RowsAffected: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// Resume clears the paused state, it returns false if the queue is not paused.
+func (r *QueuePauseRepository) Resume(
+	ctx context.Context,
+	conn dbutils.Querier,
+	queue domain.QueueName,
+) (bool, error) {
+	result, err := conn.ExecContext(ctx, `DELETE FROM queue_pauses WHERE queue = $1`, queue.String())
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("result.RowsAffected: %w", err)
+	}
+
+	return affected > 0, nil
+}
@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"server/internal/domain"
+	"server/internal/utils/dbutils"
+)
+
+var ErrTokenBucketNotFound = errors.New("token bucket not found")
+
+type TokenBucketRepository struct {
+	dialect dbutils.Dialect
+}
+
+func NewTokenBucketRepository(dialect dbutils.Dialect) *TokenBucketRepository {
+	return &TokenBucketRepository{
+		dialect: dialect,
+	}
+}
+
+// CreateIfNotExists stores the bucket unless another instance already did it.
+func (r *TokenBucketRepository) CreateIfNotExists(
+	ctx context.Context,
+	conn dbutils.Querier,
+	bucket *domain.TokenBucket,
+) error {
+	dto := bucket.ToDTO()
+
+	query := `
+		INSERT INTO queue_token_buckets (queue, tokens, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (queue) DO NOTHING
+	`
+	if _, err := conn.ExecContext(ctx, query, dto.Queue, dto.Tokens, dto.UpdatedAt); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetForUpdate locks the bucket until the end of the transaction,
+// so consumers of the queue on all instances take tokens one by one.
+func (r *TokenBucketRepository) GetForUpdate(
+	ctx context.Context,
+	tx *sql.Tx,
+	queue domain.QueueName,
+) (*domain.TokenBucket, error) {
+	query := fmt.Sprintf(`
+		SELECT queue, tokens, updated_at
+		FROM queue_token_buckets
+		WHERE queue = $1
+		%s
+	`, r.dialect.LockingClause("FOR UPDATE"))
+
+	var dto domain.TokenBucketDTO
+
+	err := tx.QueryRowContext(ctx, query, queue.String()).Scan(&dto.Queue, &dto.Tokens, &dto.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrTokenBucketNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.TokenBucketFromDTO(&dto), nil
+}
+
+func (r *TokenBucketRepository) Save(
+	ctx context.Context,
+	tx *sql.Tx,
+	bucket *domain.TokenBucket,
+) error {
+	dto := bucket.ToDTO()
+
+	query := `UPDATE queue_token_buckets SET tokens = $2, updated_at = $3 WHERE queue = $1`
+	if _, err := tx.ExecContext(ctx, query, dto.Queue, dto.Tokens, dto.UpdatedAt); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const traceParentHeader = "traceparent"
+
+// W3C Trace Context, see https://www.w3.org/TR/trace-context/
+var propagator = propagation.TraceContext{}
+
+// Extract returns ctx with the remote span context found in carrier (e.g. HTTP headers) as the parent.
+func Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	return propagator.Extract(ctx, carrier)
+}
+
+// Inject writes the span context of ctx to carrier (e.g. HTTP headers).
+func Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	propagator.Inject(ctx, carrier)
+}
+
+// TraceParent returns the traceparent of the current span, nil if ctx has no valid span context.
+func TraceParent(ctx context.Context) *string {
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+
+	traceParent, exist := carrier[traceParentHeader]
+	if !exist {
+		return nil
+	}
+	return &traceParent
+}
+
+// SpanContextFromTraceParent parses traceparent, the result is invalid if traceparent is malformed.
+func SpanContextFromTraceParent(traceParent string) trace.SpanContext {
+	ctx := propagator.Extract(context.Background(), propagation.MapCarrier{traceParentHeader: traceParent})
+	return trace.SpanContextFromContext(ctx)
+}
@@ -0,0 +1,55 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const remoteTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestTraceParent(t *testing.T) {
+	t.Run("nil without span context", func(t *testing.T) {
+		require.Nil(t, TraceParent(context.Background()))
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		spanContext := SpanContextFromTraceParent(remoteTraceParent)
+		require.True(t, spanContext.IsValid())
+
+		ctx := trace.ContextWithRemoteSpanContext(context.Background(), spanContext)
+		require.Equal(t, remoteTraceParent, *TraceParent(ctx))
+	})
+
+	t.Run("invalid traceparent", func(t *testing.T) {
+		require.False(t, SpanContextFromTraceParent("garbage").IsValid())
+	})
+
+	t.Run("child span continues the remote trace", func(t *testing.T) {
+		exporter := tracetest.NewInMemoryExporter()
+		tracer := NewTracer(sdktrace.NewSimpleSpanProcessor(exporter), 0)
+
+		remote := SpanContextFromTraceParent(remoteTraceParent)
+		ctx, span := tracer.Start(trace.ContextWithRemoteSpanContext(context.Background(), remote), "child")
+		span.End()
+
+		child := SpanContextFromTraceParent(*TraceParent(ctx))
+		require.Equal(t, remote.TraceID(), child.TraceID())
+		require.NotEqual(t, remote.SpanID(), child.SpanID())
+
+		// sampled by the parent despite the zero ratio
+		require.Len(t, exporter.GetSpans(), 1)
+	})
+
+	t.Run("noop tracer keeps the remote span context", func(t *testing.T) {
+		remote := SpanContextFromTraceParent(remoteTraceParent)
+		ctx, span := NewNoopTracer().Start(trace.ContextWithRemoteSpanContext(context.Background(), remote), "child")
+		span.End()
+
+		require.Equal(t, remoteTraceParent, *TraceParent(ctx))
+	})
+}
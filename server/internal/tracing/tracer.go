@@ -0,0 +1,82 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"server/internal/config"
+)
+
+const (
+	serviceName = "queue"
+	tracerName  = "server"
+)
+
+// Tracer owns a dedicated provider instead of the global one, so several apps can live in one process (e.g. in tests).
+type Tracer struct {
+	provider trace.TracerProvider
+	tracer   trace.Tracer
+	shutdown func(ctx context.Context) error
+}
+
+// NewTracer records spans sampled by sampleRatio (unless the parent span decided otherwise) and passes them to processor.
+func NewTracer(processor sdktrace.SpanProcessor, sampleRatio float64) *Tracer {
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+	)
+
+	return &Tracer{
+		provider: provider,
+		tracer:   provider.Tracer(tracerName),
+		shutdown: provider.Shutdown,
+	}
+}
+
+// NewNoopTracer doesn't record spans, but still propagates the incoming trace context.
+func NewNoopTracer() *Tracer {
+	provider := noop.NewTracerProvider()
+
+	return &Tracer{
+		provider: provider,
+		tracer:   provider.Tracer(tracerName),
+		shutdown: func(context.Context) error { return nil },
+	}
+}
+
+// NewOTLPTracer exports spans in batches to the collector from conf.
+// The connection is established lazily, so an unavailable collector doesn't prevent startup.
+func NewOTLPTracer(conf *config.TracingConfig) (*Tracer, error) {
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(conf.Endpoint())}
+	if conf.Insecure() {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+
+	exporter, err := otlptracegrpc.New(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("otlptracegrpc.New: %w", err)
+	}
+
+	return NewTracer(sdktrace.NewBatchSpanProcessor(exporter), conf.SampleRatio()), nil
+}
+
+func (t *Tracer) Start(
+	ctx context.Context,
+	name string,
+	opts ...trace.SpanStartOption,
+) (context.Context, trace.Span) {
+	return t.tracer.Start(ctx, name, opts...)
+}
+
+// Shutdown flushes spans that haven't been exported yet.
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	return t.shutdown(ctx)
+}
@@ -6,16 +6,25 @@ import (
 	"fmt"
 	"log/slog"
 
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
 	"server/internal/appbuilder/requestscope"
+	"server/internal/auth"
 	"server/internal/config"
+	"server/internal/domain"
+	"server/internal/metrics"
 	"server/internal/storage"
+	"server/internal/tracing"
 	"server/internal/utils/dbutils"
 	"server/internal/utils/timeutils"
 )
 
 type AckParams struct {
-	ID      string
-	Release []string
+	ID        string
+	AttemptID uuid.UUID
+	Release   []string
 }
 
 type AckMessages struct {
@@ -25,6 +34,8 @@ type AckMessages struct {
 	msgRepo      *storage.MessageRepository
 	scopeFactory requestscope.Factory
 	conf         *config.Config
+	metrics      *metrics.Metrics
+	tracer       *tracing.Tracer
 }
 
 func NewAckMessages(
@@ -34,6 +45,8 @@ func NewAckMessages(
 	msgRepo *storage.MessageRepository,
 	scopeFactory requestscope.Factory,
 	conf *config.Config,
+	metrics *metrics.Metrics,
+	tracer *tracing.Tracer,
 ) *AckMessages {
 	return &AckMessages{
 		clock:        clock,
@@ -42,10 +55,17 @@ func NewAckMessages(
 		msgRepo:      msgRepo,
 		scopeFactory: scopeFactory,
 		conf:         conf,
+		metrics:      metrics,
+		tracer:       tracer,
 	}
 }
 
 func (uc *AckMessages) Do(ctx context.Context, acks []AckParams) error {
+	ctx, span := uc.tracer.Start(ctx, "AckMessages.Do", trace.WithAttributes(
+		attribute.Int("batch_size", len(acks)),
+	))
+	defer span.End()
+
 	batchSize := len(acks)
 	for _, ack := range acks {
 		batchSize += len(ack.Release)
@@ -62,40 +82,65 @@ func (uc *AckMessages) Do(ctx context.Context, acks []AckParams) error {
 	}
 	defer dbutils.RollbackWithLog(tx, uc.logger)
 
+	ids := make([]string, 0, batchSize)
+	for _, ack := range acks {
+		ids = append(ids, ack.ID)
+		ids = append(ids, ack.Release...)
+	}
+
+	messages, err := uc.msgRepo.GetByIDsWithLock(ctx, tx, ids)
+	if err != nil {
+		return fmt.Errorf("msgRepo.GetByIDsWithLock: %w", err)
+	}
+
+	var ackedFrom []domain.QueueName
+	changed := make([]*domain.Message, 0, batchSize)
+
 	for _, ack := range acks {
-		message, err := uc.msgRepo.GetByID(ctx, uc.db, ack.ID)
-		if err != nil {
-			return fmt.Errorf("msgRepo.GetByID: %w", err)
+		message := messages[ack.ID]
+
+		if err := auth.Authorize(ctx, auth.ActionConsume, message.Queue()); err != nil {
+			return err
+		}
+
+		if err := message.CheckAttempt(ack.AttemptID); err != nil {
+			return fmt.Errorf("message.CheckAttempt: %w", err)
 		}
 
 		if err := message.MarkDelivered(uc.clock); err != nil {
 			return fmt.Errorf("message.MarkDelivered: %w", err)
 		}
 
-		if err := uc.msgRepo.Save(ctx, tx, message); err != nil {
-			return fmt.Errorf("msgRepo.Save: %w", err)
-		}
+		ackedFrom = append(ackedFrom, message.Queue())
+		changed = append(changed, message)
 
 		for _, releaseID := range ack.Release {
-			message, err := uc.msgRepo.GetByID(ctx, uc.db, releaseID)
-			if err != nil {
-				return fmt.Errorf("msgRepo.GetByID: %w", err)
+			message := messages[releaseID]
+
+			if err := auth.Authorize(ctx, auth.ActionPublish, message.Queue()); err != nil {
+				return err
 			}
 
 			if err := message.Release(uc.clock, scope.Dispatcher); err != nil {
 				return fmt.Errorf("message.Release: %w", err)
 			}
 
-			if err := uc.msgRepo.Save(ctx, tx, message); err != nil {
-				return fmt.Errorf("msgRepo.Save: %w", err)
-			}
+			changed = append(changed, message)
 		}
 	}
 
+	if err := uc.msgRepo.SaveAll(ctx, tx, changed); err != nil {
+		return fmt.Errorf("msgRepo.SaveAll: %w", err)
+	}
+
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("tx.Commit: %w", err)
 	}
 
+	for _, queue := range ackedFrom {
+		uc.metrics.MsgAcked(queue)
+	}
+
 	if err := scope.MsgAvailabilityNotifier.Flush(); err != nil {
 		uc.logger.Error("scope.MsgAvailabilityNotifier.Flush", "error", err)
 	}
@@ -0,0 +1,140 @@
+package usecases
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"server/internal/config"
+	"server/internal/domain"
+	"server/internal/metrics"
+	"server/internal/storage"
+	"server/internal/tracing"
+	"server/internal/utils/dbutils"
+	"server/internal/utils/timeutils"
+)
+
+// AgePriorities raises priorities of messages waiting in queues with priority aging.
+type AgePriorities struct {
+	clock   timeutils.Clock
+	logger  *slog.Logger
+	db      *sql.DB
+	msgRepo *storage.MessageRepository
+	conf    *config.Config
+	metrics *metrics.Metrics
+	tracer  *tracing.Tracer
+}
+
+func NewAgePriorities(
+	clock timeutils.Clock,
+	logger *slog.Logger,
+	db *sql.DB,
+	msgRepo *storage.MessageRepository,
+	conf *config.Config,
+	metrics *metrics.Metrics,
+	tracer *tracing.Tracer,
+) *AgePriorities {
+	return &AgePriorities{
+		clock:   clock,
+		logger:  logger,
+		db:      db,
+		msgRepo: msgRepo,
+		conf:    conf,
+		metrics: metrics,
+		tracer:  tracer,
+	}
+}
+
+func (uc *AgePriorities) Run(ctx context.Context) error {
+	for {
+		if err := uc.Do(ctx); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Second):
+			continue
+		}
+	}
+}
+
+func (uc *AgePriorities) Do(ctx context.Context) error {
+	ctx, span := uc.tracer.Start(ctx, "AgePriorities.Do")
+	defer span.End()
+
+	const batchSize = 100
+
+	for _, queue := range uc.conf.QueueNames() {
+		qConf, err := uc.conf.GetQueueConfig(queue)
+		if err != nil {
+			return err
+		}
+
+		aging, isSet := qConf.PriorityAging().Value()
+		if !isSet {
+			continue
+		}
+
+		for {
+			startedAt := time.Now()
+
+			affected, err := uc.doBatch(ctx, queue, aging, batchSize)
+			if err != nil {
+				return err
+			}
+
+			uc.metrics.ObserveWorkerBatch("age_priorities", affected, time.Since(startedAt))
+
+			if affected < batchSize {
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+func (uc *AgePriorities) doBatch(
+	ctx context.Context,
+	queue domain.QueueName,
+	aging *domain.PriorityAging,
+	limit int,
+) (int, error) {
+	tx, err := uc.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer dbutils.RollbackWithLog(tx, uc.logger)
+
+	messages, err := uc.msgRepo.GetAvailableToAgeWithLock(
+		ctx,
+		tx,
+		queue,
+		aging.MaxPriority(),
+		uc.clock.Now().Add(-aging.Interval()),
+		limit,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("msgRepo.GetAvailableToAgeWithLock: %w", err)
+	}
+
+	for _, message := range messages {
+		if err := message.Age(uc.clock, aging); err != nil {
+			return 0, fmt.Errorf("message.Age: %w", err)
+		}
+
+		if err := uc.msgRepo.Save(ctx, tx, message); err != nil {
+			return 0, fmt.Errorf("msgRepo.Save: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("tx.Commit: %w", err)
+	}
+
+	return len(messages), nil
+}
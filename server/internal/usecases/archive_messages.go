@@ -7,7 +7,9 @@ import (
 	"time"
 
 	"server/internal/domain"
+	"server/internal/metrics"
 	"server/internal/storage"
+	"server/internal/tracing"
 	"server/internal/utils/timeutils"
 )
 
@@ -16,6 +18,8 @@ type ArchiveMessages struct {
 	db              *sql.DB
 	msgRepo         *storage.MessageRepository
 	archivedMsgRepo *storage.ArchivedMsgRepository
+	metrics         *metrics.Metrics
+	tracer          *tracing.Tracer
 }
 
 func NewArchiveMessages(
@@ -23,12 +27,16 @@ func NewArchiveMessages(
 	db *sql.DB,
 	msgRepo *storage.MessageRepository,
 	archivedMsgRepo *storage.ArchivedMsgRepository,
+	metrics *metrics.Metrics,
+	tracer *tracing.Tracer,
 ) *ArchiveMessages {
 	return &ArchiveMessages{
 		clock:           clock,
 		db:              db,
 		msgRepo:         msgRepo,
 		archivedMsgRepo: archivedMsgRepo,
+		metrics:         metrics,
+		tracer:          tracer,
 	}
 }
 
@@ -48,14 +56,21 @@ func (uc *ArchiveMessages) Run(ctx context.Context) error {
 }
 
 func (uc *ArchiveMessages) Do(ctx context.Context) error {
+	ctx, span := uc.tracer.Start(ctx, "ArchiveMessages.Do")
+	defer span.End()
+
 	const batchSize = 100
 
 	for {
+		startedAt := time.Now()
+
 		affected, err := uc.doBatch(ctx, batchSize)
 		if err != nil {
 			return err
 		}
 
+		uc.metrics.ObserveWorkerBatch("archive_messages", affected, time.Since(startedAt))
+
 		if affected < batchSize {
 			break
 		}
@@ -7,9 +7,14 @@ import (
 	"fmt"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"server/internal/auth"
 	"server/internal/config"
 	"server/internal/domain"
 	"server/internal/storage"
+	"server/internal/tracing"
 	"server/internal/utils"
 )
 
@@ -23,6 +28,7 @@ type CheckMsgResult struct {
 	Retries     int
 	Generation  int
 	Payload     string
+	Headers     map[string]string
 	History     []CheckMsgChapter
 }
 
@@ -39,6 +45,7 @@ type CheckMessages struct {
 	msgRepo         *storage.MessageRepository
 	archivedMsgRepo *storage.ArchivedMsgRepository
 	conf            *config.Config
+	tracer          *tracing.Tracer
 }
 
 func NewCheckMessages(
@@ -46,16 +53,23 @@ func NewCheckMessages(
 	msgRepo *storage.MessageRepository,
 	archivedMsgRepo *storage.ArchivedMsgRepository,
 	conf *config.Config,
+	tracer *tracing.Tracer,
 ) *CheckMessages {
 	return &CheckMessages{
 		db:              db,
 		msgRepo:         msgRepo,
 		archivedMsgRepo: archivedMsgRepo,
 		conf:            conf,
+		tracer:          tracer,
 	}
 }
 
 func (uc *CheckMessages) Do(ctx context.Context, ids []string) ([]CheckMsgResult, error) {
+	ctx, span := uc.tracer.Start(ctx, "CheckMessages.Do", trace.WithAttributes(
+		attribute.Int("batch_size", len(ids)),
+	))
+	defer span.End()
+
 	if len(ids) > uc.conf.BatchSizeLimit() {
 		return nil, ErrBatchSizeTooBig
 	}
@@ -82,6 +96,27 @@ func (uc *CheckMessages) checkMessage(ctx context.Context, id string) (CheckMsgR
 		return CheckMsgResult{}, fmt.Errorf("msgRepo.GetByID: %w", err)
 	}
 
+	if err := auth.AuthorizeAny(ctx, message.Queue(), auth.ActionPublish, auth.ActionConsume); err != nil {
+		return CheckMsgResult{}, err
+	}
+
+	return mapMessageToCheckResult(message)
+}
+
+func (uc *CheckMessages) checkArchived(ctx context.Context, id string) (CheckMsgResult, error) {
+	archivedMsg, err := uc.archivedMsgRepo.GetByID(ctx, uc.db, id)
+	if err != nil {
+		return CheckMsgResult{}, fmt.Errorf("archivedMsgRepo.GetByID: %w", err)
+	}
+
+	if err := auth.AuthorizeAny(ctx, archivedMsg.Queue(), auth.ActionPublish, auth.ActionConsume); err != nil {
+		return CheckMsgResult{}, err
+	}
+
+	return mapArchivedMsgToCheckResult(archivedMsg), nil
+}
+
+func mapMessageToCheckResult(message *domain.Message) (CheckMsgResult, error) {
 	chapters, loaded := message.History().Chapters()
 	if !loaded {
 		return CheckMsgResult{}, errors.New("logic error: message history must be loaded")
@@ -102,22 +137,18 @@ func (uc *CheckMessages) checkMessage(ctx context.Context, id string) (CheckMsgR
 		ID:          message.ID().String(),
 		Queue:       message.Queue(),
 		Payload:     message.Payload(),
+		Headers:     message.Headers().Map(),
 		CreatedAt:   message.CreatedAt(),
 		FinalizedAt: message.FinalizedAt(),
 		Status:      string(message.Status()),
-		Priority:    message.Priority(),
+		Priority:    message.Priority().Int(),
 		Retries:     message.Retries(),
 		Generation:  message.Generation(),
 		History:     mappedChapters,
 	}, nil
 }
 
-func (uc *CheckMessages) checkArchived(ctx context.Context, id string) (CheckMsgResult, error) {
-	archivedMsg, err := uc.archivedMsgRepo.GetByID(ctx, uc.db, id)
-	if err != nil {
-		return CheckMsgResult{}, fmt.Errorf("archivedMsgRepo.GetByID: %w", err)
-	}
-
+func mapArchivedMsgToCheckResult(archivedMsg *domain.ArchivedMsg) CheckMsgResult {
 	chapters := archivedMsg.History()
 	mappedChapters := make([]CheckMsgChapter, 0, len(chapters))
 	for _, chapter := range chapters {
@@ -134,6 +165,7 @@ func (uc *CheckMessages) checkArchived(ctx context.Context, id string) (CheckMsg
 		ID:          archivedMsg.ID().String(),
 		Queue:       archivedMsg.Queue(),
 		Payload:     archivedMsg.Payload(),
+		Headers:     archivedMsg.Headers().Map(),
 		CreatedAt:   archivedMsg.CreatedAt(),
 		FinalizedAt: utils.P(archivedMsg.FinalizedAt()),
 		Status:      string(archivedMsg.Status()),
@@ -141,5 +173,5 @@ func (uc *CheckMessages) checkArchived(ctx context.Context, id string) (CheckMsg
 		Retries:     archivedMsg.Retries(),
 		Generation:  archivedMsg.Generation(),
 		History:     mappedChapters,
-	}, nil
+	}
 }
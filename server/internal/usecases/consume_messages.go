@@ -7,27 +7,39 @@ import (
 	"log/slog"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"server/internal/auth"
 	"server/internal/config"
 	"server/internal/domain"
 	"server/internal/eventbus"
+	"server/internal/metrics"
 	"server/internal/msgavailability"
 	"server/internal/storage"
+	"server/internal/tracing"
 	"server/internal/utils/dbutils"
 	"server/internal/utils/timeutils"
 )
 
 type MessageToConsume struct {
-	ID      string
-	Payload string
+	ID          string
+	AttemptID   string
+	Payload     string
+	Headers     map[string]string
+	TraceParent *string // publisher's span, consumers continue the trace from it
 }
 
 type ConsumeMessages struct {
-	logger   *slog.Logger
-	clock    timeutils.Clock
-	db       *sql.DB
-	msgRepo  *storage.MessageRepository
-	eventBus *eventbus.EventBus
-	conf     *config.Config
+	logger          *slog.Logger
+	clock           timeutils.Clock
+	db              *sql.DB
+	msgRepo         *storage.MessageRepository
+	tokenBucketRepo *storage.TokenBucketRepository
+	eventBus        *eventbus.EventBus
+	conf            *config.Config
+	metrics         *metrics.Metrics
+	tracer          *tracing.Tracer
 }
 
 func NewConsumeMessages(
@@ -35,16 +47,22 @@ func NewConsumeMessages(
 	clock timeutils.Clock,
 	db *sql.DB,
 	msgRepo *storage.MessageRepository,
+	tokenBucketRepo *storage.TokenBucketRepository,
 	eventBus *eventbus.EventBus,
 	conf *config.Config,
+	metrics *metrics.Metrics,
+	tracer *tracing.Tracer,
 ) *ConsumeMessages {
 	return &ConsumeMessages{
-		logger:   logger,
-		clock:    clock,
-		db:       db,
-		msgRepo:  msgRepo,
-		eventBus: eventBus,
-		conf:     conf,
+		logger:          logger,
+		clock:           clock,
+		db:              db,
+		msgRepo:         msgRepo,
+		tokenBucketRepo: tokenBucketRepo,
+		eventBus:        eventBus,
+		conf:            conf,
+		metrics:         metrics,
+		tracer:          tracer,
 	}
 }
 
@@ -54,12 +72,22 @@ func (uc *ConsumeMessages) Do(
 	limit int,
 	poll time.Duration,
 ) ([]MessageToConsume, error) {
+	ctx, span := uc.tracer.Start(ctx, "ConsumeMessages.Do", trace.WithAttributes(
+		attribute.String("queue", queue.String()),
+		attribute.Int("limit", limit),
+	))
+	defer span.End()
+
 	if limit > uc.conf.BatchSizeLimit() {
 		return nil, ErrBatchSizeTooBig
 	}
 
+	if err := auth.Authorize(ctx, auth.ActionConsume, queue); err != nil {
+		return nil, err
+	}
+
 	// fast path first
-	result, err := uc.takeMessages(ctx, queue, limit)
+	result, throttle, err := uc.takeMessages(ctx, queue, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -73,7 +101,7 @@ func (uc *ConsumeMessages) Do(
 	defer unsubscribe()
 
 	for {
-		result, err = uc.takeMessages(ctx, queue, limit)
+		result, throttle, err = uc.takeMessages(ctx, queue, limit)
 		if err != nil {
 			return nil, err
 		}
@@ -82,52 +110,137 @@ func (uc *ConsumeMessages) Do(
 			return result, nil
 		}
 
-		poller.WaitForNextAttempt(ctx)
+		if throttle > 0 {
+			poller.WaitForThrottle(ctx, throttle)
+		} else {
+			poller.WaitForNextAttempt(ctx)
+		}
+
 		if poller.IsTimedOut() {
 			return []MessageToConsume{}, nil
 		}
 	}
 }
 
-func (uc *ConsumeMessages) takeMessages(ctx context.Context, queue domain.QueueName, limit int) ([]MessageToConsume, error) {
+// takeMessages starts processing of up to limit available messages, a paused queue gives none.
+// If the queue is rate limited and out of tokens, it returns how long to wait for the next token.
+func (uc *ConsumeMessages) takeMessages(
+	ctx context.Context,
+	queue domain.QueueName,
+	limit int,
+) ([]MessageToConsume, time.Duration, error) {
 	qConf, err := uc.conf.GetQueueConfig(queue)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+
+	var messages []*domain.Message
+
+	// without a rate limit it's a single statement, no transaction is needed
+	if rateLimit, isLimited := qConf.RateLimit().Value(); isLimited {
+		var wait time.Duration
+
+		messages, wait, err = uc.takeRateLimited(ctx, queue, qConf, rateLimit, limit)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if wait > 0 {
+			return []MessageToConsume{}, wait, nil
+		}
+	} else {
+		starts := domain.NewProcessingStarts(uc.clock, qConf.ProcessingTimeout(), limit)
+
+		messages, err = uc.msgRepo.TakeNextAvailable(ctx, uc.db, queue, starts)
+		if err != nil {
+			return nil, 0, fmt.Errorf("msgRepo.TakeNextAvailable: %w", err)
+		}
+	}
+
+	uc.metrics.MsgsConsumed(queue, len(messages))
+
+	var result []MessageToConsume
+
+	span := trace.SpanFromContext(ctx)
+	for _, message := range messages {
+		if traceParent := message.TraceParent(); traceParent != nil {
+			span.AddLink(trace.Link{SpanContext: tracing.SpanContextFromTraceParent(*traceParent)})
+		}
+
+		result = append(result, MessageToConsume{
+			ID:          message.ID().String(),
+			AttemptID:   message.AttemptID().String(),
+			Payload:     message.Payload(),
+			Headers:     message.Headers().Map(),
+			TraceParent: message.TraceParent(),
+		})
 	}
 
+	return result, 0, nil
+}
+
+// takeRateLimited takes messages for the tokens granted by the queue's bucket and gives back the unused ones.
+// If no token is granted, it returns how long to wait for the next one.
+func (uc *ConsumeMessages) takeRateLimited(
+	ctx context.Context,
+	queue domain.QueueName,
+	qConf *domain.QueueConfig,
+	rateLimit *domain.RateLimit,
+	limit int,
+) ([]*domain.Message, time.Duration, error) {
 	tx, err := uc.db.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer dbutils.RollbackWithLog(tx, uc.logger)
 
-	messages, err := uc.msgRepo.GetNextAvailableWithLock(ctx, tx, queue, limit)
+	bucket, err := uc.getTokenBucket(ctx, tx, queue, rateLimit)
 	if err != nil {
-		return nil, fmt.Errorf("msgRepo.GetNextAvailableWithLock: %w", err)
+		return nil, 0, err
 	}
 
-	for _, message := range messages {
-		if err := message.StartProcessing(uc.clock, qConf.ProcessingTimeout()); err != nil {
-			return nil, fmt.Errorf("message.StartProcessing: %w", err)
+	granted, wait := bucket.Take(uc.clock, rateLimit, limit)
+	if granted == 0 {
+		if err := tx.Commit(); err != nil {
+			return nil, 0, fmt.Errorf("tx.Commit: %w", err)
 		}
+		return nil, wait, nil
+	}
 
-		if err := uc.msgRepo.Save(ctx, tx, message); err != nil {
-			return nil, fmt.Errorf("msgRepo.Save: %w", err)
-		}
+	starts := domain.NewProcessingStarts(uc.clock, qConf.ProcessingTimeout(), granted)
+
+	messages, err := uc.msgRepo.TakeNextAvailable(ctx, tx, queue, starts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("msgRepo.TakeNextAvailable: %w", err)
+	}
+
+	bucket.GiveBack(rateLimit, granted-len(messages))
+
+	if err := uc.tokenBucketRepo.Save(ctx, tx, bucket); err != nil {
+		return nil, 0, fmt.Errorf("tokenBucketRepo.Save: %w", err)
 	}
 
 	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("tx.Commit: %w", err)
+		return nil, 0, fmt.Errorf("tx.Commit: %w", err)
 	}
 
-	var result []MessageToConsume
+	return messages, 0, nil
+}
 
-	for _, message := range messages {
-		result = append(result, MessageToConsume{
-			ID:      message.ID().String(),
-			Payload: message.Payload(),
-		})
+func (uc *ConsumeMessages) getTokenBucket(
+	ctx context.Context,
+	tx *sql.Tx,
+	queue domain.QueueName,
+	rateLimit *domain.RateLimit,
+) (*domain.TokenBucket, error) {
+	if err := uc.tokenBucketRepo.CreateIfNotExists(ctx, tx, domain.NewTokenBucket(uc.clock, queue, rateLimit)); err != nil {
+		return nil, fmt.Errorf("tokenBucketRepo.CreateIfNotExists: %w", err)
+	}
+
+	bucket, err := uc.tokenBucketRepo.GetForUpdate(ctx, tx, queue)
+	if err != nil {
+		return nil, fmt.Errorf("tokenBucketRepo.GetForUpdate: %w", err)
 	}
 
-	return result, nil
+	return bucket, nil
 }
@@ -0,0 +1,75 @@
+package usecases
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"server/internal/metrics"
+	"server/internal/storage"
+	"server/internal/tracing"
+	"server/internal/utils/timeutils"
+)
+
+// DeleteExpiredDedupKeys removes dedup keys whose deduplication window is over.
+type DeleteExpiredDedupKeys struct {
+	clock   timeutils.Clock
+	db      *sql.DB
+	msgRepo *storage.MessageRepository
+	metrics *metrics.Metrics
+	tracer  *tracing.Tracer
+}
+
+func NewDeleteExpiredDedupKeys(
+	clock timeutils.Clock,
+	db *sql.DB,
+	msgRepo *storage.MessageRepository,
+	metrics *metrics.Metrics,
+	tracer *tracing.Tracer,
+) *DeleteExpiredDedupKeys {
+	return &DeleteExpiredDedupKeys{
+		clock:   clock,
+		db:      db,
+		msgRepo: msgRepo,
+		metrics: metrics,
+		tracer:  tracer,
+	}
+}
+
+func (uc *DeleteExpiredDedupKeys) Run(ctx context.Context) error {
+	for {
+		if err := uc.Do(ctx); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Minute):
+			continue
+		}
+	}
+}
+
+func (uc *DeleteExpiredDedupKeys) Do(ctx context.Context) error {
+	ctx, span := uc.tracer.Start(ctx, "DeleteExpiredDedupKeys.Do")
+	defer span.End()
+
+	const batchSize = 1000
+
+	for {
+		startedAt := time.Now()
+
+		affected, err := uc.msgRepo.DeleteExpiredDedupKeys(ctx, uc.db, uc.clock.Now(), batchSize)
+		if err != nil {
+			return fmt.Errorf("msgRepo.DeleteExpiredDedupKeys: %w", err)
+		}
+
+		uc.metrics.ObserveWorkerBatch("delete_expired_dedup_keys", affected, time.Since(startedAt))
+
+		if affected < batchSize {
+			return nil
+		}
+	}
+}
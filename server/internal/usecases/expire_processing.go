@@ -8,8 +8,11 @@ import (
 	"time"
 
 	"server/internal/appbuilder/requestscope"
+	"server/internal/config"
 	"server/internal/domain"
+	"server/internal/metrics"
 	"server/internal/storage"
+	"server/internal/tracing"
 	"server/internal/utils/dbutils"
 	"server/internal/utils/timeutils"
 )
@@ -21,6 +24,9 @@ type ExpireProcessing struct {
 	msgRepo      *storage.MessageRepository
 	scopeFactory requestscope.Factory
 	nackPolicy   *domain.NackPolicy
+	conf         *config.Config
+	metrics      *metrics.Metrics
+	tracer       *tracing.Tracer
 }
 
 func NewExpireProcessing(
@@ -30,6 +36,9 @@ func NewExpireProcessing(
 	msgRepo *storage.MessageRepository,
 	scopeFactory requestscope.Factory,
 	nackPolicy *domain.NackPolicy,
+	conf *config.Config,
+	metrics *metrics.Metrics,
+	tracer *tracing.Tracer,
 ) *ExpireProcessing {
 	return &ExpireProcessing{
 		clock:        clock,
@@ -38,6 +47,9 @@ func NewExpireProcessing(
 		msgRepo:      msgRepo,
 		scopeFactory: scopeFactory,
 		nackPolicy:   nackPolicy,
+		conf:         conf,
+		metrics:      metrics,
+		tracer:       tracer,
 	}
 }
 
@@ -57,14 +69,21 @@ func (uc *ExpireProcessing) Run(ctx context.Context) error {
 }
 
 func (uc *ExpireProcessing) Do(ctx context.Context) error {
+	ctx, span := uc.tracer.Start(ctx, "ExpireProcessing.Do")
+	defer span.End()
+
 	const batchSize = 100
 
 	for {
+		startedAt := time.Now()
+
 		affected, err := uc.doBatch(ctx, batchSize)
 		if err != nil {
 			return err
 		}
 
+		uc.metrics.ObserveWorkerBatch("expire_processing", affected, time.Since(startedAt))
+
 		if affected < batchSize {
 			break
 		}
@@ -74,7 +93,9 @@ func (uc *ExpireProcessing) Do(ctx context.Context) error {
 }
 
 func (uc *ExpireProcessing) doBatch(ctx context.Context, limit int) (int, error) {
-	messages, err := uc.msgRepo.GetProcessingToExpire(ctx, uc.db, limit)
+	// messages of queues removed on config reload wait for the queue to be configured again,
+	// as there's no nack policy for them
+	messages, err := uc.msgRepo.GetProcessingToExpire(ctx, uc.db, uc.conf.QueueNames(), limit)
 	if err != nil {
 		return 0, fmt.Errorf("msgRepo.GetProcessingToExpire: %w", err)
 	}
@@ -87,11 +108,20 @@ func (uc *ExpireProcessing) doBatch(ctx context.Context, limit int) (int, error)
 	}
 	defer dbutils.RollbackWithLog(tx, uc.logger)
 
+	var expiredFrom, deadLetteredFrom []domain.QueueName
+
 	for _, message := range messages {
+		queue := message.Queue()
+
 		if err := message.Nack(uc.clock, scope.Dispatcher, uc.nackPolicy, true); err != nil {
 			return 0, fmt.Errorf("message.Nack: %w", err)
 		}
 
+		expiredFrom = append(expiredFrom, queue)
+		if message.Queue() != queue {
+			deadLetteredFrom = append(deadLetteredFrom, queue)
+		}
+
 		if err := uc.msgRepo.Save(ctx, tx, message); err != nil {
 			return 0, fmt.Errorf("msgRepo.Save: %w", err)
 		}
@@ -101,6 +131,13 @@ func (uc *ExpireProcessing) doBatch(ctx context.Context, limit int) (int, error)
 		return 0, fmt.Errorf("tx.Commit: %w", err)
 	}
 
+	for _, queue := range expiredFrom {
+		uc.metrics.MsgExpired(queue)
+	}
+	for _, queue := range deadLetteredFrom {
+		uc.metrics.MsgDeadLettered(queue)
+	}
+
 	if err := scope.MsgAvailabilityNotifier.Flush(); err != nil {
 		uc.logger.Error("scope.MsgAvailabilityNotifier.Flush", "error", err)
 	}
@@ -0,0 +1,122 @@
+package usecases
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"server/internal/auth"
+	"server/internal/config"
+	"server/internal/domain"
+	"server/internal/storage"
+	"server/internal/tracing"
+	"server/internal/utils/dbutils"
+	"server/internal/utils/opt"
+	"server/internal/utils/timeutils"
+)
+
+type ExtendParams struct {
+	ID        string
+	AttemptID uuid.UUID
+	Duration  opt.Val[time.Duration] // queue's processing timeout if not set
+}
+
+type ExtendMessages struct {
+	clock   timeutils.Clock
+	logger  *slog.Logger
+	db      *sql.DB
+	msgRepo *storage.MessageRepository
+	conf    *config.Config
+	tracer  *tracing.Tracer
+}
+
+func NewExtendMessages(
+	clock timeutils.Clock,
+	logger *slog.Logger,
+	db *sql.DB,
+	msgRepo *storage.MessageRepository,
+	conf *config.Config,
+	tracer *tracing.Tracer,
+) *ExtendMessages {
+	return &ExtendMessages{
+		clock:   clock,
+		logger:  logger,
+		db:      db,
+		msgRepo: msgRepo,
+		conf:    conf,
+		tracer:  tracer,
+	}
+}
+
+func (uc *ExtendMessages) Do(ctx context.Context, extends []ExtendParams) error {
+	ctx, span := uc.tracer.Start(ctx, "ExtendMessages.Do", trace.WithAttributes(
+		attribute.Int("batch_size", len(extends)),
+	))
+	defer span.End()
+
+	if len(extends) > uc.conf.BatchSizeLimit() {
+		return ErrBatchSizeTooBig
+	}
+
+	tx, err := uc.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer dbutils.RollbackWithLog(tx, uc.logger)
+
+	ids := make([]string, 0, len(extends))
+	for _, extend := range extends {
+		ids = append(ids, extend.ID)
+	}
+
+	messages, err := uc.msgRepo.GetByIDsWithLock(ctx, tx, ids)
+	if err != nil {
+		return fmt.Errorf("msgRepo.GetByIDsWithLock: %w", err)
+	}
+
+	changed := make([]*domain.Message, 0, len(extends))
+
+	for _, extend := range extends {
+		message := messages[extend.ID]
+
+		if err := auth.Authorize(ctx, auth.ActionConsume, message.Queue()); err != nil {
+			return err
+		}
+
+		if err := message.CheckAttempt(extend.AttemptID); err != nil {
+			return fmt.Errorf("message.CheckAttempt: %w", err)
+		}
+
+		qConf, err := uc.conf.GetQueueConfig(message.Queue())
+		if err != nil {
+			return err
+		}
+
+		duration, isSet := extend.Duration.Value()
+		if !isSet {
+			duration = qConf.ProcessingTimeout()
+		}
+
+		if err := message.ExtendProcessing(uc.clock, duration, qConf.MaxProcessingTime()); err != nil {
+			return fmt.Errorf("message.ExtendProcessing: %w", err)
+		}
+
+		changed = append(changed, message)
+	}
+
+	if err := uc.msgRepo.SaveAll(ctx, tx, changed); err != nil {
+		return fmt.Errorf("msgRepo.SaveAll: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("tx.Commit: %w", err)
+	}
+
+	return nil
+}
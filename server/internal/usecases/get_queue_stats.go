@@ -0,0 +1,226 @@
+package usecases
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"server/internal/auth"
+	"server/internal/config"
+	"server/internal/domain"
+	"server/internal/storage"
+	"server/internal/tracing"
+	"server/internal/utils/dbutils"
+	"server/internal/utils/opt"
+	"server/internal/utils/timeutils"
+)
+
+// DelayedDueWindow is how far ahead DELAYED messages are counted as due soon.
+const DelayedDueWindow = time.Minute
+
+type GetQueueStatsParams struct {
+	Queues []domain.QueueName // all queues the caller administers if empty
+}
+
+type QueueStats struct {
+	Queue              domain.QueueName
+	Counts             map[domain.MessageStatus]int // archived messages are not counted
+	OldestAvailableAge opt.Val[time.Duration]       // not set if there are no AVAILABLE messages
+	DelayedDueSoon     int                          // DELAYED messages becoming available within DelayedDueWindow
+	DLQSize            opt.Val[int]                 // not set if dead-lettering is off
+}
+
+type GetQueueStatsResult struct {
+	CollectedAt time.Time
+	Queues      []QueueStats
+}
+
+// statsSnapshot holds the raw numbers of all queues, so that one snapshot serves any set of queues.
+type statsSnapshot struct {
+	collectedAt     time.Time
+	counts          map[domain.MessageStatus]map[domain.QueueName]int
+	oldestAvailable map[domain.QueueName]time.Time
+	delayedDue      map[domain.QueueName]int
+}
+
+// GetQueueStats reports the state of queues. The numbers are collected with a few grouped queries
+// for all queues at once, each backed by a partial index, and reused for the configured stats cache TTL if it's set.
+type GetQueueStats struct {
+	clock   timeutils.Clock
+	logger  *slog.Logger
+	db      *sql.DB
+	msgRepo *storage.MessageRepository
+	conf    *config.Config
+	tracer  *tracing.Tracer
+
+	mu     sync.Mutex
+	cached *statsSnapshot
+}
+
+func NewGetQueueStats(
+	clock timeutils.Clock,
+	logger *slog.Logger,
+	db *sql.DB,
+	msgRepo *storage.MessageRepository,
+	conf *config.Config,
+	tracer *tracing.Tracer,
+) *GetQueueStats {
+	return &GetQueueStats{
+		clock:   clock,
+		logger:  logger,
+		db:      db,
+		msgRepo: msgRepo,
+		conf:    conf,
+		tracer:  tracer,
+	}
+}
+
+func (uc *GetQueueStats) Do(ctx context.Context, params GetQueueStatsParams) (*GetQueueStatsResult, error) {
+	ctx, span := uc.tracer.Start(ctx, "GetQueueStats.Do")
+	defer span.End()
+
+	queues, err := uc.resolveQueues(ctx, params.Queues)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := uc.getSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GetQueueStatsResult{
+		CollectedAt: snapshot.collectedAt,
+		Queues:      make([]QueueStats, 0, len(queues)),
+	}
+
+	for _, queue := range queues {
+		stats := QueueStats{
+			Queue:          queue,
+			Counts:         make(map[domain.MessageStatus]int),
+			DelayedDueSoon: snapshot.delayedDue[queue],
+		}
+
+		// report zeros for all statuses, so that clients don't have to tell absent from empty
+		for _, status := range domain.AllMessageStatuses() {
+			stats.Counts[status] = snapshot.counts[status][queue]
+		}
+
+		if availableAt, found := snapshot.oldestAvailable[queue]; found {
+			stats.OldestAvailableAge = opt.Some(max(snapshot.collectedAt.Sub(availableAt), 0))
+		}
+
+		queueConfig, err := uc.conf.GetQueueConfig(queue)
+		if err != nil {
+			return nil, err
+		}
+
+		if queueConfig.IsDeadLetteringOn() {
+			dlq, err := queue.DLQName()
+			if err != nil {
+				return nil, fmt.Errorf("queue.DLQName: %w", err)
+			}
+
+			// finalized dead letters are waiting for archivation only
+			stats.DLQSize = opt.Some(
+				snapshot.counts[domain.MsgStatusAvailable][dlq] +
+					snapshot.counts[domain.MsgStatusDelayed][dlq] +
+					snapshot.counts[domain.MsgStatusProcessing][dlq],
+			)
+		}
+
+		result.Queues = append(result.Queues, stats)
+	}
+
+	return result, nil
+}
+
+// resolveQueues checks the requested queues or lists all queues the caller administers.
+func (uc *GetQueueStats) resolveQueues(ctx context.Context, requested []domain.QueueName) ([]domain.QueueName, error) {
+	if len(requested) > 0 {
+		for _, queue := range requested {
+			// check that the queue exists
+			if _, err := uc.conf.GetQueueConfig(queue); err != nil {
+				return nil, err
+			}
+
+			if err := auth.Authorize(ctx, auth.ActionAdmin, queue); err != nil {
+				return nil, err
+			}
+		}
+
+		return requested, nil
+	}
+
+	var queues []domain.QueueName
+	for _, queue := range uc.conf.QueueNames() {
+		if auth.Authorize(ctx, auth.ActionAdmin, queue) == nil {
+			queues = append(queues, queue)
+		}
+	}
+
+	return queues, nil
+}
+
+// getSnapshot returns the cached snapshot if it's still fresh, otherwise collects a new one.
+// Concurrent requests wait for a single collection instead of querying the database each.
+func (uc *GetQueueStats) getSnapshot(ctx context.Context) (*statsSnapshot, error) {
+	ttl, cacheOn := uc.conf.StatsCacheTTL().Value()
+	if !cacheOn {
+		return uc.collect(ctx)
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if uc.cached != nil && uc.clock.Now().Sub(uc.cached.collectedAt) < ttl {
+		return uc.cached, nil
+	}
+
+	snapshot, err := uc.collect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.cached = snapshot
+	return snapshot, nil
+}
+
+func (uc *GetQueueStats) collect(ctx context.Context) (*statsSnapshot, error) {
+	// all numbers are read from the same snapshot, so they are consistent with each other
+	tx, err := uc.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("db.BeginTx: %w", err)
+	}
+	defer dbutils.RollbackWithLog(tx, uc.logger)
+
+	now := uc.clock.Now()
+
+	counts := make(map[domain.MessageStatus]map[domain.QueueName]int)
+	for _, status := range domain.AllMessageStatuses() {
+		counts[status], err = uc.msgRepo.CountByQueue(ctx, tx, status)
+		if err != nil {
+			return nil, fmt.Errorf("msgRepo.CountByQueue: %w", err)
+		}
+	}
+
+	oldestAvailable, err := uc.msgRepo.GetOldestAvailableByQueue(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("msgRepo.GetOldestAvailableByQueue: %w", err)
+	}
+
+	delayedDue, err := uc.msgRepo.CountDelayedDueByQueue(ctx, tx, now.Add(DelayedDueWindow))
+	if err != nil {
+		return nil, fmt.Errorf("msgRepo.CountDelayedDueByQueue: %w", err)
+	}
+
+	return &statsSnapshot{
+		collectedAt:     now,
+		counts:          counts,
+		oldestAvailable: oldestAvailable,
+		delayedDue:      delayedDue,
+	}, nil
+}
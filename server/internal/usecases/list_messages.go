@@ -0,0 +1,250 @@
+package usecases
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"server/internal/auth"
+	"server/internal/config"
+	"server/internal/domain"
+	"server/internal/storage"
+	"server/internal/tracing"
+	"server/internal/utils/dbutils"
+	"server/internal/utils/opt"
+)
+
+const DefaultListLimit = 50
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+type ListMessagesParams struct {
+	Queue       domain.QueueName
+	Statuses    []domain.MessageStatus // any status if empty
+	MinPriority *int
+	MaxPriority *int
+	CreatedFrom *time.Time // inclusive
+	CreatedTo   *time.Time // exclusive
+	MinRetries  *int
+	MaxRetries  *int
+	Cursor      *string      // NextCursor of the previous page
+	Limit       opt.Val[int] // DefaultListLimit if not set
+}
+
+type ListMessagesResult struct {
+	Messages   []CheckMsgResult
+	NextCursor *string // not set on the last page
+}
+
+// ListMessages pages through messages of a queue including archived ones, oldest first.
+// It's meant for investigations, so it requires the admin permission on the queue.
+type ListMessages struct {
+	logger          *slog.Logger
+	db              *sql.DB
+	msgRepo         *storage.MessageRepository
+	archivedMsgRepo *storage.ArchivedMsgRepository
+	conf            *config.Config
+	tracer          *tracing.Tracer
+}
+
+func NewListMessages(
+	logger *slog.Logger,
+	db *sql.DB,
+	msgRepo *storage.MessageRepository,
+	archivedMsgRepo *storage.ArchivedMsgRepository,
+	conf *config.Config,
+	tracer *tracing.Tracer,
+) *ListMessages {
+	return &ListMessages{
+		logger:          logger,
+		db:              db,
+		msgRepo:         msgRepo,
+		archivedMsgRepo: archivedMsgRepo,
+		conf:            conf,
+		tracer:          tracer,
+	}
+}
+
+// listedMsg keeps the position of a message in the listing to merge both tables.
+type listedMsg struct {
+	cursor storage.MessageCursor
+	result CheckMsgResult
+}
+
+func (uc *ListMessages) Do(ctx context.Context, params ListMessagesParams) (*ListMessagesResult, error) {
+	ctx, span := uc.tracer.Start(ctx, "ListMessages.Do", trace.WithAttributes(
+		attribute.String("queue", params.Queue.String()),
+	))
+	defer span.End()
+
+	// check that the queue exists
+	if _, err := uc.conf.GetQueueConfig(params.Queue); err != nil {
+		return nil, err
+	}
+
+	if err := auth.Authorize(ctx, auth.ActionAdmin, params.Queue); err != nil {
+		return nil, err
+	}
+
+	limit, isSet := params.Limit.Value()
+	if !isSet {
+		limit = DefaultListLimit
+	}
+
+	if limit > uc.conf.BatchSizeLimit() {
+		return nil, ErrBatchSizeTooBig
+	}
+
+	filter := &storage.MessageFilter{
+		Queue:       params.Queue,
+		Statuses:    params.Statuses,
+		MinPriority: params.MinPriority,
+		MaxPriority: params.MaxPriority,
+		CreatedFrom: params.CreatedFrom,
+		CreatedTo:   params.CreatedTo,
+		MinRetries:  params.MinRetries,
+		MaxRetries:  params.MaxRetries,
+	}
+
+	if params.Cursor != nil {
+		cursor, err := decodeListCursor(*params.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.After = cursor
+	}
+
+	// both tables are read from the same snapshot, so archiving can't move a message between the queries
+	tx, err := uc.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("db.BeginTx: %w", err)
+	}
+	defer dbutils.RollbackWithLog(tx, uc.logger)
+
+	// one extra message tells whether there is a next page
+	listed, err := uc.listMessages(ctx, tx, filter, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.IncludesStatus(domain.MsgStatusDelivered, domain.MsgStatusDropped) {
+		archived, err := uc.listArchived(ctx, tx, filter, limit+1)
+		if err != nil {
+			return nil, err
+		}
+		listed = append(listed, archived...)
+	}
+
+	slices.SortFunc(listed, func(a, b listedMsg) int {
+		return cmp.Or(
+			a.cursor.CreatedAt.Compare(b.cursor.CreatedAt),
+			bytes.Compare(a.cursor.ID[:], b.cursor.ID[:]), // the same order as uuid in postgres
+		)
+	})
+
+	result := &ListMessagesResult{Messages: make([]CheckMsgResult, 0, min(len(listed), limit))}
+
+	if len(listed) > limit {
+		listed = listed[:limit]
+		result.NextCursor = encodeListCursor(listed[limit-1].cursor)
+	}
+
+	for _, msg := range listed {
+		result.Messages = append(result.Messages, msg.result)
+	}
+
+	return result, nil
+}
+
+func (uc *ListMessages) listMessages(
+	ctx context.Context,
+	tx *sql.Tx,
+	filter *storage.MessageFilter,
+	limit int,
+) ([]listedMsg, error) {
+	messages, err := uc.msgRepo.List(ctx, tx, filter, limit)
+	if err != nil {
+		return nil, fmt.Errorf("msgRepo.List: %w", err)
+	}
+
+	listed := make([]listedMsg, 0, len(messages))
+	for _, message := range messages {
+		result, err := mapMessageToCheckResult(message)
+		if err != nil {
+			return nil, err
+		}
+
+		listed = append(listed, listedMsg{
+			cursor: storage.MessageCursor{CreatedAt: message.CreatedAt(), ID: message.ID()},
+			result: result,
+		})
+	}
+
+	return listed, nil
+}
+
+func (uc *ListMessages) listArchived(
+	ctx context.Context,
+	tx *sql.Tx,
+	filter *storage.MessageFilter,
+	limit int,
+) ([]listedMsg, error) {
+	archivedMsgs, err := uc.archivedMsgRepo.List(ctx, tx, filter, limit)
+	if err != nil {
+		return nil, fmt.Errorf("archivedMsgRepo.List: %w", err)
+	}
+
+	listed := make([]listedMsg, 0, len(archivedMsgs))
+	for _, archivedMsg := range archivedMsgs {
+		listed = append(listed, listedMsg{
+			cursor: storage.MessageCursor{CreatedAt: archivedMsg.CreatedAt(), ID: archivedMsg.ID()},
+			result: mapArchivedMsgToCheckResult(archivedMsg),
+		})
+	}
+
+	return listed, nil
+}
+
+// encodeListCursor makes an opaque token of the message position, clients pass it back as is.
+func encodeListCursor(cursor storage.MessageCursor) *string {
+	raw := strconv.FormatInt(cursor.CreatedAt.UnixMicro(), 10) + "." + cursor.ID.String()
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(raw))
+	return &encoded
+}
+
+func decodeListCursor(encoded string) (*storage.MessageCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	createdAtStr, idStr, found := strings.Cut(string(raw), ".")
+	if !found {
+		return nil, ErrInvalidCursor
+	}
+
+	createdAt, err := strconv.ParseInt(createdAtStr, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &storage.MessageCursor{CreatedAt: time.UnixMicro(createdAt), ID: id}, nil
+}
@@ -6,16 +6,24 @@ import (
 	"fmt"
 	"log/slog"
 
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
 	"server/internal/appbuilder/requestscope"
+	"server/internal/auth"
 	"server/internal/config"
 	"server/internal/domain"
+	"server/internal/metrics"
 	"server/internal/storage"
+	"server/internal/tracing"
 	"server/internal/utils/dbutils"
 	"server/internal/utils/timeutils"
 )
 
 type NackParams struct {
 	ID        string
+	AttemptID uuid.UUID
 	Redeliver bool
 }
 
@@ -27,6 +35,8 @@ type NackMessages struct {
 	scopeFactory requestscope.Factory
 	nackPolicy   *domain.NackPolicy
 	conf         *config.Config
+	metrics      *metrics.Metrics
+	tracer       *tracing.Tracer
 }
 
 func NewNackMessages(
@@ -37,6 +47,8 @@ func NewNackMessages(
 	scopeFactory requestscope.Factory,
 	nackPolicy *domain.NackPolicy,
 	conf *config.Config,
+	metrics *metrics.Metrics,
+	tracer *tracing.Tracer,
 ) *NackMessages {
 	return &NackMessages{
 		clock:        clock,
@@ -46,10 +58,17 @@ func NewNackMessages(
 		scopeFactory: scopeFactory,
 		nackPolicy:   nackPolicy,
 		conf:         conf,
+		metrics:      metrics,
+		tracer:       tracer,
 	}
 }
 
 func (uc *NackMessages) Do(ctx context.Context, nacks []NackParams) error {
+	ctx, span := uc.tracer.Start(ctx, "NackMessages.Do", trace.WithAttributes(
+		attribute.Int("batch_size", len(nacks)),
+	))
+	defer span.End()
+
 	if len(nacks) > uc.conf.BatchSizeLimit() {
 		return ErrBatchSizeTooBig
 	}
@@ -62,25 +81,59 @@ func (uc *NackMessages) Do(ctx context.Context, nacks []NackParams) error {
 	}
 	defer dbutils.RollbackWithLog(tx, uc.logger)
 
+	ids := make([]string, 0, len(nacks))
+	for _, nack := range nacks {
+		ids = append(ids, nack.ID)
+	}
+
+	messages, err := uc.msgRepo.GetByIDsWithLock(ctx, tx, ids)
+	if err != nil {
+		return fmt.Errorf("msgRepo.GetByIDsWithLock: %w", err)
+	}
+
+	var nackedFrom, deadLetteredFrom []domain.QueueName
+	changed := make([]*domain.Message, 0, len(nacks))
+
 	for _, nack := range nacks {
-		message, err := uc.msgRepo.GetByID(ctx, uc.db, nack.ID)
-		if err != nil {
-			return fmt.Errorf("msgRepo.GetByID: %w", err)
+		message := messages[nack.ID]
+
+		if err := auth.Authorize(ctx, auth.ActionConsume, message.Queue()); err != nil {
+			return err
 		}
 
+		if err := message.CheckAttempt(nack.AttemptID); err != nil {
+			return fmt.Errorf("message.CheckAttempt: %w", err)
+		}
+
+		queue := message.Queue()
+
 		if err := message.Nack(uc.clock, scope.Dispatcher, uc.nackPolicy, nack.Redeliver); err != nil {
 			return fmt.Errorf("message.Nack: %w", err)
 		}
 
-		if err := uc.msgRepo.Save(ctx, tx, message); err != nil {
-			return fmt.Errorf("msgRepo.Save: %w", err)
+		nackedFrom = append(nackedFrom, queue)
+		if message.Queue() != queue {
+			deadLetteredFrom = append(deadLetteredFrom, queue)
 		}
+
+		changed = append(changed, message)
+	}
+
+	if err := uc.msgRepo.SaveAll(ctx, tx, changed); err != nil {
+		return fmt.Errorf("msgRepo.SaveAll: %w", err)
 	}
 
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("tx.Commit: %w", err)
 	}
 
+	for _, queue := range nackedFrom {
+		uc.metrics.MsgNacked(queue)
+	}
+	for _, queue := range deadLetteredFrom {
+		uc.metrics.MsgDeadLettered(queue)
+	}
+
 	if err := scope.MsgAvailabilityNotifier.Flush(); err != nil {
 		uc.logger.Error("scope.MsgAvailabilityNotifier.Flush", "error", err)
 	}
@@ -0,0 +1,74 @@
+package usecases
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"server/internal/auth"
+	"server/internal/config"
+	"server/internal/domain"
+	"server/internal/storage"
+	"server/internal/tracing"
+	"server/internal/utils/timeutils"
+)
+
+// PauseQueue stops consumers from taking messages from the queue until it's resumed.
+// Messages can still be published to a paused queue.
+type PauseQueue struct {
+	clock     timeutils.Clock
+	logger    *slog.Logger
+	db        *sql.DB
+	pauseRepo *storage.QueuePauseRepository
+	conf      *config.Config
+	tracer    *tracing.Tracer
+}
+
+func NewPauseQueue(
+	clock timeutils.Clock,
+	logger *slog.Logger,
+	db *sql.DB,
+	pauseRepo *storage.QueuePauseRepository,
+	conf *config.Config,
+	tracer *tracing.Tracer,
+) *PauseQueue {
+	return &PauseQueue{
+		clock:     clock,
+		logger:    logger,
+		db:        db,
+		pauseRepo: pauseRepo,
+		conf:      conf,
+		tracer:    tracer,
+	}
+}
+
+func (uc *PauseQueue) Do(ctx context.Context, queue domain.QueueName) error {
+	ctx, span := uc.tracer.Start(ctx, "PauseQueue.Do", trace.WithAttributes(
+		attribute.String("queue", queue.String()),
+	))
+	defer span.End()
+
+	// check that the queue exists
+	if _, err := uc.conf.GetQueueConfig(queue); err != nil {
+		return err
+	}
+
+	if err := auth.Authorize(ctx, auth.ActionAdmin, queue); err != nil {
+		return err
+	}
+
+	paused, err := uc.pauseRepo.Pause(ctx, uc.db, queue, uc.clock.Now())
+	if err != nil {
+		return fmt.Errorf("pauseRepo.Pause: %w", err)
+	}
+
+	if paused {
+		uc.logger.Info("queue paused", "queue", queue)
+	}
+
+	return nil
+}
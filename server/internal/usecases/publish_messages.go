@@ -3,28 +3,39 @@ package usecases
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"server/internal/appbuilder/requestscope"
+	"server/internal/auth"
 	"server/internal/config"
 	"server/internal/domain"
+	"server/internal/metrics"
 	"server/internal/storage"
+	"server/internal/tracing"
+	"server/internal/utils/opt"
 	"server/internal/utils/timeutils"
 )
 
 type NewMessageParams struct {
 	Queue    domain.QueueName
 	Payload  string
-	Priority int
+	Headers  domain.Headers
+	Group    *domain.MessageGroup     // messages of a group are consumed one by one in order
+	Priority opt.Val[domain.Priority] // queue's default priority if not set
 	StartAt  *time.Time
+	DedupKey opt.Val[domain.DedupKey] // no deduplication if not set
 }
 
 type NewMessageResult struct {
-	ID string
+	ID        string
+	Duplicate bool // the message was published before with the same dedup key
 }
 
 type PublishMessages struct {
@@ -34,6 +45,8 @@ type PublishMessages struct {
 	msgRepo      *storage.MessageRepository
 	scopeFactory requestscope.Factory
 	conf         *config.Config
+	metrics      *metrics.Metrics
+	tracer       *tracing.Tracer
 }
 
 func NewPublishMessages(
@@ -43,6 +56,8 @@ func NewPublishMessages(
 	msgRepo *storage.MessageRepository,
 	scopeFactory requestscope.Factory,
 	conf *config.Config,
+	metrics *metrics.Metrics,
+	tracer *tracing.Tracer,
 ) *PublishMessages {
 	return &PublishMessages{
 		logger:       logger,
@@ -51,6 +66,8 @@ func NewPublishMessages(
 		msgRepo:      msgRepo,
 		scopeFactory: scopeFactory,
 		conf:         conf,
+		metrics:      metrics,
+		tracer:       tracer,
 	}
 }
 
@@ -59,6 +76,12 @@ func (uc *PublishMessages) Do(
 	messages []NewMessageParams,
 	autoRelease bool,
 ) ([]BatchResult[NewMessageResult], error) {
+	ctx, span := uc.tracer.Start(ctx, "PublishMessages.Do", trace.WithAttributes(
+		attribute.Int("batch_size", len(messages)),
+		attribute.Bool("auto_release", autoRelease),
+	))
+	defer span.End()
+
 	if len(messages) > uc.conf.BatchSizeLimit() {
 		return nil, ErrBatchSizeTooBig
 	}
@@ -77,10 +100,21 @@ func (uc *PublishMessages) doOne(
 	params NewMessageParams,
 	autoRelease bool,
 ) (*NewMessageResult, error) {
+	// every message gets its own span, consumers continue the trace from it
+	ctx, span := uc.tracer.Start(ctx, "PublishMessages.doOne", trace.WithAttributes(
+		attribute.String("queue", params.Queue.String()),
+	))
+	defer span.End()
+
 	scope := uc.scopeFactory.New()
 
 	// check that the queue exists
-	if _, err := uc.conf.GetQueueConfig(params.Queue); err != nil {
+	qConf, err := uc.conf.GetQueueConfig(params.Queue)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := auth.Authorize(ctx, auth.ActionPublish, params.Queue); err != nil {
 		return nil, err
 	}
 
@@ -88,18 +122,36 @@ func (uc *PublishMessages) doOne(
 		return nil, ErrDirectWriteToDLQNotAllowed
 	}
 
+	priority, isSet := params.Priority.Value()
+	if !isSet {
+		priority = qConf.DefaultPriority()
+	}
+
+	if err := qConf.PriorityRange().Check(priority); err != nil {
+		return nil, err
+	}
+
 	message, err := domain.NewMessage(
 		uc.clock,
 		uuid.New(),
 		params.Queue,
 		params.Payload,
-		params.Priority,
+		params.Headers,
+		params.Group,
+		priority,
 		params.StartAt,
+		tracing.TraceParent(ctx),
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	if dedupKey, isSet := params.DedupKey.Value(); isSet {
+		if err := message.Deduplicate(uc.clock, dedupKey, qConf.DedupWindow()); err != nil {
+			return nil, fmt.Errorf("message.Deduplicate: %w", err)
+		}
+	}
+
 	if autoRelease {
 		if err := message.Release(uc.clock, scope.Dispatcher); err != nil {
 			return nil, fmt.Errorf("message.Release: %w", err)
@@ -107,9 +159,23 @@ func (uc *PublishMessages) doOne(
 	}
 
 	if err := uc.msgRepo.SaveInNewTransaction(ctx, uc.db, message); err != nil {
+		var duplicateErr *storage.DuplicateMsgError
+		if errors.As(err, &duplicateErr) {
+			span.SetAttributes(
+				attribute.String("message_id", duplicateErr.OriginalID.String()),
+				attribute.Bool("duplicate", true),
+			)
+			return &NewMessageResult{
+				ID:        duplicateErr.OriginalID.String(),
+				Duplicate: true,
+			}, nil
+		}
 		return nil, fmt.Errorf("msgRepo.Save: %w", err)
 	}
 
+	uc.metrics.MsgPublished(message.Queue())
+	span.SetAttributes(attribute.String("message_id", message.ID().String()))
+
 	if err := scope.MsgAvailabilityNotifier.Flush(); err != nil {
 		uc.logger.Error("scope.MsgAvailabilityNotifier.Flush", "error", err)
 	}
@@ -0,0 +1,96 @@
+package usecases
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"server/internal/config"
+	"server/internal/metrics"
+	"server/internal/storage"
+	"server/internal/tracing"
+	"server/internal/utils/timeutils"
+)
+
+// PurgeArchive removes archived messages that are older than the retention of their queue.
+// Queues without retention keep archived messages forever.
+type PurgeArchive struct {
+	clock           timeutils.Clock
+	db              *sql.DB
+	archivedMsgRepo *storage.ArchivedMsgRepository
+	conf            *config.Config
+	metrics         *metrics.Metrics
+	tracer          *tracing.Tracer
+}
+
+func NewPurgeArchive(
+	clock timeutils.Clock,
+	db *sql.DB,
+	archivedMsgRepo *storage.ArchivedMsgRepository,
+	conf *config.Config,
+	metrics *metrics.Metrics,
+	tracer *tracing.Tracer,
+) *PurgeArchive {
+	return &PurgeArchive{
+		clock:           clock,
+		db:              db,
+		archivedMsgRepo: archivedMsgRepo,
+		conf:            conf,
+		metrics:         metrics,
+		tracer:          tracer,
+	}
+}
+
+func (uc *PurgeArchive) Run(ctx context.Context) error {
+	for {
+		if err := uc.Do(ctx); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Minute):
+			continue
+		}
+	}
+}
+
+func (uc *PurgeArchive) Do(ctx context.Context) error {
+	ctx, span := uc.tracer.Start(ctx, "PurgeArchive.Do")
+	defer span.End()
+
+	const batchSize = 1000
+
+	for _, queue := range uc.conf.QueueNames() {
+		qConf, err := uc.conf.GetQueueConfig(queue)
+		if err != nil {
+			return err
+		}
+
+		retention, isSet := qConf.Retention().Value()
+		if !isSet {
+			continue
+		}
+
+		finalizedBefore := uc.clock.Now().Add(-retention)
+
+		for {
+			startedAt := time.Now()
+
+			affected, err := uc.archivedMsgRepo.DeleteFinalizedBefore(ctx, uc.db, queue, finalizedBefore, batchSize)
+			if err != nil {
+				return fmt.Errorf("archivedMsgRepo.DeleteFinalizedBefore: %w", err)
+			}
+
+			uc.metrics.ObserveWorkerBatch("purge_archive", affected, time.Since(startedAt))
+
+			if affected < batchSize {
+				break
+			}
+		}
+	}
+
+	return nil
+}
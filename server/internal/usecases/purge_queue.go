@@ -0,0 +1,162 @@
+package usecases
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"server/internal/auth"
+	"server/internal/config"
+	"server/internal/domain"
+	"server/internal/metrics"
+	"server/internal/storage"
+	"server/internal/tracing"
+	"server/internal/utils/dbutils"
+	"server/internal/utils/timeutils"
+)
+
+const purgeBatchSize = 100
+
+type PurgeQueueParams struct {
+	Queue    domain.QueueName
+	Statuses []domain.MessageStatus // any status if empty
+	Archive  bool                   // keep purged messages in the archive as DROPPED instead of deleting them
+}
+
+// PurgeQueue removes messages of a queue, e.g. after a bad bulk publish. It works in batches,
+// each one in its own transaction, so a large queue doesn't hold locks for long.
+// Messages that are locked by a concurrent operation are left in the queue.
+type PurgeQueue struct {
+	clock           timeutils.Clock
+	logger          *slog.Logger
+	db              *sql.DB
+	msgRepo         *storage.MessageRepository
+	archivedMsgRepo *storage.ArchivedMsgRepository
+	conf            *config.Config
+	metrics         *metrics.Metrics
+	tracer          *tracing.Tracer
+}
+
+func NewPurgeQueue(
+	clock timeutils.Clock,
+	logger *slog.Logger,
+	db *sql.DB,
+	msgRepo *storage.MessageRepository,
+	archivedMsgRepo *storage.ArchivedMsgRepository,
+	conf *config.Config,
+	metrics *metrics.Metrics,
+	tracer *tracing.Tracer,
+) *PurgeQueue {
+	return &PurgeQueue{
+		clock:           clock,
+		logger:          logger,
+		db:              db,
+		msgRepo:         msgRepo,
+		archivedMsgRepo: archivedMsgRepo,
+		conf:            conf,
+		metrics:         metrics,
+		tracer:          tracer,
+	}
+}
+
+// Do returns the number of purged messages.
+func (uc *PurgeQueue) Do(ctx context.Context, params PurgeQueueParams) (int, error) {
+	ctx, span := uc.tracer.Start(ctx, "PurgeQueue.Do", trace.WithAttributes(
+		attribute.String("queue", params.Queue.String()),
+		attribute.Bool("archive", params.Archive),
+	))
+	defer span.End()
+
+	// check that the queue exists
+	if _, err := uc.conf.GetQueueConfig(params.Queue); err != nil {
+		return 0, err
+	}
+
+	if err := auth.Authorize(ctx, auth.ActionAdmin, params.Queue); err != nil {
+		return 0, err
+	}
+
+	filter := &storage.MessageFilter{
+		Queue:    params.Queue,
+		Statuses: params.Statuses,
+	}
+
+	purged := 0
+
+	for {
+		startedAt := time.Now()
+
+		affected, err := uc.doBatch(ctx, filter, params.Archive)
+		if err != nil {
+			return purged, err
+		}
+
+		uc.metrics.ObserveWorkerBatch("purge_queue", affected, time.Since(startedAt))
+
+		purged += affected
+
+		if affected < purgeBatchSize {
+			break
+		}
+	}
+
+	uc.logger.Info("queue purged", "queue", params.Queue, "purged", purged, "archived", params.Archive)
+
+	return purged, nil
+}
+
+func (uc *PurgeQueue) doBatch(ctx context.Context, filter *storage.MessageFilter, archive bool) (int, error) {
+	tx, err := uc.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("db.BeginTx: %w", err)
+	}
+	defer dbutils.RollbackWithLog(tx, uc.logger)
+
+	messages, err := uc.msgRepo.ListWithLock(ctx, tx, filter, purgeBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("msgRepo.ListWithLock: %w", err)
+	}
+
+	for _, message := range messages {
+		if archive {
+			if err := uc.archive(ctx, tx, message); err != nil {
+				return 0, err
+			}
+		}
+
+		if err := uc.msgRepo.Delete(ctx, tx, message); err != nil {
+			return 0, fmt.Errorf("msgRepo.Delete: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("tx.Commit: %w", err)
+	}
+
+	return len(messages), nil
+}
+
+func (uc *PurgeQueue) archive(ctx context.Context, tx *sql.Tx, message *domain.Message) error {
+	// finalized messages are archived as they are
+	if message.FinalizedAt() == nil {
+		if err := message.Purge(uc.clock); err != nil {
+			return fmt.Errorf("message.Purge: %w", err)
+		}
+	}
+
+	archivedMsg, err := domain.NewArchivedMsg(message)
+	if err != nil {
+		return fmt.Errorf("domain.NewArchivedMsg: %w", err)
+	}
+
+	if err := uc.archivedMsgRepo.Upsert(ctx, tx, archivedMsg); err != nil {
+		return fmt.Errorf("archivedMsgRepo.Upsert: %w", err)
+	}
+
+	return nil
+}
@@ -6,16 +6,24 @@ import (
 	"fmt"
 	"log/slog"
 
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
 	"server/internal/appbuilder/requestscope"
+	"server/internal/auth"
 	"server/internal/config"
 	"server/internal/domain"
+	"server/internal/metrics"
 	"server/internal/storage"
+	"server/internal/tracing"
 	"server/internal/utils/dbutils"
 	"server/internal/utils/timeutils"
 )
 
 type RedirectParams struct {
 	ID          string
+	AttemptID   uuid.UUID
 	Destination domain.QueueName
 }
 
@@ -26,6 +34,8 @@ type RedirectMessages struct {
 	msgRepo      *storage.MessageRepository
 	scopeFactory requestscope.Factory
 	conf         *config.Config
+	metrics      *metrics.Metrics
+	tracer       *tracing.Tracer
 }
 
 func NewRedirectMessages(
@@ -35,6 +45,8 @@ func NewRedirectMessages(
 	msgRepo *storage.MessageRepository,
 	scopeFactory requestscope.Factory,
 	conf *config.Config,
+	metrics *metrics.Metrics,
+	tracer *tracing.Tracer,
 ) *RedirectMessages {
 	return &RedirectMessages{
 		clock:        clock,
@@ -43,10 +55,17 @@ func NewRedirectMessages(
 		msgRepo:      msgRepo,
 		scopeFactory: scopeFactory,
 		conf:         conf,
+		metrics:      metrics,
+		tracer:       tracer,
 	}
 }
 
 func (uc *RedirectMessages) Do(ctx context.Context, redirects []RedirectParams) error {
+	ctx, span := uc.tracer.Start(ctx, "RedirectMessages.Do", trace.WithAttributes(
+		attribute.Int("batch_size", len(redirects)),
+	))
+	defer span.End()
+
 	if len(redirects) > uc.conf.BatchSizeLimit() {
 		return ErrBatchSizeTooBig
 	}
@@ -59,9 +78,27 @@ func (uc *RedirectMessages) Do(ctx context.Context, redirects []RedirectParams)
 	}
 	defer dbutils.RollbackWithLog(tx, uc.logger)
 
+	ids := make([]string, 0, len(redirects))
+	for _, redirect := range redirects {
+		ids = append(ids, redirect.ID)
+	}
+
+	messages, err := uc.msgRepo.GetByIDsWithLock(ctx, tx, ids)
+	if err != nil {
+		return fmt.Errorf("msgRepo.GetByIDsWithLock: %w", err)
+	}
+
+	var redirectedFrom []domain.QueueName
+	changed := make([]*domain.Message, 0, len(redirects))
+
 	for _, redirect := range redirects {
 		// check that the queue exists
-		if _, err := uc.conf.GetQueueConfig(redirect.Destination); err != nil {
+		destConf, err := uc.conf.GetQueueConfig(redirect.Destination)
+		if err != nil {
+			return err
+		}
+
+		if err := auth.Authorize(ctx, auth.ActionPublish, redirect.Destination); err != nil {
 			return err
 		}
 
@@ -69,24 +106,37 @@ func (uc *RedirectMessages) Do(ctx context.Context, redirects []RedirectParams)
 			return ErrDirectWriteToDLQNotAllowed
 		}
 
-		message, err := uc.msgRepo.GetByID(ctx, uc.db, redirect.ID)
-		if err != nil {
-			return fmt.Errorf("msgRepo.GetByID: %w", err)
+		message := messages[redirect.ID]
+
+		if err := auth.Authorize(ctx, auth.ActionConsume, message.Queue()); err != nil {
+			return err
 		}
 
-		if err := message.Redirect(uc.clock, scope.Dispatcher, redirect.Destination); err != nil {
-			return fmt.Errorf("message.Redirect: %w", err)
+		if err := message.CheckAttempt(redirect.AttemptID); err != nil {
+			return fmt.Errorf("message.CheckAttempt: %w", err)
 		}
 
-		if err := uc.msgRepo.Save(ctx, tx, message); err != nil {
-			return fmt.Errorf("msgRepo.Save: %w", err)
+		redirectedFrom = append(redirectedFrom, message.Queue())
+
+		if err := message.Redirect(uc.clock, scope.Dispatcher, redirect.Destination, destConf.PriorityRange()); err != nil {
+			return fmt.Errorf("message.Redirect: %w", err)
 		}
+
+		changed = append(changed, message)
+	}
+
+	if err := uc.msgRepo.SaveAll(ctx, tx, changed); err != nil {
+		return fmt.Errorf("msgRepo.SaveAll: %w", err)
 	}
 
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("tx.Commit: %w", err)
 	}
 
+	for _, queue := range redirectedFrom {
+		uc.metrics.MsgRedirected(queue)
+	}
+
 	if err := scope.MsgAvailabilityNotifier.Flush(); err != nil {
 		uc.logger.Error("scope.MsgAvailabilityNotifier.Flush", "error", err)
 	}
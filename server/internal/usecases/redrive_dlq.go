@@ -0,0 +1,217 @@
+package usecases
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"server/internal/appbuilder/requestscope"
+	"server/internal/auth"
+	"server/internal/config"
+	"server/internal/domain"
+	"server/internal/metrics"
+	"server/internal/storage"
+	"server/internal/tracing"
+	"server/internal/utils/dbutils"
+	"server/internal/utils/opt"
+	"server/internal/utils/timeutils"
+)
+
+const redriveBatchSize = 100
+
+var ErrNotDLQ = errors.New("queue is not a DLQ")
+
+type RedriveDLQParams struct {
+	Queue       domain.QueueName // the DLQ to redrive from
+	MinPriority *int
+	MaxPriority *int
+	CreatedFrom *time.Time   // inclusive
+	CreatedTo   *time.Time   // exclusive
+	Limit       opt.Val[int] // all matching messages if not set
+}
+
+type RedriveDLQProgress struct {
+	Redriven int
+	Skipped  int // messages whose source queue is not configured anymore
+}
+
+// RedriveDLQ moves messages from a DLQ back to the queues they were dead-lettered from.
+// It works in batches, each one in its own transaction, so a large DLQ doesn't hold locks for long.
+type RedriveDLQ struct {
+	clock        timeutils.Clock
+	logger       *slog.Logger
+	db           *sql.DB
+	msgRepo      *storage.MessageRepository
+	scopeFactory requestscope.Factory
+	conf         *config.Config
+	metrics      *metrics.Metrics
+	tracer       *tracing.Tracer
+}
+
+func NewRedriveDLQ(
+	clock timeutils.Clock,
+	logger *slog.Logger,
+	db *sql.DB,
+	msgRepo *storage.MessageRepository,
+	scopeFactory requestscope.Factory,
+	conf *config.Config,
+	metrics *metrics.Metrics,
+	tracer *tracing.Tracer,
+) *RedriveDLQ {
+	return &RedriveDLQ{
+		clock:        clock,
+		logger:       logger,
+		db:           db,
+		msgRepo:      msgRepo,
+		scopeFactory: scopeFactory,
+		conf:         conf,
+		metrics:      metrics,
+		tracer:       tracer,
+	}
+}
+
+// Do redrives the matching messages and calls onProgress after each batch, onProgress may be nil.
+// Messages that are locked by a concurrent operation are left in the DLQ.
+func (uc *RedriveDLQ) Do(
+	ctx context.Context,
+	params RedriveDLQParams,
+	onProgress func(RedriveDLQProgress),
+) (*RedriveDLQProgress, error) {
+	ctx, span := uc.tracer.Start(ctx, "RedriveDLQ.Do", trace.WithAttributes(
+		attribute.String("queue", params.Queue.String()),
+	))
+	defer span.End()
+
+	if !params.Queue.IsDLQ() {
+		return nil, ErrNotDLQ
+	}
+
+	// check that the queue exists
+	if _, err := uc.conf.GetQueueConfig(params.Queue); err != nil {
+		return nil, err
+	}
+
+	if err := auth.Authorize(ctx, auth.ActionAdmin, params.Queue); err != nil {
+		return nil, err
+	}
+
+	filter := &storage.MessageFilter{
+		Queue:       params.Queue,
+		Statuses:    []domain.MessageStatus{domain.MsgStatusAvailable, domain.MsgStatusDelayed},
+		MinPriority: params.MinPriority,
+		MaxPriority: params.MaxPriority,
+		CreatedFrom: params.CreatedFrom,
+		CreatedTo:   params.CreatedTo,
+	}
+
+	limit, hasLimit := params.Limit.Value()
+	progress := &RedriveDLQProgress{}
+
+	for {
+		batchSize := redriveBatchSize
+		if hasLimit {
+			batchSize = min(batchSize, limit-progress.Redriven-progress.Skipped)
+		}
+
+		if batchSize <= 0 {
+			break
+		}
+
+		last, selected, err := uc.doBatch(ctx, filter, batchSize, progress)
+		if err != nil {
+			return nil, err
+		}
+
+		if selected == 0 {
+			break
+		}
+
+		if onProgress != nil {
+			onProgress(*progress)
+		}
+
+		if selected < batchSize {
+			break
+		}
+
+		// skipped messages stay in the DLQ, so the next batch must start after them
+		filter.After = last
+	}
+
+	return progress, nil
+}
+
+// doBatch returns the position of the last selected message and the number of selected messages.
+func (uc *RedriveDLQ) doBatch(
+	ctx context.Context,
+	filter *storage.MessageFilter,
+	batchSize int,
+	progress *RedriveDLQProgress,
+) (*storage.MessageCursor, int, error) {
+	startedAt := time.Now()
+
+	scope := uc.scopeFactory.New()
+
+	tx, err := uc.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("db.BeginTx: %w", err)
+	}
+	defer dbutils.RollbackWithLog(tx, uc.logger)
+
+	messages, err := uc.msgRepo.ListWithLock(ctx, tx, filter, batchSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("msgRepo.ListWithLock: %w", err)
+	}
+
+	if len(messages) == 0 {
+		return nil, 0, nil
+	}
+
+	var redriven, skipped int
+
+	for _, message := range messages {
+		source, err := message.SourceQueue()
+		if err != nil {
+			return nil, 0, fmt.Errorf("message.SourceQueue: %w", err)
+		}
+
+		// the queue could be removed from the config after the message was dead-lettered
+		if _, err := uc.conf.GetQueueConfig(source); err != nil {
+			uc.logger.Warn("source queue of dead-lettered message is not configured", "msg_id", message.ID(), "queue", source)
+			skipped++
+			continue
+		}
+
+		if err := message.Redrive(uc.clock, scope.Dispatcher); err != nil {
+			return nil, 0, fmt.Errorf("message.Redrive: %w", err)
+		}
+
+		if err := uc.msgRepo.Save(ctx, tx, message); err != nil {
+			return nil, 0, fmt.Errorf("msgRepo.Save: %w", err)
+		}
+
+		redriven++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, 0, fmt.Errorf("tx.Commit: %w", err)
+	}
+
+	progress.Redriven += redriven
+	progress.Skipped += skipped
+
+	uc.metrics.ObserveWorkerBatch("redrive_dlq", len(messages), time.Since(startedAt))
+
+	if err := scope.MsgAvailabilityNotifier.Flush(); err != nil {
+		uc.logger.Error("scope.MsgAvailabilityNotifier.Flush", "error", err)
+	}
+
+	lastMsg := messages[len(messages)-1]
+	return &storage.MessageCursor{CreatedAt: lastMsg.CreatedAt(), ID: lastMsg.ID()}, len(messages), nil
+}
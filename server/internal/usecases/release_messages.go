@@ -6,9 +6,15 @@ import (
 	"fmt"
 	"log/slog"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
 	"server/internal/appbuilder/requestscope"
+	"server/internal/auth"
 	"server/internal/config"
+	"server/internal/domain"
 	"server/internal/storage"
+	"server/internal/tracing"
 	"server/internal/utils/dbutils"
 	"server/internal/utils/timeutils"
 )
@@ -20,6 +26,7 @@ type ReleaseMessages struct {
 	msgRepo      *storage.MessageRepository
 	scopeFactory requestscope.Factory
 	conf         *config.Config
+	tracer       *tracing.Tracer
 }
 
 func NewReleaseMessages(
@@ -29,6 +36,7 @@ func NewReleaseMessages(
 	msgRepo *storage.MessageRepository,
 	scopeFactory requestscope.Factory,
 	conf *config.Config,
+	tracer *tracing.Tracer,
 ) *ReleaseMessages {
 	return &ReleaseMessages{
 		logger:       logger,
@@ -37,10 +45,16 @@ func NewReleaseMessages(
 		msgRepo:      msgRepo,
 		scopeFactory: scopeFactory,
 		conf:         conf,
+		tracer:       tracer,
 	}
 }
 
 func (uc *ReleaseMessages) Do(ctx context.Context, ids []string) error {
+	ctx, span := uc.tracer.Start(ctx, "ReleaseMessages.Do", trace.WithAttributes(
+		attribute.Int("batch_size", len(ids)),
+	))
+	defer span.End()
+
 	if len(ids) > uc.conf.BatchSizeLimit() {
 		return ErrBatchSizeTooBig
 	}
@@ -53,19 +67,29 @@ func (uc *ReleaseMessages) Do(ctx context.Context, ids []string) error {
 	}
 	defer dbutils.RollbackWithLog(tx, uc.logger)
 
+	messages, err := uc.msgRepo.GetByIDsWithLock(ctx, tx, ids)
+	if err != nil {
+		return fmt.Errorf("msgRepo.GetByIDsWithLock: %w", err)
+	}
+
+	changed := make([]*domain.Message, 0, len(ids))
+
 	for _, id := range ids {
-		message, err := uc.msgRepo.GetByID(ctx, uc.db, id)
-		if err != nil {
-			return fmt.Errorf("msgRepo.GetByID: %w", err)
+		message := messages[id]
+
+		if err := auth.Authorize(ctx, auth.ActionPublish, message.Queue()); err != nil {
+			return err
 		}
 
 		if err := message.Release(uc.clock, scope.Dispatcher); err != nil {
 			return fmt.Errorf("message.Release: %w", err)
 		}
 
-		if err := uc.msgRepo.Save(ctx, tx, message); err != nil {
-			return fmt.Errorf("msgRepo.Save: %w", err)
-		}
+		changed = append(changed, message)
+	}
+
+	if err := uc.msgRepo.SaveAll(ctx, tx, changed); err != nil {
+		return fmt.Errorf("msgRepo.SaveAll: %w", err)
 	}
 
 	if err := tx.Commit(); err != nil {
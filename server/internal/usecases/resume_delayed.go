@@ -8,7 +8,10 @@ import (
 	"time"
 
 	"server/internal/appbuilder/requestscope"
+	"server/internal/config"
+	"server/internal/metrics"
 	"server/internal/storage"
+	"server/internal/tracing"
 	"server/internal/utils/dbutils"
 	"server/internal/utils/timeutils"
 )
@@ -19,6 +22,9 @@ type ResumeDelayed struct {
 	db           *sql.DB
 	msgRepo      *storage.MessageRepository
 	scopeFactory requestscope.Factory
+	conf         *config.Config
+	metrics      *metrics.Metrics
+	tracer       *tracing.Tracer
 }
 
 func NewResumeDelayed(
@@ -27,6 +33,9 @@ func NewResumeDelayed(
 	db *sql.DB,
 	msgRepo *storage.MessageRepository,
 	scopeFactory requestscope.Factory,
+	conf *config.Config,
+	metrics *metrics.Metrics,
+	tracer *tracing.Tracer,
 ) *ResumeDelayed {
 	return &ResumeDelayed{
 		clock:        clock,
@@ -34,6 +43,9 @@ func NewResumeDelayed(
 		db:           db,
 		msgRepo:      msgRepo,
 		scopeFactory: scopeFactory,
+		conf:         conf,
+		metrics:      metrics,
+		tracer:       tracer,
 	}
 }
 
@@ -53,14 +65,21 @@ func (uc *ResumeDelayed) Run(ctx context.Context) error {
 }
 
 func (uc *ResumeDelayed) Do(ctx context.Context) error {
+	ctx, span := uc.tracer.Start(ctx, "ResumeDelayed.Do")
+	defer span.End()
+
 	const batchSize = 100
 
 	for {
+		startedAt := time.Now()
+
 		affected, err := uc.doBatch(ctx, batchSize)
 		if err != nil {
 			return err
 		}
 
+		uc.metrics.ObserveWorkerBatch("resume_delayed", affected, time.Since(startedAt))
+
 		if affected < batchSize {
 			break
 		}
@@ -72,7 +91,8 @@ func (uc *ResumeDelayed) Do(ctx context.Context) error {
 func (uc *ResumeDelayed) doBatch(ctx context.Context, limit int) (int, error) {
 	scope := uc.scopeFactory.New()
 
-	messages, err := uc.msgRepo.GetDelayedReadyToResume(ctx, uc.db, limit)
+	// messages of queues removed on config reload stay delayed until the queue is configured again
+	messages, err := uc.msgRepo.GetDelayedReadyToResume(ctx, uc.db, uc.conf.QueueNames(), limit)
 	if err != nil {
 		return 0, fmt.Errorf("msgRepo.GetDelayedReadyToResume: %w", err)
 	}
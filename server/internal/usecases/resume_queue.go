@@ -0,0 +1,83 @@
+package usecases
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"server/internal/appbuilder/requestscope"
+	"server/internal/auth"
+	"server/internal/config"
+	"server/internal/domain"
+	"server/internal/storage"
+	"server/internal/tracing"
+)
+
+// ResumeQueue lets consumers take messages from a paused queue again.
+type ResumeQueue struct {
+	logger       *slog.Logger
+	db           *sql.DB
+	pauseRepo    *storage.QueuePauseRepository
+	scopeFactory requestscope.Factory
+	conf         *config.Config
+	tracer       *tracing.Tracer
+}
+
+func NewResumeQueue(
+	logger *slog.Logger,
+	db *sql.DB,
+	pauseRepo *storage.QueuePauseRepository,
+	scopeFactory requestscope.Factory,
+	conf *config.Config,
+	tracer *tracing.Tracer,
+) *ResumeQueue {
+	return &ResumeQueue{
+		logger:       logger,
+		db:           db,
+		pauseRepo:    pauseRepo,
+		scopeFactory: scopeFactory,
+		conf:         conf,
+		tracer:       tracer,
+	}
+}
+
+func (uc *ResumeQueue) Do(ctx context.Context, queue domain.QueueName) error {
+	ctx, span := uc.tracer.Start(ctx, "ResumeQueue.Do", trace.WithAttributes(
+		attribute.String("queue", queue.String()),
+	))
+	defer span.End()
+
+	// check that the queue exists
+	if _, err := uc.conf.GetQueueConfig(queue); err != nil {
+		return err
+	}
+
+	if err := auth.Authorize(ctx, auth.ActionAdmin, queue); err != nil {
+		return err
+	}
+
+	resumed, err := uc.pauseRepo.Resume(ctx, uc.db, queue)
+	if err != nil {
+		return fmt.Errorf("pauseRepo.Resume: %w", err)
+	}
+
+	if !resumed {
+		return nil
+	}
+
+	uc.logger.Info("queue resumed", "queue", queue)
+
+	// wake up consumers that are long-polling the paused queue
+	scope := uc.scopeFactory.New()
+	scope.Dispatcher.Dispatch(domain.NewMsgAvailableEvent(queue))
+
+	if err := scope.MsgAvailabilityNotifier.Flush(); err != nil {
+		uc.logger.Error("scope.MsgAvailabilityNotifier.Flush", "error", err)
+	}
+
+	return nil
+}
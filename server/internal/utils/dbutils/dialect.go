@@ -0,0 +1,42 @@
+package dbutils
+
+import "fmt"
+
+// Dialect is the SQL flavor of the database. Queries are written to run on both,
+// the few constructs that differ are adapted with the methods below.
+type Dialect string
+
+const (
+	DialectPostgres Dialect = "postgres"
+	DialectSQLite   Dialect = "sqlite"
+)
+
+// LockingClause returns the row locking clause (e.g. FOR UPDATE SKIP LOCKED) for the dialect.
+// SQLite has no row locks: write transactions take the database lock at BEGIN and run one at a time,
+// so the selected rows can't change until the end of the transaction anyway.
+func (d Dialect) LockingClause(clause string) string {
+	if d == DialectSQLite {
+		return ""
+	}
+	return clause
+}
+
+// Placeholder returns the placeholder of the n-th argument of a statement. On SQLite it's an anonymous ?,
+// the driver looks up numbered placeholders among all the arguments one by one, so binding a batch
+// of hundreds of them takes longer than running the statement. A ? takes the argument after
+// the last one bound before it, use it only where arguments come in order after all numbered placeholders.
+func (d Dialect) Placeholder(n int) string {
+	if d == DialectSQLite {
+		return "?"
+	}
+	return fmt.Sprintf("$%d", n)
+}
+
+// Cast returns the placeholder cast to the column type where the type can't be inferred (e.g. in VALUES).
+// SQLite columns take values of any type, so placeholders are left as they are.
+func (d Dialect) Cast(placeholder string, columnType string) string {
+	if d == DialectSQLite {
+		return placeholder
+	}
+	return placeholder + "::" + columnType
+}
@@ -0,0 +1,60 @@
+package runkit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+type GRPCServer struct {
+	Name   string
+	Addr   string
+	Server *grpc.Server
+	Logger *slog.Logger
+}
+
+func (r GRPCServer) Run(ctx context.Context) error {
+	listener, err := net.Listen("tcp", r.Addr)
+	if err != nil {
+		r.Logger.Error(fmt.Sprintf("%s server failed", r.Name), "error", err)
+		return fmt.Errorf("net.Listen: %w", err)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		r.Logger.Info(fmt.Sprintf("starting %s server", r.Name), "addr", r.Addr)
+		if err := r.Server.Serve(listener); err != nil {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		r.Logger.Error(fmt.Sprintf("%s server failed", r.Name), "error", err)
+		return err
+	case <-ctx.Done():
+		r.Logger.Info(fmt.Sprintf("shutting down %s server", r.Name))
+
+		stopped := make(chan struct{})
+		go func() {
+			r.Server.GracefulStop()
+			close(stopped)
+		}()
+
+		// streaming consumers may never finish on their own
+		select {
+		case <-stopped:
+		case <-time.After(5 * time.Second):
+			r.Logger.Error(fmt.Sprintf("shutting down %s server timed out, closing connections", r.Name))
+			r.Server.Stop()
+		}
+
+		r.Logger.Info(fmt.Sprintf("%s server gracefully stopped", r.Name))
+		return nil
+	}
+}
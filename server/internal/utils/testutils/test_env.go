@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -14,6 +15,25 @@ func SkipIfNotInTestEnv(t *testing.T) {
 	}
 }
 
+// UseSQLite reports whether the test environment runs on SQLite (TEST_DB=sqlite) instead of Postgres.
+func UseSQLite() bool {
+	value, _ := os.LookupEnv("TEST_DB")
+	return value == "sqlite"
+}
+
+// SkipIfSQLite skips tests of Postgres specifics when the test environment runs on SQLite.
+func SkipIfSQLite(t *testing.T) {
+	t.Helper()
+	if UseSQLite() {
+		t.Skip("skipping test: postgres only")
+	}
+}
+
+// SQLitePath is the database file of the test environment on SQLite, it's shared by all tests like the Postgres database.
+func SQLitePath() string {
+	return filepath.Join(os.TempDir(), "queue-test.db")
+}
+
 func OpenDB() (*sql.DB, error) {
 	dbURL := fmt.Sprintf(
 		"postgres://%s:%s@%s/%s",
@@ -0,0 +1,241 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"server/internal/config"
+	"server/internal/domain"
+	"server/internal/metrics"
+	"server/internal/tracing"
+	"server/internal/usecases"
+	"server/internal/utils/runkit"
+	"server/pkg/httpmodels"
+)
+
+const pollDuration = 30 * time.Second
+
+// Dispatcher consumes messages of queues in push mode on behalf of their consumers
+// and POSTs them to the configured webhooks. The response decides the fate of the message:
+// 2xx acks it, retryable failures nack it with redelivery and permanent failures nack it
+// without redelivery, so the nack policy drops it or moves it to the DLQ.
+type Dispatcher struct {
+	logger          *slog.Logger
+	conf            *config.Config
+	consumeMessages *usecases.ConsumeMessages
+	ackMessages     *usecases.AckMessages
+	nackMessages    *usecases.NackMessages
+	metrics         *metrics.Metrics
+	tracer          *tracing.Tracer
+	httpClient      *http.Client
+}
+
+func NewDispatcher(
+	logger *slog.Logger,
+	conf *config.Config,
+	consumeMessages *usecases.ConsumeMessages,
+	ackMessages *usecases.AckMessages,
+	nackMessages *usecases.NackMessages,
+	metrics *metrics.Metrics,
+	tracer *tracing.Tracer,
+) *Dispatcher {
+	return &Dispatcher{
+		logger:          logger,
+		conf:            conf,
+		consumeMessages: consumeMessages,
+		ackMessages:     ackMessages,
+		nackMessages:    nackMessages,
+		metrics:         metrics,
+		tracer:          tracer,
+		httpClient:      &http.Client{}, // timeouts are per webhook
+	}
+}
+
+func (d *Dispatcher) Run(ctx context.Context) error {
+	queues := d.conf.WebhookQueues()
+
+	if len(queues) == 0 {
+		// nothing to do, but keep running not to stop sibling processes
+		<-ctx.Done()
+		return nil
+	}
+
+	runners := make(runkit.Multiple, 0, len(queues))
+	for _, queue := range queues {
+		runners = append(runners, queueRunner{dispatcher: d, queue: queue})
+	}
+
+	return runners.Run(ctx)
+}
+
+// Do delivers everything currently available in push mode queues without waiting for new messages.
+func (d *Dispatcher) Do(ctx context.Context) error {
+	for _, queue := range d.conf.WebhookQueues() {
+		for {
+			delivered, err := d.deliverBatch(ctx, queue, 0)
+			if err != nil {
+				return err
+			}
+
+			if delivered == 0 {
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+type queueRunner struct {
+	dispatcher *Dispatcher
+	queue      domain.QueueName
+}
+
+func (r queueRunner) Run(ctx context.Context) error {
+	for ctx.Err() == nil {
+		if _, err := r.dispatcher.deliverBatch(ctx, r.queue, pollDuration); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *Dispatcher) deliverBatch(ctx context.Context, queue domain.QueueName, poll time.Duration) (int, error) {
+	webhook, isSet := d.conf.GetWebhookConfig(queue).Value()
+	if !isSet {
+		return 0, fmt.Errorf("queue %s is not in push mode", queue)
+	}
+
+	messages, err := d.consumeMessages.Do(ctx, queue, webhook.Concurrency(), poll)
+	if err != nil {
+		return 0, fmt.Errorf("consumeMessages.Do: %w", err)
+	}
+
+	var wg sync.WaitGroup
+
+	for _, message := range messages {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.deliver(ctx, queue, webhook, message)
+		}()
+	}
+
+	wg.Wait()
+
+	return len(messages), nil
+}
+
+func (d *Dispatcher) deliver(
+	ctx context.Context,
+	queue domain.QueueName,
+	webhook *config.WebhookConfig,
+	message usecases.MessageToConsume,
+) {
+	// the delivery continues the publisher's trace, the webhook receives it in the traceparent header
+	if message.TraceParent != nil {
+		ctx = trace.ContextWithRemoteSpanContext(ctx, tracing.SpanContextFromTraceParent(*message.TraceParent))
+	}
+
+	ctx, span := d.tracer.Start(ctx, "Dispatcher.deliver", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("queue", queue.String()),
+		attribute.String("message_id", message.ID),
+	))
+	defer span.End()
+
+	attemptID, err := uuid.Parse(message.AttemptID)
+	if err != nil {
+		d.logger.Error("uuid.Parse", "error", err, "id", message.ID)
+		return
+	}
+
+	result, err := d.send(ctx, queue, webhook, message)
+	if err != nil {
+		d.logger.Warn("webhook delivery failed", "queue", queue.String(), "id", message.ID, "error", err)
+	}
+
+	d.metrics.WebhookDelivery(queue, string(result))
+	span.SetAttributes(attribute.String("outcome", string(result)))
+
+	// record the result even if the dispatcher is shutting down
+	finalizeCtx := context.WithoutCancel(ctx)
+
+	switch result {
+	case outcomeDelivered:
+		err = d.ackMessages.Do(finalizeCtx, []usecases.AckParams{{
+			ID:        message.ID,
+			AttemptID: attemptID,
+		}})
+	case outcomeRetryable, outcomePermanent:
+		err = d.nackMessages.Do(finalizeCtx, []usecases.NackParams{{
+			ID:        message.ID,
+			AttemptID: attemptID,
+			Redeliver: result == outcomeRetryable,
+		}})
+	}
+	if err != nil {
+		// most likely the lease has expired meanwhile and the message will be redelivered
+		d.logger.Error("finalizing webhook delivery failed", "queue", queue.String(), "id", message.ID, "error", err)
+	}
+}
+
+func (d *Dispatcher) send(
+	ctx context.Context,
+	queue domain.QueueName,
+	webhook *config.WebhookConfig,
+	message usecases.MessageToConsume,
+) (outcome, error) {
+	body, err := json.Marshal(httpmodels.WebhookRequest{
+		ID:      message.ID,
+		Queue:   queue.String(),
+		Payload: message.Payload,
+		Headers: message.Headers,
+	})
+	if err != nil {
+		return outcomeRetryable, fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, webhook.Timeout())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL(), bytes.NewReader(body))
+	if err != nil {
+		return outcomeRetryable, fmt.Errorf("http.NewRequestWithContext: %w", err)
+	}
+
+	for name, value := range webhook.Headers() {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	tracing.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return outcomeRetryable, fmt.Errorf("httpClient.Do: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	// drain the body to reuse the connection
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	result := classifyResponse(resp.StatusCode)
+	if result != outcomeDelivered {
+		return result, fmt.Errorf("unexpected response status: %d", resp.StatusCode)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,27 @@
+package webhooks
+
+import "net/http"
+
+type outcome string
+
+const (
+	outcomeDelivered outcome = "delivered"
+	outcomeRetryable outcome = "retryable"
+	outcomePermanent outcome = "permanent"
+)
+
+// classifyResponse maps a webhook response status to the delivery outcome.
+// Client errors mean that the message will never be accepted, except for those
+// that signal overload or a slow receiver.
+func classifyResponse(statusCode int) outcome {
+	switch {
+	case statusCode >= 200 && statusCode < 300:
+		return outcomeDelivered
+	case statusCode == http.StatusRequestTimeout, statusCode == http.StatusTooManyRequests:
+		return outcomeRetryable
+	case statusCode >= 400 && statusCode < 500:
+		return outcomePermanent
+	default:
+		return outcomeRetryable
+	}
+}
@@ -0,0 +1,33 @@
+package webhooks
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyResponse(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		expected   outcome
+	}{
+		{http.StatusOK, outcomeDelivered},
+		{http.StatusAccepted, outcomeDelivered},
+		{http.StatusNoContent, outcomeDelivered},
+		{http.StatusMovedPermanently, outcomeRetryable},
+		{http.StatusBadRequest, outcomePermanent},
+		{http.StatusNotFound, outcomePermanent},
+		{http.StatusUnprocessableEntity, outcomePermanent},
+		{http.StatusRequestTimeout, outcomeRetryable},
+		{http.StatusTooManyRequests, outcomeRetryable},
+		{http.StatusInternalServerError, outcomeRetryable},
+		{http.StatusServiceUnavailable, outcomeRetryable},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.statusCode), func(t *testing.T) {
+			require.Equal(t, tt.expected, classifyResponse(tt.statusCode))
+		})
+	}
+}
@@ -0,0 +1,4 @@
+// Package grpcapi contains the protobuf models and the client/server stubs of the gRPC API.
+package grpcapi
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative queue.proto
@@ -0,0 +1,1674 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        v5.29.3
+// source: queue.proto
+
+package grpcapi
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Error carries the same codes as the HTTP API, e.g. "queue_not_found".
+type Error struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	mi := &file_queue_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_queue_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_queue_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Error) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Error) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type OkResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OkResponse) Reset() {
+	*x = OkResponse{}
+	mi := &file_queue_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OkResponse) ProtoMessage() {}
+
+func (x *OkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_queue_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OkResponse.ProtoReflect.Descriptor instead.
+func (*OkResponse) Descriptor() ([]byte, []int) {
+	return file_queue_proto_rawDescGZIP(), []int{1}
+}
+
+type PublishRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Messages      []*PublishRequestItem  `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublishRequest) Reset() {
+	*x = PublishRequest{}
+	mi := &file_queue_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishRequest) ProtoMessage() {}
+
+func (x *PublishRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_queue_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishRequest.ProtoReflect.Descriptor instead.
+func (*PublishRequest) Descriptor() ([]byte, []int) {
+	return file_queue_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PublishRequest) GetMessages() []*PublishRequestItem {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+type PublishRequestItem struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Queue    string                 `protobuf:"bytes,1,opt,name=queue,proto3" json:"queue,omitempty"`
+	Payload  string                 `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	Priority *int32                 `protobuf:"varint,3,opt,name=priority,proto3,oneof" json:"priority,omitempty"`
+	StartAt  *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=start_at,json=startAt,proto3" json:"start_at,omitempty"`
+	Headers  map[string]string      `protobuf:"bytes,5,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	DedupKey *string                `protobuf:"bytes,6,opt,name=dedup_key,json=dedupKey,proto3,oneof" json:"dedup_key,omitempty"`
+	// messages of a group are consumed one at a time in publishing order
+	Group         *string `protobuf:"bytes,7,opt,name=group,proto3,oneof" json:"group,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublishRequestItem) Reset() {
+	*x = PublishRequestItem{}
+	mi := &file_queue_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishRequestItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishRequestItem) ProtoMessage() {}
+
+func (x *PublishRequestItem) ProtoReflect() protoreflect.Message {
+	mi := &file_queue_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishRequestItem.ProtoReflect.Descriptor instead.
+func (*PublishRequestItem) Descriptor() ([]byte, []int) {
+	return file_queue_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PublishRequestItem) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *PublishRequestItem) GetPayload() string {
+	if x != nil {
+		return x.Payload
+	}
+	return ""
+}
+
+func (x *PublishRequestItem) GetPriority() int32 {
+	if x != nil && x.Priority != nil {
+		return *x.Priority
+	}
+	return 0
+}
+
+func (x *PublishRequestItem) GetStartAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartAt
+	}
+	return nil
+}
+
+func (x *PublishRequestItem) GetHeaders() map[string]string {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+func (x *PublishRequestItem) GetDedupKey() string {
+	if x != nil && x.DedupKey != nil {
+		return *x.DedupKey
+	}
+	return ""
+}
+
+func (x *PublishRequestItem) GetGroup() string {
+	if x != nil && x.Group != nil {
+		return *x.Group
+	}
+	return ""
+}
+
+type PublishResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// one result per request item, in the same order
+	Results       []*PublishResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublishResponse) Reset() {
+	*x = PublishResponse{}
+	mi := &file_queue_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishResponse) ProtoMessage() {}
+
+func (x *PublishResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_queue_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishResponse.ProtoReflect.Descriptor instead.
+func (*PublishResponse) Descriptor() ([]byte, []int) {
+	return file_queue_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PublishResponse) GetResults() []*PublishResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type PublishResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Result:
+	//
+	//	*PublishResult_Message
+	//	*PublishResult_Error
+	Result        isPublishResult_Result `protobuf_oneof:"result"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublishResult) Reset() {
+	*x = PublishResult{}
+	mi := &file_queue_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishResult) ProtoMessage() {}
+
+func (x *PublishResult) ProtoReflect() protoreflect.Message {
+	mi := &file_queue_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishResult.ProtoReflect.Descriptor instead.
+func (*PublishResult) Descriptor() ([]byte, []int) {
+	return file_queue_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PublishResult) GetResult() isPublishResult_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *PublishResult) GetMessage() *PublishedMessage {
+	if x != nil {
+		if x, ok := x.Result.(*PublishResult_Message); ok {
+			return x.Message
+		}
+	}
+	return nil
+}
+
+func (x *PublishResult) GetError() *Error {
+	if x != nil {
+		if x, ok := x.Result.(*PublishResult_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+type isPublishResult_Result interface {
+	isPublishResult_Result()
+}
+
+type PublishResult_Message struct {
+	Message *PublishedMessage `protobuf:"bytes,1,opt,name=message,proto3,oneof"`
+}
+
+type PublishResult_Error struct {
+	Error *Error `protobuf:"bytes,2,opt,name=error,proto3,oneof"`
+}
+
+func (*PublishResult_Message) isPublishResult_Result() {}
+
+func (*PublishResult_Error) isPublishResult_Result() {}
+
+type PublishedMessage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// the message was published before with the same dedup key
+	Duplicate     bool `protobuf:"varint,2,opt,name=duplicate,proto3" json:"duplicate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublishedMessage) Reset() {
+	*x = PublishedMessage{}
+	mi := &file_queue_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishedMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishedMessage) ProtoMessage() {}
+
+func (x *PublishedMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_queue_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishedMessage.ProtoReflect.Descriptor instead.
+func (*PublishedMessage) Descriptor() ([]byte, []int) {
+	return file_queue_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PublishedMessage) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PublishedMessage) GetDuplicate() bool {
+	if x != nil {
+		return x.Duplicate
+	}
+	return false
+}
+
+type ReleaseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseRequest) Reset() {
+	*x = ReleaseRequest{}
+	mi := &file_queue_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseRequest) ProtoMessage() {}
+
+func (x *ReleaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_queue_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseRequest.ProtoReflect.Descriptor instead.
+func (*ReleaseRequest) Descriptor() ([]byte, []int) {
+	return file_queue_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ReleaseRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type ConsumeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Queue string                 `protobuf:"bytes,1,opt,name=queue,proto3" json:"queue,omitempty"`
+	Limit *int32                 `protobuf:"varint,2,opt,name=limit,proto3,oneof" json:"limit,omitempty"`
+	// seconds to wait for messages if the queue is empty
+	Poll          *int32 `protobuf:"varint,3,opt,name=poll,proto3,oneof" json:"poll,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConsumeRequest) Reset() {
+	*x = ConsumeRequest{}
+	mi := &file_queue_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConsumeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConsumeRequest) ProtoMessage() {}
+
+func (x *ConsumeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_queue_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConsumeRequest.ProtoReflect.Descriptor instead.
+func (*ConsumeRequest) Descriptor() ([]byte, []int) {
+	return file_queue_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ConsumeRequest) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *ConsumeRequest) GetLimit() int32 {
+	if x != nil && x.Limit != nil {
+		return *x.Limit
+	}
+	return 0
+}
+
+func (x *ConsumeRequest) GetPoll() int32 {
+	if x != nil && x.Poll != nil {
+		return *x.Poll
+	}
+	return 0
+}
+
+type ConsumeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Messages      []*ConsumedMessage     `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConsumeResponse) Reset() {
+	*x = ConsumeResponse{}
+	mi := &file_queue_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConsumeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConsumeResponse) ProtoMessage() {}
+
+func (x *ConsumeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_queue_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConsumeResponse.ProtoReflect.Descriptor instead.
+func (*ConsumeResponse) Descriptor() ([]byte, []int) {
+	return file_queue_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ConsumeResponse) GetMessages() []*ConsumedMessage {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+type ConsumeStreamRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Queue string                 `protobuf:"bytes,1,opt,name=queue,proto3" json:"queue,omitempty"`
+	// max number of messages taken from the queue at once
+	BatchSize     *int32 `protobuf:"varint,2,opt,name=batch_size,json=batchSize,proto3,oneof" json:"batch_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConsumeStreamRequest) Reset() {
+	*x = ConsumeStreamRequest{}
+	mi := &file_queue_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConsumeStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConsumeStreamRequest) ProtoMessage() {}
+
+func (x *ConsumeStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_queue_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConsumeStreamRequest.ProtoReflect.Descriptor instead.
+func (*ConsumeStreamRequest) Descriptor() ([]byte, []int) {
+	return file_queue_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ConsumeStreamRequest) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *ConsumeStreamRequest) GetBatchSize() int32 {
+	if x != nil && x.BatchSize != nil {
+		return *x.BatchSize
+	}
+	return 0
+}
+
+type ConsumedMessage struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AttemptId string                 `protobuf:"bytes,2,opt,name=attempt_id,json=attemptId,proto3" json:"attempt_id,omitempty"`
+	Payload   string                 `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
+	// W3C traceparent of the publisher's span, consumers continue the trace from it
+	TraceParent   *string           `protobuf:"bytes,4,opt,name=trace_parent,json=traceParent,proto3,oneof" json:"trace_parent,omitempty"`
+	Headers       map[string]string `protobuf:"bytes,5,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConsumedMessage) Reset() {
+	*x = ConsumedMessage{}
+	mi := &file_queue_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConsumedMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConsumedMessage) ProtoMessage() {}
+
+func (x *ConsumedMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_queue_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConsumedMessage.ProtoReflect.Descriptor instead.
+func (*ConsumedMessage) Descriptor() ([]byte, []int) {
+	return file_queue_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ConsumedMessage) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ConsumedMessage) GetAttemptId() string {
+	if x != nil {
+		return x.AttemptId
+	}
+	return ""
+}
+
+func (x *ConsumedMessage) GetPayload() string {
+	if x != nil {
+		return x.Payload
+	}
+	return ""
+}
+
+func (x *ConsumedMessage) GetTraceParent() string {
+	if x != nil && x.TraceParent != nil {
+		return *x.TraceParent
+	}
+	return ""
+}
+
+func (x *ConsumedMessage) GetHeaders() map[string]string {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+type AckRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Messages      []*AckRequestItem      `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AckRequest) Reset() {
+	*x = AckRequest{}
+	mi := &file_queue_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AckRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AckRequest) ProtoMessage() {}
+
+func (x *AckRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_queue_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AckRequest.ProtoReflect.Descriptor instead.
+func (*AckRequest) Descriptor() ([]byte, []int) {
+	return file_queue_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *AckRequest) GetMessages() []*AckRequestItem {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+type AckRequestItem struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AttemptId string                 `protobuf:"bytes,2,opt,name=attempt_id,json=attemptId,proto3" json:"attempt_id,omitempty"`
+	// prepared messages to release atomically with the ack
+	Release       []string `protobuf:"bytes,3,rep,name=release,proto3" json:"release,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AckRequestItem) Reset() {
+	*x = AckRequestItem{}
+	mi := &file_queue_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AckRequestItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AckRequestItem) ProtoMessage() {}
+
+func (x *AckRequestItem) ProtoReflect() protoreflect.Message {
+	mi := &file_queue_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AckRequestItem.ProtoReflect.Descriptor instead.
+func (*AckRequestItem) Descriptor() ([]byte, []int) {
+	return file_queue_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *AckRequestItem) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AckRequestItem) GetAttemptId() string {
+	if x != nil {
+		return x.AttemptId
+	}
+	return ""
+}
+
+func (x *AckRequestItem) GetRelease() []string {
+	if x != nil {
+		return x.Release
+	}
+	return nil
+}
+
+type NackRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Messages      []*NackRequestItem     `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NackRequest) Reset() {
+	*x = NackRequest{}
+	mi := &file_queue_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NackRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NackRequest) ProtoMessage() {}
+
+func (x *NackRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_queue_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NackRequest.ProtoReflect.Descriptor instead.
+func (*NackRequest) Descriptor() ([]byte, []int) {
+	return file_queue_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *NackRequest) GetMessages() []*NackRequestItem {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+type NackRequestItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AttemptId     string                 `protobuf:"bytes,2,opt,name=attempt_id,json=attemptId,proto3" json:"attempt_id,omitempty"`
+	Redeliver     *bool                  `protobuf:"varint,3,opt,name=redeliver,proto3,oneof" json:"redeliver,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NackRequestItem) Reset() {
+	*x = NackRequestItem{}
+	mi := &file_queue_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NackRequestItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NackRequestItem) ProtoMessage() {}
+
+func (x *NackRequestItem) ProtoReflect() protoreflect.Message {
+	mi := &file_queue_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NackRequestItem.ProtoReflect.Descriptor instead.
+func (*NackRequestItem) Descriptor() ([]byte, []int) {
+	return file_queue_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *NackRequestItem) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *NackRequestItem) GetAttemptId() string {
+	if x != nil {
+		return x.AttemptId
+	}
+	return ""
+}
+
+func (x *NackRequestItem) GetRedeliver() bool {
+	if x != nil && x.Redeliver != nil {
+		return *x.Redeliver
+	}
+	return false
+}
+
+type RedirectRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Messages      []*RedirectRequestItem `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RedirectRequest) Reset() {
+	*x = RedirectRequest{}
+	mi := &file_queue_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RedirectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RedirectRequest) ProtoMessage() {}
+
+func (x *RedirectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_queue_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RedirectRequest.ProtoReflect.Descriptor instead.
+func (*RedirectRequest) Descriptor() ([]byte, []int) {
+	return file_queue_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *RedirectRequest) GetMessages() []*RedirectRequestItem {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+type RedirectRequestItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AttemptId     string                 `protobuf:"bytes,2,opt,name=attempt_id,json=attemptId,proto3" json:"attempt_id,omitempty"`
+	Destination   string                 `protobuf:"bytes,3,opt,name=destination,proto3" json:"destination,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RedirectRequestItem) Reset() {
+	*x = RedirectRequestItem{}
+	mi := &file_queue_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RedirectRequestItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RedirectRequestItem) ProtoMessage() {}
+
+func (x *RedirectRequestItem) ProtoReflect() protoreflect.Message {
+	mi := &file_queue_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RedirectRequestItem.ProtoReflect.Descriptor instead.
+func (*RedirectRequestItem) Descriptor() ([]byte, []int) {
+	return file_queue_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *RedirectRequestItem) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RedirectRequestItem) GetAttemptId() string {
+	if x != nil {
+		return x.AttemptId
+	}
+	return ""
+}
+
+func (x *RedirectRequestItem) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+type ExtendRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Messages      []*ExtendRequestItem   `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExtendRequest) Reset() {
+	*x = ExtendRequest{}
+	mi := &file_queue_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExtendRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtendRequest) ProtoMessage() {}
+
+func (x *ExtendRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_queue_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtendRequest.ProtoReflect.Descriptor instead.
+func (*ExtendRequest) Descriptor() ([]byte, []int) {
+	return file_queue_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ExtendRequest) GetMessages() []*ExtendRequestItem {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+type ExtendRequestItem struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	AttemptId string                 `protobuf:"bytes,2,opt,name=attempt_id,json=attemptId,proto3" json:"attempt_id,omitempty"`
+	// seconds, queue's processing timeout if not set
+	Duration      *int32 `protobuf:"varint,3,opt,name=duration,proto3,oneof" json:"duration,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExtendRequestItem) Reset() {
+	*x = ExtendRequestItem{}
+	mi := &file_queue_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExtendRequestItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtendRequestItem) ProtoMessage() {}
+
+func (x *ExtendRequestItem) ProtoReflect() protoreflect.Message {
+	mi := &file_queue_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtendRequestItem.ProtoReflect.Descriptor instead.
+func (*ExtendRequestItem) Descriptor() ([]byte, []int) {
+	return file_queue_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ExtendRequestItem) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ExtendRequestItem) GetAttemptId() string {
+	if x != nil {
+		return x.AttemptId
+	}
+	return ""
+}
+
+func (x *ExtendRequestItem) GetDuration() int32 {
+	if x != nil && x.Duration != nil {
+		return *x.Duration
+	}
+	return 0
+}
+
+type CheckRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckRequest) Reset() {
+	*x = CheckRequest{}
+	mi := &file_queue_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckRequest) ProtoMessage() {}
+
+func (x *CheckRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_queue_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckRequest.ProtoReflect.Descriptor instead.
+func (*CheckRequest) Descriptor() ([]byte, []int) {
+	return file_queue_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *CheckRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type CheckResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Messages      []*Message             `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckResponse) Reset() {
+	*x = CheckResponse{}
+	mi := &file_queue_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckResponse) ProtoMessage() {}
+
+func (x *CheckResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_queue_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckResponse.ProtoReflect.Descriptor instead.
+func (*CheckResponse) Descriptor() ([]byte, []int) {
+	return file_queue_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *CheckResponse) GetMessages() []*Message {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+type Message struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Queue         string                 `protobuf:"bytes,2,opt,name=queue,proto3" json:"queue,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	FinalizedAt   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=finalized_at,json=finalizedAt,proto3" json:"finalized_at,omitempty"`
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	Priority      int32                  `protobuf:"varint,6,opt,name=priority,proto3" json:"priority,omitempty"`
+	Retries       int32                  `protobuf:"varint,7,opt,name=retries,proto3" json:"retries,omitempty"`
+	Generation    int32                  `protobuf:"varint,8,opt,name=generation,proto3" json:"generation,omitempty"`
+	History       []*MessageChapter      `protobuf:"bytes,9,rep,name=history,proto3" json:"history,omitempty"`
+	Payload       string                 `protobuf:"bytes,10,opt,name=payload,proto3" json:"payload,omitempty"`
+	Headers       map[string]string      `protobuf:"bytes,11,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Message) Reset() {
+	*x = Message{}
+	mi := &file_queue_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Message) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message) ProtoMessage() {}
+
+func (x *Message) ProtoReflect() protoreflect.Message {
+	mi := &file_queue_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message.ProtoReflect.Descriptor instead.
+func (*Message) Descriptor() ([]byte, []int) {
+	return file_queue_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *Message) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Message) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *Message) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Message) GetFinalizedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FinalizedAt
+	}
+	return nil
+}
+
+func (x *Message) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Message) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *Message) GetRetries() int32 {
+	if x != nil {
+		return x.Retries
+	}
+	return 0
+}
+
+func (x *Message) GetGeneration() int32 {
+	if x != nil {
+		return x.Generation
+	}
+	return 0
+}
+
+func (x *Message) GetHistory() []*MessageChapter {
+	if x != nil {
+		return x.History
+	}
+	return nil
+}
+
+func (x *Message) GetPayload() string {
+	if x != nil {
+		return x.Payload
+	}
+	return ""
+}
+
+func (x *Message) GetHeaders() map[string]string {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+type MessageChapter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Generation    int32                  `protobuf:"varint,1,opt,name=generation,proto3" json:"generation,omitempty"`
+	Queue         string                 `protobuf:"bytes,2,opt,name=queue,proto3" json:"queue,omitempty"`
+	RedirectedAt  *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=redirected_at,json=redirectedAt,proto3" json:"redirected_at,omitempty"`
+	Priority      int32                  `protobuf:"varint,4,opt,name=priority,proto3" json:"priority,omitempty"`
+	Retries       int32                  `protobuf:"varint,5,opt,name=retries,proto3" json:"retries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessageChapter) Reset() {
+	*x = MessageChapter{}
+	mi := &file_queue_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageChapter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageChapter) ProtoMessage() {}
+
+func (x *MessageChapter) ProtoReflect() protoreflect.Message {
+	mi := &file_queue_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageChapter.ProtoReflect.Descriptor instead.
+func (*MessageChapter) Descriptor() ([]byte, []int) {
+	return file_queue_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *MessageChapter) GetGeneration() int32 {
+	if x != nil {
+		return x.Generation
+	}
+	return 0
+}
+
+func (x *MessageChapter) GetQueue() string {
+	if x != nil {
+		return x.Queue
+	}
+	return ""
+}
+
+func (x *MessageChapter) GetRedirectedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RedirectedAt
+	}
+	return nil
+}
+
+func (x *MessageChapter) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *MessageChapter) GetRetries() int32 {
+	if x != nil {
+		return x.Retries
+	}
+	return 0
+}
+
+var File_queue_proto protoreflect.FileDescriptor
+
+const file_queue_proto_rawDesc = "" +
+	"\n" +
+	"\vqueue.proto\x12\bqueue.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"5\n" +
+	"\x05Error\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\f\n" +
+	"\n" +
+	"OkResponse\"J\n" +
+	"\x0ePublishRequest\x128\n" +
+	"\bmessages\x18\x01 \x03(\v2\x1c.queue.v1.PublishRequestItemR\bmessages\"\xff\x02\n" +
+	"\x12PublishRequestItem\x12\x14\n" +
+	"\x05queue\x18\x01 \x01(\tR\x05queue\x12\x18\n" +
+	"\apayload\x18\x02 \x01(\tR\apayload\x12\x1f\n" +
+	"\bpriority\x18\x03 \x01(\x05H\x00R\bpriority\x88\x01\x01\x125\n" +
+	"\bstart_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\astartAt\x12C\n" +
+	"\aheaders\x18\x05 \x03(\v2).queue.v1.PublishRequestItem.HeadersEntryR\aheaders\x12 \n" +
+	"\tdedup_key\x18\x06 \x01(\tH\x01R\bdedupKey\x88\x01\x01\x12\x19\n" +
+	"\x05group\x18\a \x01(\tH\x02R\x05group\x88\x01\x01\x1a:\n" +
+	"\fHeadersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\v\n" +
+	"\t_priorityB\f\n" +
+	"\n" +
+	"_dedup_keyB\b\n" +
+	"\x06_group\"D\n" +
+	"\x0fPublishResponse\x121\n" +
+	"\aresults\x18\x01 \x03(\v2\x17.queue.v1.PublishResultR\aresults\"z\n" +
+	"\rPublishResult\x126\n" +
+	"\amessage\x18\x01 \x01(\v2\x1a.queue.v1.PublishedMessageH\x00R\amessage\x12'\n" +
+	"\x05error\x18\x02 \x01(\v2\x0f.queue.v1.ErrorH\x00R\x05errorB\b\n" +
+	"\x06result\"@\n" +
+	"\x10PublishedMessage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1c\n" +
+	"\tduplicate\x18\x02 \x01(\bR\tduplicate\"\"\n" +
+	"\x0eReleaseRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\"m\n" +
+	"\x0eConsumeRequest\x12\x14\n" +
+	"\x05queue\x18\x01 \x01(\tR\x05queue\x12\x19\n" +
+	"\x05limit\x18\x02 \x01(\x05H\x00R\x05limit\x88\x01\x01\x12\x17\n" +
+	"\x04poll\x18\x03 \x01(\x05H\x01R\x04poll\x88\x01\x01B\b\n" +
+	"\x06_limitB\a\n" +
+	"\x05_poll\"H\n" +
+	"\x0fConsumeResponse\x125\n" +
+	"\bmessages\x18\x01 \x03(\v2\x19.queue.v1.ConsumedMessageR\bmessages\"_\n" +
+	"\x14ConsumeStreamRequest\x12\x14\n" +
+	"\x05queue\x18\x01 \x01(\tR\x05queue\x12\"\n" +
+	"\n" +
+	"batch_size\x18\x02 \x01(\x05H\x00R\tbatchSize\x88\x01\x01B\r\n" +
+	"\v_batch_size\"\x91\x02\n" +
+	"\x0fConsumedMessage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"attempt_id\x18\x02 \x01(\tR\tattemptId\x12\x18\n" +
+	"\apayload\x18\x03 \x01(\tR\apayload\x12&\n" +
+	"\ftrace_parent\x18\x04 \x01(\tH\x00R\vtraceParent\x88\x01\x01\x12@\n" +
+	"\aheaders\x18\x05 \x03(\v2&.queue.v1.ConsumedMessage.HeadersEntryR\aheaders\x1a:\n" +
+	"\fHeadersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\x0f\n" +
+	"\r_trace_parent\"B\n" +
+	"\n" +
+	"AckRequest\x124\n" +
+	"\bmessages\x18\x01 \x03(\v2\x18.queue.v1.AckRequestItemR\bmessages\"Y\n" +
+	"\x0eAckRequestItem\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"attempt_id\x18\x02 \x01(\tR\tattemptId\x12\x18\n" +
+	"\arelease\x18\x03 \x03(\tR\arelease\"D\n" +
+	"\vNackRequest\x125\n" +
+	"\bmessages\x18\x01 \x03(\v2\x19.queue.v1.NackRequestItemR\bmessages\"q\n" +
+	"\x0fNackRequestItem\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"attempt_id\x18\x02 \x01(\tR\tattemptId\x12!\n" +
+	"\tredeliver\x18\x03 \x01(\bH\x00R\tredeliver\x88\x01\x01B\f\n" +
+	"\n" +
+	"_redeliver\"L\n" +
+	"\x0fRedirectRequest\x129\n" +
+	"\bmessages\x18\x01 \x03(\v2\x1d.queue.v1.RedirectRequestItemR\bmessages\"f\n" +
+	"\x13RedirectRequestItem\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"attempt_id\x18\x02 \x01(\tR\tattemptId\x12 \n" +
+	"\vdestination\x18\x03 \x01(\tR\vdestination\"H\n" +
+	"\rExtendRequest\x127\n" +
+	"\bmessages\x18\x01 \x03(\v2\x1b.queue.v1.ExtendRequestItemR\bmessages\"p\n" +
+	"\x11ExtendRequestItem\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"attempt_id\x18\x02 \x01(\tR\tattemptId\x12\x1f\n" +
+	"\bduration\x18\x03 \x01(\x05H\x00R\bduration\x88\x01\x01B\v\n" +
+	"\t_duration\" \n" +
+	"\fCheckRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\">\n" +
+	"\rCheckResponse\x12-\n" +
+	"\bmessages\x18\x01 \x03(\v2\x11.queue.v1.MessageR\bmessages\"\xdb\x03\n" +
+	"\aMessage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05queue\x18\x02 \x01(\tR\x05queue\x129\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12=\n" +
+	"\ffinalized_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\vfinalizedAt\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1a\n" +
+	"\bpriority\x18\x06 \x01(\x05R\bpriority\x12\x18\n" +
+	"\aretries\x18\a \x01(\x05R\aretries\x12\x1e\n" +
+	"\n" +
+	"generation\x18\b \x01(\x05R\n" +
+	"generation\x122\n" +
+	"\ahistory\x18\t \x03(\v2\x18.queue.v1.MessageChapterR\ahistory\x12\x18\n" +
+	"\apayload\x18\n" +
+	" \x01(\tR\apayload\x128\n" +
+	"\aheaders\x18\v \x03(\v2\x1e.queue.v1.Message.HeadersEntryR\aheaders\x1a:\n" +
+	"\fHeadersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xbd\x01\n" +
+	"\x0eMessageChapter\x12\x1e\n" +
+	"\n" +
+	"generation\x18\x01 \x01(\x05R\n" +
+	"generation\x12\x14\n" +
+	"\x05queue\x18\x02 \x01(\tR\x05queue\x12?\n" +
+	"\rredirected_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\fredirectedAt\x12\x1a\n" +
+	"\bpriority\x18\x04 \x01(\x05R\bpriority\x12\x18\n" +
+	"\aretries\x18\x05 \x01(\x05R\aretries2\xef\x04\n" +
+	"\fQueueService\x12>\n" +
+	"\aPublish\x12\x18.queue.v1.PublishRequest\x1a\x19.queue.v1.PublishResponse\x12>\n" +
+	"\aPrepare\x12\x18.queue.v1.PublishRequest\x1a\x19.queue.v1.PublishResponse\x129\n" +
+	"\aRelease\x12\x18.queue.v1.ReleaseRequest\x1a\x14.queue.v1.OkResponse\x12>\n" +
+	"\aConsume\x12\x18.queue.v1.ConsumeRequest\x1a\x19.queue.v1.ConsumeResponse\x12L\n" +
+	"\rConsumeStream\x12\x1e.queue.v1.ConsumeStreamRequest\x1a\x19.queue.v1.ConsumedMessage0\x01\x121\n" +
+	"\x03Ack\x12\x14.queue.v1.AckRequest\x1a\x14.queue.v1.OkResponse\x123\n" +
+	"\x04Nack\x12\x15.queue.v1.NackRequest\x1a\x14.queue.v1.OkResponse\x12;\n" +
+	"\bRedirect\x12\x19.queue.v1.RedirectRequest\x1a\x14.queue.v1.OkResponse\x127\n" +
+	"\x06Extend\x12\x17.queue.v1.ExtendRequest\x1a\x14.queue.v1.OkResponse\x128\n" +
+	"\x05Check\x12\x16.queue.v1.CheckRequest\x1a\x17.queue.v1.CheckResponseB\x14Z\x12server/pkg/grpcapib\x06proto3"
+
+var (
+	file_queue_proto_rawDescOnce sync.Once
+	file_queue_proto_rawDescData []byte
+)
+
+func file_queue_proto_rawDescGZIP() []byte {
+	file_queue_proto_rawDescOnce.Do(func() {
+		file_queue_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_queue_proto_rawDesc), len(file_queue_proto_rawDesc)))
+	})
+	return file_queue_proto_rawDescData
+}
+
+var file_queue_proto_msgTypes = make([]protoimpl.MessageInfo, 27)
+var file_queue_proto_goTypes = []any{
+	(*Error)(nil),                 // 0: queue.v1.Error
+	(*OkResponse)(nil),            // 1: queue.v1.OkResponse
+	(*PublishRequest)(nil),        // 2: queue.v1.PublishRequest
+	(*PublishRequestItem)(nil),    // 3: queue.v1.PublishRequestItem
+	(*PublishResponse)(nil),       // 4: queue.v1.PublishResponse
+	(*PublishResult)(nil),         // 5: queue.v1.PublishResult
+	(*PublishedMessage)(nil),      // 6: queue.v1.PublishedMessage
+	(*ReleaseRequest)(nil),        // 7: queue.v1.ReleaseRequest
+	(*ConsumeRequest)(nil),        // 8: queue.v1.ConsumeRequest
+	(*ConsumeResponse)(nil),       // 9: queue.v1.ConsumeResponse
+	(*ConsumeStreamRequest)(nil),  // 10: queue.v1.ConsumeStreamRequest
+	(*ConsumedMessage)(nil),       // 11: queue.v1.ConsumedMessage
+	(*AckRequest)(nil),            // 12: queue.v1.AckRequest
+	(*AckRequestItem)(nil),        // 13: queue.v1.AckRequestItem
+	(*NackRequest)(nil),           // 14: queue.v1.NackRequest
+	(*NackRequestItem)(nil),       // 15: queue.v1.NackRequestItem
+	(*RedirectRequest)(nil),       // 16: queue.v1.RedirectRequest
+	(*RedirectRequestItem)(nil),   // 17: queue.v1.RedirectRequestItem
+	(*ExtendRequest)(nil),         // 18: queue.v1.ExtendRequest
+	(*ExtendRequestItem)(nil),     // 19: queue.v1.ExtendRequestItem
+	(*CheckRequest)(nil),          // 20: queue.v1.CheckRequest
+	(*CheckResponse)(nil),         // 21: queue.v1.CheckResponse
+	(*Message)(nil),               // 22: queue.v1.Message
+	(*MessageChapter)(nil),        // 23: queue.v1.MessageChapter
+	nil,                           // 24: queue.v1.PublishRequestItem.HeadersEntry
+	nil,                           // 25: queue.v1.ConsumedMessage.HeadersEntry
+	nil,                           // 26: queue.v1.Message.HeadersEntry
+	(*timestamppb.Timestamp)(nil), // 27: google.protobuf.Timestamp
+}
+var file_queue_proto_depIdxs = []int32{
+	3,  // 0: queue.v1.PublishRequest.messages:type_name -> queue.v1.PublishRequestItem
+	27, // 1: queue.v1.PublishRequestItem.start_at:type_name -> google.protobuf.Timestamp
+	24, // 2: queue.v1.PublishRequestItem.headers:type_name -> queue.v1.PublishRequestItem.HeadersEntry
+	5,  // 3: queue.v1.PublishResponse.results:type_name -> queue.v1.PublishResult
+	6,  // 4: queue.v1.PublishResult.message:type_name -> queue.v1.PublishedMessage
+	0,  // 5: queue.v1.PublishResult.error:type_name -> queue.v1.Error
+	11, // 6: queue.v1.ConsumeResponse.messages:type_name -> queue.v1.ConsumedMessage
+	25, // 7: queue.v1.ConsumedMessage.headers:type_name -> queue.v1.ConsumedMessage.HeadersEntry
+	13, // 8: queue.v1.AckRequest.messages:type_name -> queue.v1.AckRequestItem
+	15, // 9: queue.v1.NackRequest.messages:type_name -> queue.v1.NackRequestItem
+	17, // 10: queue.v1.RedirectRequest.messages:type_name -> queue.v1.RedirectRequestItem
+	19, // 11: queue.v1.ExtendRequest.messages:type_name -> queue.v1.ExtendRequestItem
+	22, // 12: queue.v1.CheckResponse.messages:type_name -> queue.v1.Message
+	27, // 13: queue.v1.Message.created_at:type_name -> google.protobuf.Timestamp
+	27, // 14: queue.v1.Message.finalized_at:type_name -> google.protobuf.Timestamp
+	23, // 15: queue.v1.Message.history:type_name -> queue.v1.MessageChapter
+	26, // 16: queue.v1.Message.headers:type_name -> queue.v1.Message.HeadersEntry
+	27, // 17: queue.v1.MessageChapter.redirected_at:type_name -> google.protobuf.Timestamp
+	2,  // 18: queue.v1.QueueService.Publish:input_type -> queue.v1.PublishRequest
+	2,  // 19: queue.v1.QueueService.Prepare:input_type -> queue.v1.PublishRequest
+	7,  // 20: queue.v1.QueueService.Release:input_type -> queue.v1.ReleaseRequest
+	8,  // 21: queue.v1.QueueService.Consume:input_type -> queue.v1.ConsumeRequest
+	10, // 22: queue.v1.QueueService.ConsumeStream:input_type -> queue.v1.ConsumeStreamRequest
+	12, // 23: queue.v1.QueueService.Ack:input_type -> queue.v1.AckRequest
+	14, // 24: queue.v1.QueueService.Nack:input_type -> queue.v1.NackRequest
+	16, // 25: queue.v1.QueueService.Redirect:input_type -> queue.v1.RedirectRequest
+	18, // 26: queue.v1.QueueService.Extend:input_type -> queue.v1.ExtendRequest
+	20, // 27: queue.v1.QueueService.Check:input_type -> queue.v1.CheckRequest
+	4,  // 28: queue.v1.QueueService.Publish:output_type -> queue.v1.PublishResponse
+	4,  // 29: queue.v1.QueueService.Prepare:output_type -> queue.v1.PublishResponse
+	1,  // 30: queue.v1.QueueService.Release:output_type -> queue.v1.OkResponse
+	9,  // 31: queue.v1.QueueService.Consume:output_type -> queue.v1.ConsumeResponse
+	11, // 32: queue.v1.QueueService.ConsumeStream:output_type -> queue.v1.ConsumedMessage
+	1,  // 33: queue.v1.QueueService.Ack:output_type -> queue.v1.OkResponse
+	1,  // 34: queue.v1.QueueService.Nack:output_type -> queue.v1.OkResponse
+	1,  // 35: queue.v1.QueueService.Redirect:output_type -> queue.v1.OkResponse
+	1,  // 36: queue.v1.QueueService.Extend:output_type -> queue.v1.OkResponse
+	21, // 37: queue.v1.QueueService.Check:output_type -> queue.v1.CheckResponse
+	28, // [28:38] is the sub-list for method output_type
+	18, // [18:28] is the sub-list for method input_type
+	18, // [18:18] is the sub-list for extension type_name
+	18, // [18:18] is the sub-list for extension extendee
+	0,  // [0:18] is the sub-list for field type_name
+}
+
+func init() { file_queue_proto_init() }
+func file_queue_proto_init() {
+	if File_queue_proto != nil {
+		return
+	}
+	file_queue_proto_msgTypes[3].OneofWrappers = []any{}
+	file_queue_proto_msgTypes[5].OneofWrappers = []any{
+		(*PublishResult_Message)(nil),
+		(*PublishResult_Error)(nil),
+	}
+	file_queue_proto_msgTypes[8].OneofWrappers = []any{}
+	file_queue_proto_msgTypes[10].OneofWrappers = []any{}
+	file_queue_proto_msgTypes[11].OneofWrappers = []any{}
+	file_queue_proto_msgTypes[15].OneofWrappers = []any{}
+	file_queue_proto_msgTypes[19].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_queue_proto_rawDesc), len(file_queue_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   27,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_queue_proto_goTypes,
+		DependencyIndexes: file_queue_proto_depIdxs,
+		MessageInfos:      file_queue_proto_msgTypes,
+	}.Build()
+	File_queue_proto = out.File
+	file_queue_proto_goTypes = nil
+	file_queue_proto_depIdxs = nil
+}
@@ -18,8 +18,10 @@ type HTTPDoer interface {
 }
 
 type Client struct {
-	baseURL  string
-	httpDoer HTTPDoer
+	baseURL     string
+	httpDoer    HTTPDoer
+	apiKey      string
+	traceParent string
 }
 
 func NewClient(baseURL string, httpDoer HTTPDoer) *Client {
@@ -34,6 +36,20 @@ func NewClient(baseURL string, httpDoer HTTPDoer) *Client {
 	}
 }
 
+// WithAPIKey returns a copy of the client that authenticates with the given API key.
+func (c *Client) WithAPIKey(apiKey string) *Client {
+	clone := *c
+	clone.apiKey = apiKey
+	return &clone
+}
+
+// WithTraceParent returns a copy of the client that sends requests as part of the given W3C trace.
+func (c *Client) WithTraceParent(traceParent string) *Client {
+	clone := *c
+	clone.traceParent = traceParent
+	return &clone
+}
+
 func (c *Client) PrepareMessages(reqDTO httpmodels.PublishRequest) (*httpmodels.PublishResponse, error) {
 	var respDTO httpmodels.PublishResponse
 
@@ -74,6 +90,66 @@ func (c *Client) CheckMessages(reqDTO httpmodels.CheckRequest) (httpmodels.Check
 	return respDTO, nil
 }
 
+func (c *Client) ListMessages(reqDTO httpmodels.ListRequest) (*httpmodels.ListResponse, error) {
+	var respDTO httpmodels.ListResponse
+
+	if err := c.doRequest("/messages/list", reqDTO, &respDTO); err != nil {
+		return nil, err
+	}
+
+	return &respDTO, nil
+}
+
+func (c *Client) RedriveDLQ(reqDTO httpmodels.RedriveRequest) (*httpmodels.RedriveResponse, error) {
+	var respDTO httpmodels.RedriveResponse
+
+	if err := c.doRequest("/messages/redrive", reqDTO, &respDTO); err != nil {
+		return nil, err
+	}
+
+	return &respDTO, nil
+}
+
+func (c *Client) GetQueueStats(reqDTO httpmodels.StatsRequest) (*httpmodels.StatsResponse, error) {
+	var respDTO httpmodels.StatsResponse
+
+	if err := c.doRequest("/queues/stats", reqDTO, &respDTO); err != nil {
+		return nil, err
+	}
+
+	return &respDTO, nil
+}
+
+func (c *Client) PauseQueue(reqDTO httpmodels.PauseRequest) error {
+	var respDTO httpmodels.OkResponse
+
+	if err := c.doRequest("/queues/pause", reqDTO, &respDTO); err != nil {
+		return err
+	}
+
+	return c.checkOkResponse(respDTO)
+}
+
+func (c *Client) ResumeQueue(reqDTO httpmodels.ResumeRequest) error {
+	var respDTO httpmodels.OkResponse
+
+	if err := c.doRequest("/queues/resume", reqDTO, &respDTO); err != nil {
+		return err
+	}
+
+	return c.checkOkResponse(respDTO)
+}
+
+func (c *Client) PurgeQueue(reqDTO httpmodels.PurgeRequest) (*httpmodels.PurgeResponse, error) {
+	var respDTO httpmodels.PurgeResponse
+
+	if err := c.doRequest("/queues/purge", reqDTO, &respDTO); err != nil {
+		return nil, err
+	}
+
+	return &respDTO, nil
+}
+
 func (c *Client) ConsumeMessages(reqDTO httpmodels.ConsumeRequest) (httpmodels.ConsumeResponse, error) {
 	var respDTO httpmodels.ConsumeResponse
 
@@ -104,6 +180,16 @@ func (c *Client) NackMessages(reqDTO httpmodels.NackRequest) error {
 	return c.checkOkResponse(respDTO)
 }
 
+func (c *Client) ExtendMessages(reqDTO httpmodels.ExtendRequest) error {
+	var respDTO httpmodels.OkResponse
+
+	if err := c.doRequest("/messages/extend", reqDTO, &respDTO); err != nil {
+		return err
+	}
+
+	return c.checkOkResponse(respDTO)
+}
+
 func (c *Client) RedirectMessages(reqDTO httpmodels.RedirectRequest) error {
 	var respDTO httpmodels.OkResponse
 
@@ -131,6 +217,12 @@ func (c *Client) doRequest(method string, reqDTO any, respDTO any) error {
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	if c.traceParent != "" {
+		req.Header.Set("traceparent", c.traceParent)
+	}
 
 	resp, err := c.httpDoer.Do(req)
 	if err != nil {
@@ -6,6 +6,8 @@ type QueueName = string
 
 type MessageID = string
 
+type AttemptID = string
+
 type MessageStatus string
 
 const (
@@ -17,6 +19,17 @@ const (
 	MsgStatusDropped    MessageStatus = "DROPPED"
 )
 
+func AllMessageStatuses() []MessageStatus {
+	return []MessageStatus{
+		MsgStatusPrepared,
+		MsgStatusAvailable,
+		MsgStatusProcessing,
+		MsgStatusDelayed,
+		MsgStatusDelivered,
+		MsgStatusDropped,
+	}
+}
+
 type MessageChapter struct {
 	Generation   int       `json:"generation"`
 	Queue        QueueName `json:"queue"`
@@ -26,16 +39,17 @@ type MessageChapter struct {
 }
 
 type Message struct {
-	ID          MessageID        `json:"id"`
-	Queue       QueueName        `json:"queue"`
-	CreatedAt   time.Time        `json:"created_at"`
-	FinalizedAt *time.Time       `json:"finalized_at"`
-	Status      MessageStatus    `json:"status"`
-	Priority    int              `json:"priority"`
-	Retries     int              `json:"retries"`
-	Generation  int              `json:"generation"`
-	History     []MessageChapter `json:"history"`
-	Payload     string           `json:"payload"`
+	ID          MessageID         `json:"id"`
+	Queue       QueueName         `json:"queue"`
+	CreatedAt   time.Time         `json:"created_at"`
+	FinalizedAt *time.Time        `json:"finalized_at"`
+	Status      MessageStatus     `json:"status"`
+	Priority    int               `json:"priority"`
+	Retries     int               `json:"retries"`
+	Generation  int               `json:"generation"`
+	History     []MessageChapter  `json:"history"`
+	Payload     string            `json:"payload"`
+	Headers     map[string]string `json:"headers"`
 }
 
 type BatchResult[T any] struct {
@@ -14,6 +14,10 @@ const (
 	ErrorCodeRequestInvalid   ErrorCode = "request_invalid"
 	ErrorCodeBatchSizeTooBig  ErrorCode = "batch_size_too_big"
 	ErrorCodeQueueNotWritable ErrorCode = "queue_not_writable"
+	ErrorCodeAttemptMismatch  ErrorCode = "attempt_mismatch"
+	ErrorCodeMaxLeaseReached  ErrorCode = "max_lease_reached"
+	ErrorCodeUnauthorized     ErrorCode = "unauthorized"
+	ErrorCodeForbidden        ErrorCode = "forbidden"
 )
 
 type Error struct {
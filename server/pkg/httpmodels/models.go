@@ -2,14 +2,17 @@ package httpmodels
 
 import (
 	"errors"
+	"fmt"
+	"slices"
 	"time"
 )
 
 type AckRequest []AckRequestItem
 
 type AckRequestItem struct {
-	ID      MessageID   `json:"id"`
-	Release []MessageID `json:"release,omitempty"`
+	ID        MessageID   `json:"id"`
+	AttemptID AttemptID   `json:"attempt_id"`
+	Release   []MessageID `json:"release,omitempty"`
 }
 
 func (items AckRequest) Validate() error {
@@ -22,6 +25,10 @@ func (items AckRequest) Validate() error {
 			return errors.New("field 'id' must not be empty")
 		}
 
+		if item.AttemptID == "" {
+			return errors.New("field 'attempt_id' must not be empty")
+		}
+
 		for _, id := range item.Release {
 			if id == "" {
 				return errors.New("every element inside 'release' must be non-empty string")
@@ -50,6 +57,137 @@ func (items CheckRequest) Validate() error {
 
 type CheckResponse = []Message
 
+type ListRequest struct {
+	Queue       QueueName       `json:"queue"`
+	Statuses    []MessageStatus `json:"statuses,omitempty"`
+	MinPriority *int            `json:"min_priority,omitempty"`
+	MaxPriority *int            `json:"max_priority,omitempty"`
+	CreatedFrom *time.Time      `json:"created_from,omitempty"` // inclusive
+	CreatedTo   *time.Time      `json:"created_to,omitempty"`   // exclusive
+	MinRetries  *int            `json:"min_retries,omitempty"`
+	MaxRetries  *int            `json:"max_retries,omitempty"`
+	Cursor      *string         `json:"cursor,omitempty"`
+	Limit       *int            `json:"limit,omitempty"`
+}
+
+func (r ListRequest) Validate() error {
+	if r.Queue == "" {
+		return errors.New("field 'queue' required")
+	}
+
+	for _, status := range r.Statuses {
+		if !slices.Contains(AllMessageStatuses(), status) {
+			return fmt.Errorf("unknown status '%s'", status)
+		}
+	}
+
+	if r.Limit != nil && *r.Limit < 1 {
+		return errors.New("field 'limit' must be greater than 0")
+	}
+
+	return nil
+}
+
+type ListResponse struct {
+	Messages   []Message `json:"messages"`
+	NextCursor *string   `json:"next_cursor"` // null on the last page
+}
+
+type RedriveRequest struct {
+	Queue       QueueName  `json:"queue"` // the DLQ to redrive from
+	MinPriority *int       `json:"min_priority,omitempty"`
+	MaxPriority *int       `json:"max_priority,omitempty"`
+	CreatedFrom *time.Time `json:"created_from,omitempty"` // inclusive
+	CreatedTo   *time.Time `json:"created_to,omitempty"`   // exclusive
+	Limit       *int       `json:"limit,omitempty"`        // all matching messages if not set
+}
+
+func (r RedriveRequest) Validate() error {
+	if r.Queue == "" {
+		return errors.New("field 'queue' required")
+	}
+
+	if r.Limit != nil && *r.Limit < 1 {
+		return errors.New("field 'limit' must be greater than 0")
+	}
+
+	return nil
+}
+
+type RedriveResponse struct {
+	Redriven int `json:"redriven"`
+	Skipped  int `json:"skipped"` // source queue is not configured anymore
+}
+
+type StatsRequest struct {
+	Queues []QueueName `json:"queues,omitempty"` // all queues the caller administers if not set
+}
+
+func (r StatsRequest) Validate() error {
+	return nil
+}
+
+type StatsResponse struct {
+	CollectedAt time.Time    `json:"collected_at"` // older than the request if stats are cached
+	Queues      []QueueStats `json:"queues"`
+}
+
+type QueueStats struct {
+	Queue              QueueName             `json:"queue"`
+	Messages           map[MessageStatus]int `json:"messages"`             // archived messages are not counted
+	OldestAvailableAge *int                  `json:"oldest_available_age"` // in seconds, null if there are no AVAILABLE messages
+	DelayedDueSoon     int                   `json:"delayed_due_soon"`     // DELAYED messages becoming available within a minute
+	DLQSize            *int                  `json:"dlq_size"`             // null if dead-lettering is off
+}
+
+type PauseRequest struct {
+	Queue QueueName `json:"queue"`
+}
+
+func (r PauseRequest) Validate() error {
+	if r.Queue == "" {
+		return errors.New("field 'queue' required")
+	}
+
+	return nil
+}
+
+type ResumeRequest struct {
+	Queue QueueName `json:"queue"`
+}
+
+func (r ResumeRequest) Validate() error {
+	if r.Queue == "" {
+		return errors.New("field 'queue' required")
+	}
+
+	return nil
+}
+
+type PurgeRequest struct {
+	Queue    QueueName       `json:"queue"`
+	Statuses []MessageStatus `json:"statuses,omitempty"` // any status if not set
+	Archive  bool            `json:"archive,omitempty"`  // archive purged messages as DROPPED instead of deleting
+}
+
+func (r PurgeRequest) Validate() error {
+	if r.Queue == "" {
+		return errors.New("field 'queue' required")
+	}
+
+	for _, status := range r.Statuses {
+		if !slices.Contains(AllMessageStatuses(), status) {
+			return fmt.Errorf("unknown status '%s'", status)
+		}
+	}
+
+	return nil
+}
+
+type PurgeResponse struct {
+	Purged int `json:"purged"`
+}
+
 type ConsumeRequest struct {
 	Queue QueueName `json:"queue"`
 	Limit *int      `json:"limit,omitempty"`
@@ -75,14 +213,48 @@ func (r ConsumeRequest) Validate() error {
 type ConsumeResponse = []ConsumeResponseItem
 
 type ConsumeResponseItem struct {
-	ID      MessageID `json:"id"`
-	Payload string    `json:"payload"`
+	ID          MessageID         `json:"id"`
+	AttemptID   AttemptID         `json:"attempt_id"`
+	Payload     string            `json:"payload"`
+	Headers     map[string]string `json:"headers"`
+	TraceParent *string           `json:"trace_parent,omitempty"`
+}
+
+type ExtendRequest []ExtendRequestItem
+
+type ExtendRequestItem struct {
+	ID        MessageID `json:"id"`
+	AttemptID AttemptID `json:"attempt_id"`
+	Duration  *int      `json:"duration,omitempty"`
+}
+
+func (items ExtendRequest) Validate() error {
+	if len(items) == 0 {
+		return errors.New("at least one message must be specified")
+	}
+
+	for _, el := range items {
+		if el.ID == "" {
+			return errors.New("field 'id' must not be empty")
+		}
+
+		if el.AttemptID == "" {
+			return errors.New("field 'attempt_id' must not be empty")
+		}
+
+		if el.Duration != nil && *el.Duration < 1 {
+			return errors.New("field 'duration' must be greater than 0")
+		}
+	}
+
+	return nil
 }
 
 type NackRequest []NackRequestItem
 
 type NackRequestItem struct {
 	ID        MessageID `json:"id"`
+	AttemptID AttemptID `json:"attempt_id"`
 	Redeliver *bool     `json:"redeliver,omitempty"`
 }
 
@@ -95,6 +267,10 @@ func (items NackRequest) Validate() error {
 		if el.ID == "" {
 			return errors.New("field 'id' must not be empty")
 		}
+
+		if el.AttemptID == "" {
+			return errors.New("field 'attempt_id' must not be empty")
+		}
 	}
 
 	return nil
@@ -103,10 +279,13 @@ func (items NackRequest) Validate() error {
 type PublishRequest []PublishRequestItem
 
 type PublishRequestItem struct {
-	Queue    QueueName  `json:"queue"`
-	Payload  string     `json:"payload"`
-	Priority *int       `json:"priority,omitempty"`
-	StartAt  *time.Time `json:"startAt,omitempty"`
+	Queue    QueueName         `json:"queue"`
+	Payload  string            `json:"payload"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	Group    *string           `json:"group,omitempty"`
+	Priority *int              `json:"priority,omitempty"`
+	StartAt  *time.Time        `json:"startAt,omitempty"`
+	DedupKey *string           `json:"dedup_key,omitempty"`
 }
 
 func (items PublishRequest) Validate() error {
@@ -132,13 +311,15 @@ type PublishResponse struct {
 }
 
 type PublishedMessage struct {
-	ID MessageID `json:"id"`
+	ID        MessageID `json:"id"`
+	Duplicate bool      `json:"duplicate"`
 }
 
 type RedirectRequest []RedirectRequestItem
 
 type RedirectRequestItem struct {
 	ID          MessageID `json:"id"`
+	AttemptID   AttemptID `json:"attempt_id"`
 	Destination string    `json:"destination"`
 }
 
@@ -152,6 +333,10 @@ func (items RedirectRequest) Validate() error {
 			return errors.New("field 'id' must not be empty")
 		}
 
+		if el.AttemptID == "" {
+			return errors.New("field 'attempt_id' must not be empty")
+		}
+
 		if el.Destination == "" {
 			return errors.New("field 'destination' must not be empty")
 		}
@@ -183,3 +368,11 @@ type ErrorResponse struct {
 type OkResponse struct {
 	Ok bool `json:"ok"`
 }
+
+// WebhookRequest is the body POSTed to webhook URLs of queues in push mode.
+type WebhookRequest struct {
+	ID      MessageID         `json:"id"`
+	Queue   QueueName         `json:"queue"`
+	Payload string            `json:"payload"`
+	Headers map[string]string `json:"headers"`
+}
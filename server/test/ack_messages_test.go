@@ -3,6 +3,7 @@ package test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -27,7 +28,8 @@ func TestAckMessages(t *testing.T) {
 	// Act
 	err := client.AckMessages(httpmodels.AckRequest{
 		httpmodels.AckRequestItem{
-			ID: msgID,
+			ID:        msgID,
+			AttemptID: fixtures.GetAttemptID(app, msgID),
 		},
 	})
 
@@ -56,8 +58,9 @@ func TestAckMessagesAtomicRelease(t *testing.T) {
 	// Act
 	err := client.AckMessages(httpmodels.AckRequest{
 		httpmodels.AckRequestItem{
-			ID:      msgToAckID,
-			Release: []httpmodels.MessageID{msgToReleaseID},
+			ID:        msgToAckID,
+			AttemptID: fixtures.GetAttemptID(app, msgToAckID),
+			Release:   []httpmodels.MessageID{msgToReleaseID},
 		},
 	})
 
@@ -84,10 +87,142 @@ func TestAckUnknownMessage(t *testing.T) {
 	// Act
 	err := client.AckMessages(httpmodels.AckRequest{
 		httpmodels.AckRequestItem{
-			ID: "d8d4d0f7-1bbd-48c0-9f80-c66f5fd45fc2",
+			ID:        "d8d4d0f7-1bbd-48c0-9f80-c66f5fd45fc2",
+			AttemptID: "0f8e5a4c-2d0b-4c0e-9a8e-1f3c5b7d9e21",
 		},
 	})
 
 	// Assert
 	require.True(t, httpclient.IsCode(err, httpmodels.ErrorCodeMessageNotFound))
 }
+
+func TestAckMessagesStaleAttempt(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	msgID := fixtures.CreateProcessingMsg(app)
+	staleAttemptID := fixtures.GetAttemptID(app, msgID)
+
+	// the lease expires and the message gets redelivered to another consumer
+	testkit.AdvanceClock(app, 6*time.Minute)
+	require.NoError(t, app.ExpireProcessing.Do(context.Background()))
+	testkit.AdvanceClock(app, time.Minute)
+	require.NoError(t, app.ResumeDelayed.Do(context.Background()))
+
+	respDTO, err := client.ConsumeMessages(httpmodels.ConsumeRequest{Queue: fixtures.DefaultMsgQueue})
+	require.NoError(t, err)
+	require.Len(t, respDTO, 1)
+	require.NotEqual(t, staleAttemptID, respDTO[0].AttemptID)
+
+	// Act
+	err = client.AckMessages(httpmodels.AckRequest{
+		httpmodels.AckRequestItem{
+			ID:        msgID,
+			AttemptID: staleAttemptID,
+		},
+	})
+
+	// Assert response
+	require.True(t, httpclient.IsCode(err, httpmodels.ErrorCodeAttemptMismatch))
+
+	// Assert the message in DB
+	message, err := app.MsgRepo.GetByID(context.Background(), app.DB, msgID)
+	require.NoError(t, err)
+	require.Equal(t, domain.MsgStatusProcessing, message.Status())
+}
+
+func TestAckMessagesBatch(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	var msgIDs []string
+	request := httpmodels.AckRequest{}
+	for range 100 {
+		msgID := fixtures.CreateProcessingMsg(app)
+		msgIDs = append(msgIDs, msgID)
+		request = append(request, httpmodels.AckRequestItem{
+			ID:        msgID,
+			AttemptID: fixtures.GetAttemptID(app, msgID),
+		})
+	}
+
+	// Act
+	err := client.AckMessages(request)
+
+	// Assert
+	require.NoError(t, err)
+
+	for _, msgID := range msgIDs {
+		message, err := app.MsgRepo.GetByID(context.Background(), app.DB, msgID)
+		require.NoError(t, err)
+		require.Equal(t, domain.MsgStatusDelivered, message.Status())
+	}
+}
+
+func TestAckMessagesBatchWithUnknownMessage(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	msgID := fixtures.CreateProcessingMsg(app)
+
+	// Act
+	err := client.AckMessages(httpmodels.AckRequest{
+		httpmodels.AckRequestItem{
+			ID:        msgID,
+			AttemptID: fixtures.GetAttemptID(app, msgID),
+		},
+		httpmodels.AckRequestItem{
+			ID:        "d8d4d0f7-1bbd-48c0-9f80-c66f5fd45fc2",
+			AttemptID: "0f8e5a4c-2d0b-4c0e-9a8e-1f3c5b7d9e21",
+		},
+	})
+
+	// Assert: the batch is rejected as a whole
+	require.True(t, httpclient.IsCode(err, httpmodels.ErrorCodeMessageNotFound))
+
+	message, err := app.MsgRepo.GetByID(context.Background(), app.DB, msgID)
+	require.NoError(t, err)
+	require.Equal(t, domain.MsgStatusProcessing, message.Status())
+}
+
+func TestAckMessagesBatchWithInvalidID(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	msgID := fixtures.CreateProcessingMsg(app)
+
+	// Act
+	err := client.AckMessages(httpmodels.AckRequest{
+		httpmodels.AckRequestItem{
+			ID:        msgID,
+			AttemptID: fixtures.GetAttemptID(app, msgID),
+		},
+		httpmodels.AckRequestItem{
+			ID:        "not-a-uuid",
+			AttemptID: "0f8e5a4c-2d0b-4c0e-9a8e-1f3c5b7d9e21",
+		},
+	})
+
+	// Assert: an ID that isn't a UUID is reported as an unknown message
+	require.True(t, httpclient.IsCode(err, httpmodels.ErrorCodeMessageNotFound))
+
+	message, err := app.MsgRepo.GetByID(context.Background(), app.DB, msgID)
+	require.NoError(t, err)
+	require.Equal(t, domain.MsgStatusProcessing, message.Status())
+}
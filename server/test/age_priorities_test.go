@@ -0,0 +1,87 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"server/internal/utils"
+	"server/internal/utils/testutils"
+	"server/pkg/httpmodels"
+	"server/test/fixtures"
+	"server/test/testkit"
+)
+
+func TestAgePriorities(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig(testkit.WithPriorityAging(time.Minute, 10, 150)))
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	lowMsgID := fixtures.CreateAvailableMsg(app, fixtures.WithPriority(100))
+	highMsgID := fixtures.CreateAvailableMsg(app, fixtures.WithPriority(200))
+	testkit.AdvanceClock(app, 3*time.Minute+30*time.Second)
+
+	// Act
+	err := app.AgePriorities.Do(context.Background())
+	require.NoError(t, err)
+
+	// Assert
+	lowMsg, err := app.MsgRepo.GetByID(context.Background(), app.DB, lowMsgID)
+	require.NoError(t, err)
+	require.Equal(t, 130, lowMsg.Priority().Int())
+
+	highMsg, err := app.MsgRepo.GetByID(context.Background(), app.DB, highMsgID)
+	require.NoError(t, err)
+	require.Equal(t, 200, highMsg.Priority().Int())
+}
+
+func TestAgePrioritiesOvertakesNewMessages(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig(testkit.WithPriorityAging(time.Minute, 10, 255)))
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	oldMsgID := fixtures.CreateAvailableMsg(app, fixtures.WithPriority(100))
+	testkit.AdvanceClock(app, 5*time.Minute)
+	fixtures.CreateAvailableMsg(app, fixtures.WithPriority(140))
+
+	err := app.AgePriorities.Do(context.Background())
+	require.NoError(t, err)
+
+	// Act
+	respDTO, err := client.ConsumeMessages(httpmodels.ConsumeRequest{
+		Queue: fixtures.DefaultMsgQueue,
+		Limit: utils.P(1),
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, respDTO, 1)
+	require.Equal(t, oldMsgID, respDTO[0].ID)
+}
+
+func TestAgePrioritiesWithoutPolicy(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	msgID := fixtures.CreateAvailableMsg(app, fixtures.WithPriority(100))
+	testkit.AdvanceClock(app, 24*time.Hour)
+
+	// Act
+	err := app.AgePriorities.Do(context.Background())
+	require.NoError(t, err)
+
+	// Assert
+	msg, err := app.MsgRepo.GetByID(context.Background(), app.DB, msgID)
+	require.NoError(t, err)
+	require.Equal(t, 100, msg.Priority().Int())
+}
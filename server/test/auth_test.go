@@ -0,0 +1,191 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"server/internal/auth"
+	"server/internal/domain"
+	"server/internal/utils/testutils"
+	"server/pkg/httpclient"
+	"server/pkg/httpmodels"
+	"server/test/fixtures"
+	"server/test/testkit"
+)
+
+const (
+	producerKey = "producer-secret"
+	consumerKey = "consumer-secret"
+	operatorKey = "operator-secret"
+)
+
+func newAuthAppConfigOptions() []testkit.ConfigOption {
+	return []testkit.ConfigOption{
+		testkit.WithAPIKey("producer", producerKey, map[auth.Action][]string{
+			auth.ActionPublish: {"test"},
+		}),
+		testkit.WithAPIKey("consumer", consumerKey, map[auth.Action][]string{
+			auth.ActionConsume: {"test"},
+		}),
+		testkit.WithAPIKey("operator", operatorKey, map[auth.Action][]string{
+			auth.ActionAdmin: {"*"},
+		}),
+	}
+}
+
+func TestAuthMissingAPIKey(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig(newAuthAppConfigOptions()...))
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	// Act
+	_, err := client.PublishMessages(httpmodels.PublishRequest{
+		httpmodels.PublishRequestItem{Queue: "test", Payload: `{"arg": 123}`},
+	})
+
+	// Assert
+	require.Error(t, err)
+	require.True(t, httpclient.IsCode(err, httpmodels.ErrorCodeUnauthorized))
+}
+
+func TestAuthInvalidAPIKey(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig(newAuthAppConfigOptions()...))
+	client := testkit.NewHTTPClient(t, app).WithAPIKey("unknown-secret")
+	testkit.CleanupDatabase(app.DB)
+
+	// Act
+	_, err := client.PublishMessages(httpmodels.PublishRequest{
+		httpmodels.PublishRequestItem{Queue: "test", Payload: `{"arg": 123}`},
+	})
+
+	// Assert
+	require.Error(t, err)
+	require.True(t, httpclient.IsCode(err, httpmodels.ErrorCodeUnauthorized))
+}
+
+func TestAuthPublishScopedToQueue(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig(newAuthAppConfigOptions()...))
+	client := testkit.NewHTTPClient(t, app).WithAPIKey(producerKey)
+	testkit.CleanupDatabase(app.DB)
+
+	// Act
+	respDTO, err := client.PublishMessages(httpmodels.PublishRequest{
+		httpmodels.PublishRequestItem{Queue: "test", Payload: `{"arg": 123}`},
+		httpmodels.PublishRequestItem{Queue: "test.result", Payload: `{"arg": 123}`},
+	})
+
+	// Assert response
+	require.NoError(t, err)
+	require.Len(t, respDTO.Results, 2)
+	require.Nil(t, respDTO.Results[0].Error)
+	require.NotNil(t, respDTO.Results[1].Error)
+	require.True(t, httpclient.IsCode(respDTO.Results[1].Error, httpmodels.ErrorCodeForbidden))
+
+	// Assert the allowed message in DB
+	message, err := app.MsgRepo.GetByID(context.Background(), app.DB, respDTO.Results[0].Data.ID)
+	require.NoError(t, err)
+	require.Equal(t, domain.MsgStatusAvailable, message.Status())
+}
+
+func TestAuthConsumeForbidden(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig(newAuthAppConfigOptions()...))
+	client := testkit.NewHTTPClient(t, app).WithAPIKey(producerKey)
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	fixtures.CreateAvailableMsg(app)
+
+	// Act
+	_, err := client.ConsumeMessages(httpmodels.ConsumeRequest{Queue: "test"})
+
+	// Assert
+	require.Error(t, err)
+	require.True(t, httpclient.IsCode(err, httpmodels.ErrorCodeForbidden))
+}
+
+func TestAuthAckForbidden(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig(newAuthAppConfigOptions()...))
+	client := testkit.NewHTTPClient(t, app).WithAPIKey(producerKey)
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	msgID := fixtures.CreateProcessingMsg(app)
+
+	// Act
+	err := client.AckMessages(httpmodels.AckRequest{
+		httpmodels.AckRequestItem{
+			ID:        msgID,
+			AttemptID: fixtures.GetAttemptID(app, msgID),
+		},
+	})
+
+	// Assert response
+	require.Error(t, err)
+	require.True(t, httpclient.IsCode(err, httpmodels.ErrorCodeForbidden))
+
+	// Assert the message in DB is untouched
+	message, err := app.MsgRepo.GetByID(context.Background(), app.DB, msgID)
+	require.NoError(t, err)
+	require.Equal(t, domain.MsgStatusProcessing, message.Status())
+}
+
+func TestAuthConsumeAndAck(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig(newAuthAppConfigOptions()...))
+	client := testkit.NewHTTPClient(t, app).WithAPIKey(consumerKey)
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	msgID := fixtures.CreateAvailableMsg(app)
+
+	// Act
+	consumed, err := client.ConsumeMessages(httpmodels.ConsumeRequest{Queue: "test"})
+	require.NoError(t, err)
+	require.Len(t, consumed, 1)
+	require.Equal(t, msgID, consumed[0].ID)
+
+	err = client.AckMessages(httpmodels.AckRequest{
+		httpmodels.AckRequestItem{
+			ID:        consumed[0].ID,
+			AttemptID: consumed[0].AttemptID,
+		},
+	})
+
+	// Assert
+	require.NoError(t, err)
+}
+
+func TestAuthAdminImpliesAllActions(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig(newAuthAppConfigOptions()...))
+	client := testkit.NewHTTPClient(t, app).WithAPIKey(operatorKey)
+	testkit.CleanupDatabase(app.DB)
+
+	// Act
+	publishResp, err := client.PublishMessages(httpmodels.PublishRequest{
+		httpmodels.PublishRequestItem{Queue: "test.result", Payload: `{"arg": 123}`},
+	})
+	require.NoError(t, err)
+	require.Nil(t, publishResp.Results[0].Error)
+
+	consumed, err := client.ConsumeMessages(httpmodels.ConsumeRequest{Queue: "test.result"})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, consumed, 1)
+	require.Equal(t, publishResp.Results[0].Data.ID, consumed[0].ID)
+}
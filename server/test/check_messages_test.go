@@ -21,11 +21,12 @@ func TestCheckExistingMessage(t *testing.T) {
 	testkit.CleanupDatabase(app.DB)
 
 	const msgHistoryQueue = "test.result"
+	headers := map[string]string{"content-type": "application/json", "tenant": "acme"}
 
 	// Arrange
-	msg1ID := fixtures.CreateArchivedMsg(app)
+	msg1ID := fixtures.CreateArchivedMsg(app, fixtures.WithHeaders(headers))
 	msg2ID := fixtures.CreatePreparedMsg(app)
-	msg3ID := fixtures.CreateAvailableMsg(app, fixtures.WithHistory(msgHistoryQueue))
+	msg3ID := fixtures.CreateAvailableMsg(app, fixtures.WithHistory(msgHistoryQueue), fixtures.WithHeaders(headers))
 
 	// Act
 	respDTO, err := client.CheckMessages(httpmodels.CheckRequest{msg1ID, msg2ID, msg3ID})
@@ -46,6 +47,7 @@ func TestCheckExistingMessage(t *testing.T) {
 		Generation:  0,
 		History:     []httpmodels.MessageChapter{},
 		Payload:     fixtures.DefaultMsgPayload,
+		Headers:     headers,
 	}, respDTO[0])
 
 	require.Equal(t, httpmodels.Message{
@@ -59,6 +61,7 @@ func TestCheckExistingMessage(t *testing.T) {
 		Generation:  0,
 		History:     []httpmodels.MessageChapter{},
 		Payload:     fixtures.DefaultMsgPayload,
+		Headers:     map[string]string{},
 	}, respDTO[1])
 
 	require.Equal(t, httpmodels.Message{
@@ -80,6 +83,7 @@ func TestCheckExistingMessage(t *testing.T) {
 			},
 		},
 		Payload: fixtures.DefaultMsgPayload,
+		Headers: headers,
 	}, respDTO[2])
 }
 
@@ -2,10 +2,14 @@ package test
 
 import (
 	"context"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
+	"server/internal/appbuilder"
+	"server/internal/config"
 	"server/internal/domain"
 	"server/internal/utils"
 	"server/internal/utils/testutils"
@@ -23,10 +27,16 @@ func TestConsumeMessages(t *testing.T) {
 	testkit.CleanupDatabase(app.DB)
 
 	const msg2Payload = `{"arg": 213}`
+	msg2Headers := map[string]string{"content-type": "application/json"}
 
 	// Arrange
 	msg1ID := fixtures.CreateAvailableMsg(app, fixtures.WithPriority(10))
-	msg2ID := fixtures.CreateAvailableMsg(app, fixtures.WithPriority(200), fixtures.WithPayload(msg2Payload))
+	msg2ID := fixtures.CreateAvailableMsg(
+		app,
+		fixtures.WithPriority(200),
+		fixtures.WithPayload(msg2Payload),
+		fixtures.WithHeaders(msg2Headers),
+	)
 	msg3ID := fixtures.CreateAvailableMsg(app, fixtures.WithPriority(100))
 
 	// Act
@@ -40,8 +50,10 @@ func TestConsumeMessages(t *testing.T) {
 
 	require.Len(t, respDTO, 1)
 	require.Equal(t, httpmodels.ConsumeResponseItem{
-		ID:      msg2ID,
-		Payload: msg2Payload,
+		ID:        msg2ID,
+		AttemptID: fixtures.GetAttemptID(app, msg2ID),
+		Payload:   msg2Payload,
+		Headers:   msg2Headers,
 	}, respDTO[0])
 
 	// Assert messages in DB
@@ -119,8 +131,10 @@ func TestConsumeFromDLQAllowed(t *testing.T) {
 
 	require.Len(t, respDTO, 1)
 	require.Equal(t, httpmodels.ConsumeResponseItem{
-		ID:      msgID,
-		Payload: fixtures.DefaultMsgPayload,
+		ID:        msgID,
+		AttemptID: fixtures.GetAttemptID(app, msgID),
+		Payload:   fixtures.DefaultMsgPayload,
+		Headers:   map[string]string{},
 	}, respDTO[0])
 
 	// Assert messages in DB
@@ -128,3 +142,312 @@ func TestConsumeFromDLQAllowed(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, domain.MsgStatusProcessing, takenMsg.Status())
 }
+
+func TestConsumeMessageGroups(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	consume := func() []string {
+		respDTO, err := client.ConsumeMessages(httpmodels.ConsumeRequest{
+			Queue: fixtures.DefaultMsgQueue,
+			Limit: utils.P(10),
+		})
+		require.NoError(t, err)
+
+		ids := make([]string, 0, len(respDTO))
+		for _, item := range respDTO {
+			ids = append(ids, item.ID)
+		}
+		return ids
+	}
+
+	// Arrange
+	first1ID := fixtures.CreateAvailableMsg(app, fixtures.WithGroup("customer-1"), fixtures.WithPriority(10))
+	testkit.AdvanceClock(app, time.Second)
+	first2ID := fixtures.CreateAvailableMsg(app, fixtures.WithGroup("customer-1"), fixtures.WithPriority(200))
+	second1ID := fixtures.CreateAvailableMsg(app, fixtures.WithGroup("customer-2"))
+	ungroupedID := fixtures.CreateAvailableMsg(app)
+
+	// Act
+	consumed := consume()
+	blocked := consume()
+
+	err := client.AckMessages(httpmodels.AckRequest{{
+		ID:        first1ID,
+		AttemptID: fixtures.GetAttemptID(app, first1ID),
+	}})
+	require.NoError(t, err)
+
+	afterAck := consume()
+
+	// Assert: the higher priority can't overtake an older message of the group
+	require.ElementsMatch(t, []string{first1ID, second1ID, ungroupedID}, consumed)
+	require.Empty(t, blocked)
+	require.Equal(t, []string{first2ID}, afterAck)
+}
+
+func TestConsumeMessageGroupWaitsForRetry(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	first1ID := fixtures.CreateProcessingMsg(app, fixtures.WithGroup("customer-1"))
+	testkit.AdvanceClock(app, time.Second)
+	fixtures.CreateAvailableMsg(app, fixtures.WithGroup("customer-1"))
+
+	err := client.NackMessages(httpmodels.NackRequest{{
+		ID:        first1ID,
+		AttemptID: fixtures.GetAttemptID(app, first1ID),
+	}})
+	require.NoError(t, err)
+
+	// Act
+	respDTO, err := client.ConsumeMessages(httpmodels.ConsumeRequest{
+		Queue: fixtures.DefaultMsgQueue,
+	})
+
+	// Assert: the delayed message keeps its place in the group
+	require.NoError(t, err)
+	require.Empty(t, respDTO)
+
+	first1, err := app.MsgRepo.GetByID(context.Background(), app.DB, first1ID)
+	require.NoError(t, err)
+	require.Equal(t, domain.MsgStatusDelayed, first1.Status())
+}
+
+func TestConsumeMessagesLongPollWithMemoryEventBus(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig(testkit.WithEventBusDriver(config.EventBusDriverMemory)))
+	consumer := testkit.NewHTTPClient(t, app)
+	producer := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() { _ = app.EventBus.Run(ctx) }()
+
+	// Arrange: publish while the consumer is waiting
+	publishErr := make(chan error, 1)
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		_, err := producer.PublishMessages(httpmodels.PublishRequest{
+			{Queue: fixtures.DefaultMsgQueue, Payload: "{}"},
+		})
+		publishErr <- err
+	}()
+
+	// Act
+	startedAt := time.Now()
+	respDTO, err := consumer.ConsumeMessages(httpmodels.ConsumeRequest{
+		Queue: fixtures.DefaultMsgQueue,
+		Poll:  utils.P(5),
+	})
+	pollDuration := time.Since(startedAt)
+
+	// Assert: the consumer is woken up by the notification instead of waiting for the whole poll
+	require.NoError(t, <-publishErr)
+	require.NoError(t, err)
+	require.Len(t, respDTO, 1)
+	require.Less(t, pollDuration, 5*time.Second)
+}
+
+func TestConsumeMessagesOrder(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	oldLowID := fixtures.CreateAvailableMsg(app, fixtures.WithPriority(10))
+	testkit.AdvanceClock(app, time.Second)
+	newHighID := fixtures.CreateAvailableMsg(app, fixtures.WithPriority(50))
+	testkit.AdvanceClock(app, time.Second)
+	newLowID := fixtures.CreateAvailableMsg(app, fixtures.WithPriority(10))
+	testkit.AdvanceClock(app, time.Second)
+	newestHighID := fixtures.CreateAvailableMsg(app, fixtures.WithPriority(50))
+
+	// Act
+	respDTO, err := client.ConsumeMessages(httpmodels.ConsumeRequest{
+		Queue: fixtures.DefaultMsgQueue,
+		Limit: utils.P(10),
+	})
+
+	// Assert: higher priority first, then the ones waiting longer
+	require.NoError(t, err)
+
+	ids := make([]string, 0, len(respDTO))
+	for _, item := range respDTO {
+		ids = append(ids, item.ID)
+	}
+	require.Equal(t, []string{newHighID, newestHighID, oldLowID, newLowID}, ids)
+}
+
+func TestConsumeMessagesSetsProcessingTimeout(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	msgID := fixtures.CreateAvailableMsg(app)
+	testkit.AdvanceClock(app, time.Minute)
+
+	// Act
+	_, err := client.ConsumeMessages(httpmodels.ConsumeRequest{
+		Queue: fixtures.DefaultMsgQueue,
+	})
+	require.NoError(t, err)
+
+	// Assert
+	takenMsg, err := app.MsgRepo.GetByID(context.Background(), app.DB, msgID)
+	require.NoError(t, err)
+
+	require.NotNil(t, takenMsg.TimeoutAt())
+	require.WithinDuration(t, app.Clock.Now().Add(5*time.Minute), *takenMsg.TimeoutAt(), time.Millisecond)
+}
+
+func TestConsumeMessagesNewAttemptOnRetake(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	consume := func() httpmodels.ConsumeResponseItem {
+		respDTO, err := client.ConsumeMessages(httpmodels.ConsumeRequest{
+			Queue: fixtures.DefaultMsgQueue,
+		})
+		require.NoError(t, err)
+		require.Len(t, respDTO, 1)
+		return respDTO[0]
+	}
+
+	// Arrange: the first attempt times out and the message is retried
+	msgID := fixtures.CreateAvailableMsg(app)
+	firstAttempt := consume()
+
+	testkit.AdvanceClock(app, 6*time.Minute)
+	require.NoError(t, app.ExpireProcessing.Do(context.Background()))
+	testkit.AdvanceClock(app, time.Minute)
+	require.NoError(t, app.ResumeDelayed.Do(context.Background()))
+
+	// Act
+	secondAttempt := consume()
+
+	err := client.AckMessages(httpmodels.AckRequest{{
+		ID:        msgID,
+		AttemptID: firstAttempt.AttemptID,
+	}})
+
+	// Assert: the stale consumer can't ack the message taken by the new one
+	require.Equal(t, msgID, secondAttempt.ID)
+	require.NotEqual(t, firstAttempt.AttemptID, secondAttempt.AttemptID)
+	require.True(t, httpclient.IsCode(err, httpmodels.ErrorCodeAttemptMismatch))
+
+	message, err := app.MsgRepo.GetByID(context.Background(), app.DB, msgID)
+	require.NoError(t, err)
+	require.Equal(t, domain.MsgStatusProcessing, message.Status())
+	require.Equal(t, secondAttempt.AttemptID, message.AttemptID().String())
+}
+
+// consumeConcurrently drains the queue with several consumers at once and returns the IDs
+// each of them got.
+func consumeConcurrently(t *testing.T, app *appbuilder.App, consumers int, limit int) [][]string {
+	t.Helper()
+
+	results := make([][]string, consumers)
+	errs := make([]error, consumers)
+
+	var wg sync.WaitGroup
+	for i := range consumers {
+		client := testkit.NewHTTPClient(t, app)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				respDTO, err := client.ConsumeMessages(httpmodels.ConsumeRequest{
+					Queue: fixtures.DefaultMsgQueue,
+					Limit: utils.P(limit),
+				})
+				if err != nil {
+					errs[i] = err
+					return
+				}
+				if len(respDTO) == 0 {
+					return
+				}
+
+				for _, item := range respDTO {
+					results[i] = append(results[i], item.ID)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	return results
+}
+
+func TestConsumeMessagesConcurrently(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	var msgIDs []string
+	for range 50 {
+		msgIDs = append(msgIDs, fixtures.CreateAvailableMsg(app))
+	}
+
+	// Act
+	results := consumeConcurrently(t, app, 8, 3)
+
+	// Assert: every message is taken exactly once
+	var consumed []string
+	for _, ids := range results {
+		consumed = append(consumed, ids...)
+	}
+	require.ElementsMatch(t, msgIDs, consumed)
+}
+
+func TestConsumeMessageGroupsConcurrently(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	groupIDs := map[string]bool{}
+	for i := range 10 {
+		groupIDs[fixtures.CreateAvailableMsg(app, fixtures.WithGroup("customer-1"))] = i == 0
+		testkit.AdvanceClock(app, time.Second)
+	}
+
+	// Act
+	results := consumeConcurrently(t, app, 8, 5)
+
+	// Assert: only the oldest message of the group is in flight, the others wait for it
+	var consumed []string
+	for _, ids := range results {
+		consumed = append(consumed, ids...)
+	}
+	require.Len(t, consumed, 1)
+	require.True(t, groupIDs[consumed[0]])
+}
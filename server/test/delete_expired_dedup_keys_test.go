@@ -0,0 +1,48 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"server/internal/utils"
+	"server/internal/utils/testutils"
+	"server/pkg/httpmodels"
+	"server/test/fixtures"
+	"server/test/testkit"
+)
+
+func TestDeleteExpiredDedupKeys(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig(testkit.WithDedupWindow(10 * time.Minute)))
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	publish := func(dedupKey string) {
+		_, err := client.PublishMessages(httpmodels.PublishRequest{
+			httpmodels.PublishRequestItem{
+				Queue:    fixtures.DefaultMsgQueue,
+				Payload:  fixtures.DefaultMsgPayload,
+				DedupKey: utils.P(dedupKey),
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	// Arrange
+	publish("order-1")
+	testkit.AdvanceClock(app, 11*time.Minute)
+	publish("order-2")
+
+	// Act
+	err := app.DeleteExpiredDedupKeys.Do(context.Background())
+	require.NoError(t, err)
+
+	// Assert: only the key within its window is left
+	remaining, err := app.MsgRepo.DeleteExpiredDedupKeys(context.Background(), app.DB, app.Clock.Now().Add(time.Hour), 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, remaining)
+}
@@ -56,3 +56,33 @@ func TestExpireProcessingBeforeTimeout(t *testing.T) {
 	require.Equal(t, domain.MsgStatusProcessing, unchangedMsg.Status())
 	require.Equal(t, 0, unchangedMsg.Retries())
 }
+
+func TestExpireProcessingOfRemovedQueue(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange: the queue is removed on reload while its message is in flight
+	removedMsgID := fixtures.CreateProcessingMsg(app, fixtures.WithQueue("test.result"))
+	msgID := fixtures.CreateProcessingMsg(app)
+
+	err := app.Config.ReloadQueues(testkit.NewAppConfig(testkit.WithQueues("test", "all_results")))
+	require.NoError(t, err)
+
+	testkit.AdvanceClock(app, 6*time.Minute)
+
+	// Act
+	err = app.ExpireProcessing.Do(context.Background())
+
+	// Assert: other queues are still expired, the message of the removed queue waits for it
+	require.NoError(t, err)
+
+	updatedMsg, err := app.MsgRepo.GetByID(context.Background(), app.DB, msgID)
+	require.NoError(t, err)
+	require.Equal(t, domain.MsgStatusDelayed, updatedMsg.Status())
+
+	removedMsg, err := app.MsgRepo.GetByID(context.Background(), app.DB, removedMsgID)
+	require.NoError(t, err)
+	require.Equal(t, domain.MsgStatusProcessing, removedMsg.Status())
+}
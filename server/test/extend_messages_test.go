@@ -0,0 +1,227 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"server/internal/domain"
+	"server/internal/utils"
+	"server/internal/utils/testutils"
+	"server/pkg/httpclient"
+	"server/pkg/httpmodels"
+	"server/test/fixtures"
+	"server/test/testkit"
+)
+
+func TestExtendMessages(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	msgID := fixtures.CreateProcessingMsg(app)
+	testkit.AdvanceClock(app, 4*time.Minute)
+
+	// Act
+	err := client.ExtendMessages(httpmodels.ExtendRequest{
+		httpmodels.ExtendRequestItem{
+			ID:        msgID,
+			AttemptID: fixtures.GetAttemptID(app, msgID),
+			Duration:  utils.P(600),
+		},
+	})
+
+	// Assert response
+	require.NoError(t, err)
+
+	// Assert the message survives the original timeout
+	testkit.AdvanceClock(app, 5*time.Minute)
+	require.NoError(t, app.ExpireProcessing.Do(context.Background()))
+
+	message, err := app.MsgRepo.GetByID(context.Background(), app.DB, msgID)
+	require.NoError(t, err)
+	require.Equal(t, domain.MsgStatusProcessing, message.Status())
+	require.True(t, app.Clock.Now().Add(5*time.Minute).Equal(*message.TimeoutAt()))
+}
+
+func TestExtendMessagesDefaultDuration(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	msgID := fixtures.CreateProcessingMsg(app)
+	testkit.AdvanceClock(app, 4*time.Minute)
+
+	// Act
+	err := client.ExtendMessages(httpmodels.ExtendRequest{
+		httpmodels.ExtendRequestItem{
+			ID:        msgID,
+			AttemptID: fixtures.GetAttemptID(app, msgID),
+		},
+	})
+
+	// Assert
+	require.NoError(t, err)
+
+	message, err := app.MsgRepo.GetByID(context.Background(), app.DB, msgID)
+	require.NoError(t, err)
+	require.True(t, app.Clock.Now().Add(5*time.Minute).Equal(*message.TimeoutAt()))
+}
+
+func TestExtendMessagesCappedByMaxProcessingTime(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig(testkit.WithMaxProcessingTime(10 * time.Minute)))
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	startedAt := app.Clock.Now()
+	msgID := fixtures.CreateProcessingMsg(app)
+	testkit.AdvanceClock(app, 4*time.Minute)
+
+	// Act
+	err := client.ExtendMessages(httpmodels.ExtendRequest{
+		httpmodels.ExtendRequestItem{
+			ID:        msgID,
+			AttemptID: fixtures.GetAttemptID(app, msgID),
+			Duration:  utils.P(3600),
+		},
+	})
+
+	// Assert
+	require.NoError(t, err)
+
+	message, err := app.MsgRepo.GetByID(context.Background(), app.DB, msgID)
+	require.NoError(t, err)
+	require.True(t, startedAt.Add(10*time.Minute).Equal(*message.TimeoutAt()))
+}
+
+func TestExtendMessagesStaleAttempt(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	msgID := fixtures.CreateProcessingMsg(app)
+	staleAttemptID := fixtures.GetAttemptID(app, msgID)
+
+	testkit.AdvanceClock(app, 6*time.Minute)
+	require.NoError(t, app.ExpireProcessing.Do(context.Background()))
+
+	// Act
+	err := client.ExtendMessages(httpmodels.ExtendRequest{
+		httpmodels.ExtendRequestItem{
+			ID:        msgID,
+			AttemptID: staleAttemptID,
+		},
+	})
+
+	// Assert
+	require.True(t, httpclient.IsCode(err, httpmodels.ErrorCodeAttemptMismatch))
+}
+
+func TestExtendMessagesMaxProcessingTimeReached(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig(testkit.WithMaxProcessingTime(10 * time.Minute)))
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange: the lease is extended up to the cap
+	msgID := fixtures.CreateProcessingMsg(app)
+	testkit.AdvanceClock(app, 4*time.Minute)
+
+	request := httpmodels.ExtendRequest{
+		httpmodels.ExtendRequestItem{
+			ID:        msgID,
+			AttemptID: fixtures.GetAttemptID(app, msgID),
+			Duration:  utils.P(3600),
+		},
+	}
+	require.NoError(t, client.ExtendMessages(request))
+
+	// Act
+	err := client.ExtendMessages(request)
+
+	// Assert
+	require.True(t, httpclient.IsCode(err, httpmodels.ErrorCodeMaxLeaseReached))
+}
+
+func TestExtendMessagesBatch(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	msg1ID := fixtures.CreateProcessingMsg(app)
+	msg2ID := fixtures.CreateProcessingMsg(app)
+
+	// Act
+	err := client.ExtendMessages(httpmodels.ExtendRequest{
+		httpmodels.ExtendRequestItem{
+			ID:        msg1ID,
+			AttemptID: fixtures.GetAttemptID(app, msg1ID),
+			Duration:  utils.P(600),
+		},
+		httpmodels.ExtendRequestItem{
+			ID:        msg2ID,
+			AttemptID: fixtures.GetAttemptID(app, msg2ID),
+			Duration:  utils.P(900),
+		},
+	})
+
+	// Assert
+	require.NoError(t, err)
+
+	msg1, err := app.MsgRepo.GetByID(context.Background(), app.DB, msg1ID)
+	require.NoError(t, err)
+	require.True(t, app.Clock.Now().Add(10*time.Minute).Equal(*msg1.TimeoutAt()))
+
+	msg2, err := app.MsgRepo.GetByID(context.Background(), app.DB, msg2ID)
+	require.NoError(t, err)
+	require.True(t, app.Clock.Now().Add(15*time.Minute).Equal(*msg2.TimeoutAt()))
+}
+
+func TestExtendMessagesBatchWithUnknownMessage(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	msgID := fixtures.CreateProcessingMsg(app)
+
+	// Act
+	err := client.ExtendMessages(httpmodels.ExtendRequest{
+		httpmodels.ExtendRequestItem{
+			ID:        msgID,
+			AttemptID: fixtures.GetAttemptID(app, msgID),
+			Duration:  utils.P(600),
+		},
+		httpmodels.ExtendRequestItem{
+			ID:        "d8d4d0f7-1bbd-48c0-9f80-c66f5fd45fc2",
+			AttemptID: "0f8e5a4c-2d0b-4c0e-9a8e-1f3c5b7d9e21",
+		},
+	})
+
+	// Assert: the batch is rejected as a whole
+	require.True(t, httpclient.IsCode(err, httpmodels.ErrorCodeMessageNotFound))
+
+	message, err := app.MsgRepo.GetByID(context.Background(), app.DB, msgID)
+	require.NoError(t, err)
+	require.True(t, app.Clock.Now().Add(5*time.Minute).Equal(*message.TimeoutAt()))
+}
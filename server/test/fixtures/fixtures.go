@@ -5,24 +5,29 @@ import (
 	"fmt"
 	"slices"
 
+	"github.com/google/uuid"
+
 	"server/internal/appbuilder"
 	"server/internal/domain"
 	"server/internal/usecases"
+	"server/internal/utils/opt"
 	"server/test/testkit"
 )
 
 func CreatePreparedMsg(app *appbuilder.App, optArgs ...Option) string {
 	opts := buildOptions(optArgs)
-	return publish(app, opts.queue, opts.payload, opts.priority, false)
+	return publish(app, opts.queue, opts, false)
 }
 
-func publish(app *appbuilder.App, queue string, payload string, priority int, release bool) string {
+func publish(app *appbuilder.App, queue string, opts *options, release bool) string {
 	results, err := app.PublishMessages.Do(
 		context.Background(),
 		[]usecases.NewMessageParams{{
 			Queue:    domain.UnsafeQueueName(queue),
-			Payload:  payload,
-			Priority: priority,
+			Payload:  opts.payload,
+			Headers:  domain.UnsafeHeaders(opts.headers),
+			Group:    opts.group,
+			Priority: opt.Some(domain.UnsafePriority(opts.priority)),
 			StartAt:  nil,
 		}},
 		release,
@@ -48,20 +53,20 @@ func CreateAvailableMsg(app *appbuilder.App, optArgs ...Option) string {
 	history := append(slices.Clone(opts.history), opts.queue)
 	publishQueue, redirectQueues := history[0], history[1:]
 
-	msgID := publish(app, publishQueue, opts.payload, opts.priority, true)
+	msgID := publish(app, publishQueue, opts, true)
 
 	prevQueue := publishQueue
 	for _, nextQueue := range redirectQueues {
-		consumeMessage(app, msgID, prevQueue)
+		attemptID := consumeMessage(app, msgID, prevQueue)
 
 		if domain.UnsafeQueueName(nextQueue).IsDLQ() {
 			if testkit.GetDLQ(prevQueue) != nextQueue {
 				panic(fmt.Sprintf("queue %s is unreachable from queue %s", nextQueue, prevQueue))
 			}
 
-			nackPermanent(app, msgID)
+			nackPermanent(app, msgID, attemptID)
 		} else {
-			redirect(app, msgID, nextQueue)
+			redirect(app, msgID, attemptID, nextQueue)
 		}
 
 		prevQueue = nextQueue
@@ -70,17 +75,22 @@ func CreateAvailableMsg(app *appbuilder.App, optArgs ...Option) string {
 	return msgID
 }
 
-func redirect(app *appbuilder.App, msgID string, toQueue string) {
+func redirect(app *appbuilder.App, msgID string, attemptID uuid.UUID, toQueue string) {
 	if err := app.RedirectMessages.Do(context.Background(), []usecases.RedirectParams{{
 		ID:          msgID,
+		AttemptID:   attemptID,
 		Destination: domain.UnsafeQueueName(toQueue),
 	}}); err != nil {
 		panic(err)
 	}
 }
 
-func nackPermanent(app *appbuilder.App, msgID string) {
-	err := app.NackMessages.Do(context.Background(), []usecases.NackParams{{ID: msgID, Redeliver: false}})
+func nackPermanent(app *appbuilder.App, msgID string, attemptID uuid.UUID) {
+	err := app.NackMessages.Do(context.Background(), []usecases.NackParams{{
+		ID:        msgID,
+		AttemptID: attemptID,
+		Redeliver: false,
+	}})
 	if err != nil {
 		panic(err)
 	}
@@ -96,7 +106,7 @@ func CreateProcessingMsg(app *appbuilder.App, optArgs ...Option) string {
 	return msgID
 }
 
-func consumeMessage(app *appbuilder.App, msgID string, queue string) {
+func consumeMessage(app *appbuilder.App, msgID string, queue string) uuid.UUID {
 	result, err := app.ConsumeMessages.Do(context.Background(), domain.UnsafeQueueName(queue), 1, 0)
 	if err != nil {
 		panic(err)
@@ -105,12 +115,33 @@ func consumeMessage(app *appbuilder.App, msgID string, queue string) {
 	if len(result) != 1 || result[0].ID != msgID {
 		panic("consumed unexpected message")
 	}
+
+	return uuid.MustParse(result[0].AttemptID)
+}
+
+// GetAttemptID returns the current processing attempt of a message in PROCESSING status.
+func GetAttemptID(app *appbuilder.App, msgID string) string {
+	message, err := app.MsgRepo.GetByID(context.Background(), app.DB, msgID)
+	if err != nil {
+		panic(err)
+	}
+
+	attemptID := message.AttemptID()
+	if attemptID == nil {
+		panic("message has no processing attempt")
+	}
+
+	return attemptID.String()
 }
 
 func CreateDelayedMsg(app *appbuilder.App, optArgs ...Option) string {
 	msgID := CreateProcessingMsg(app, optArgs...)
 
-	err := app.NackMessages.Do(context.Background(), []usecases.NackParams{{ID: msgID, Redeliver: true}})
+	err := app.NackMessages.Do(context.Background(), []usecases.NackParams{{
+		ID:        msgID,
+		AttemptID: uuid.MustParse(GetAttemptID(app, msgID)),
+		Redeliver: true,
+	}})
 	if err != nil {
 		panic(err)
 	}
@@ -121,7 +152,10 @@ func CreateDelayedMsg(app *appbuilder.App, optArgs ...Option) string {
 func CreateDeliveredMsg(app *appbuilder.App, optArgs ...Option) string {
 	msgID := CreateProcessingMsg(app, optArgs...)
 
-	err := app.AckMessages.Do(context.Background(), []usecases.AckParams{{ID: msgID}})
+	err := app.AckMessages.Do(context.Background(), []usecases.AckParams{{
+		ID:        msgID,
+		AttemptID: uuid.MustParse(GetAttemptID(app, msgID)),
+	}})
 	if err != nil {
 		panic(err)
 	}
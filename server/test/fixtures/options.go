@@ -1,5 +1,10 @@
 package fixtures
 
+import (
+	"server/internal/domain"
+	"server/internal/utils"
+)
+
 const DefaultMsgQueue = "test"
 const DefaultMsgPayload = `{"arg": 123}`
 const DefaultMsgPriority = 100
@@ -8,6 +13,8 @@ type options struct {
 	queue    string
 	payload  string
 	priority int
+	headers  map[string]string
+	group    *domain.MessageGroup
 	history  []string
 }
 
@@ -31,6 +38,18 @@ func WithPriority(priority int) Option {
 	}
 }
 
+func WithHeaders(headers map[string]string) Option {
+	return func(o *options) {
+		o.headers = headers
+	}
+}
+
+func WithGroup(group string) Option {
+	return func(o *options) {
+		o.group = utils.P(domain.UnsafeMessageGroup(group))
+	}
+}
+
 func WithHistory(queues ...string) Option {
 	return func(o *options) {
 		o.history = queues
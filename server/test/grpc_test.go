@@ -0,0 +1,139 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"server/internal/domain"
+	"server/internal/utils/testutils"
+	"server/pkg/grpcapi"
+	"server/test/fixtures"
+	"server/test/testkit"
+)
+
+func TestGRPCPublishConsumeAck(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	client := testkit.NewGRPCClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	ctx := context.Background()
+
+	// Act
+	publishResp, err := client.Publish(ctx, &grpcapi.PublishRequest{
+		Messages: []*grpcapi.PublishRequestItem{
+			{Queue: fixtures.DefaultMsgQueue, Payload: `{"arg": 1}`, Headers: map[string]string{"tenant": "acme"}},
+			{Queue: "unknown", Payload: `{"arg": 2}`},
+		},
+	})
+	require.NoError(t, err)
+
+	consumeResp, err := client.Consume(ctx, &grpcapi.ConsumeRequest{Queue: fixtures.DefaultMsgQueue})
+	require.NoError(t, err)
+	require.Len(t, consumeResp.GetMessages(), 1)
+
+	consumed := consumeResp.GetMessages()[0]
+	_, err = client.Ack(ctx, &grpcapi.AckRequest{
+		Messages: []*grpcapi.AckRequestItem{{Id: consumed.GetId(), AttemptId: consumed.GetAttemptId()}},
+	})
+	require.NoError(t, err)
+
+	// Assert
+	require.Len(t, publishResp.GetResults(), 2)
+	msgID := publishResp.GetResults()[0].GetMessage().GetId()
+	require.Equal(t, msgID, consumed.GetId())
+	require.Equal(t, `{"arg": 1}`, consumed.GetPayload())
+	require.Equal(t, map[string]string{"tenant": "acme"}, consumed.GetHeaders())
+	require.Equal(t, "queue_not_found", publishResp.GetResults()[1].GetError().GetCode())
+
+	msg, err := app.MsgRepo.GetByID(ctx, app.DB, msgID)
+	require.NoError(t, err)
+	require.Equal(t, domain.MsgStatusDelivered, msg.Status())
+}
+
+func TestGRPCConsumeStream(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	client := testkit.NewGRPCClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Arrange
+	msg1ID := fixtures.CreateAvailableMsg(app)
+
+	// Act
+	stream, err := client.ConsumeStream(ctx, &grpcapi.ConsumeStreamRequest{Queue: fixtures.DefaultMsgQueue})
+	require.NoError(t, err)
+
+	first, err := stream.Recv()
+	require.NoError(t, err)
+
+	// published while the stream is already waiting
+	msg2ID := fixtures.CreateAvailableMsg(app)
+
+	second, err := stream.Recv()
+	require.NoError(t, err)
+
+	// Assert
+	require.Equal(t, msg1ID, first.GetId())
+	require.Equal(t, msg2ID, second.GetId())
+	require.Equal(t, fixtures.GetAttemptID(app, msg2ID), second.GetAttemptId())
+}
+
+func TestGRPCInvalidRequest(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	client := testkit.NewGRPCClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	// Act
+	_, invalidAttemptErr := client.Ack(context.Background(), &grpcapi.AckRequest{
+		Messages: []*grpcapi.AckRequestItem{{Id: "some-id", AttemptId: "not-uuid"}},
+	})
+	_, unknownQueueErr := client.Consume(context.Background(), &grpcapi.ConsumeRequest{Queue: "unknown"})
+
+	// Assert
+	require.Equal(t, codes.InvalidArgument, status.Code(invalidAttemptErr))
+	require.Equal(t, codes.NotFound, status.Code(unknownQueueErr))
+}
+
+func TestGRPCAuth(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig(newAuthAppConfigOptions()...))
+	client := testkit.NewGRPCClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	withKey := func(key string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+key)
+	}
+
+	req := &grpcapi.PublishRequest{
+		Messages: []*grpcapi.PublishRequestItem{{Queue: "test", Payload: `{"arg": 1}`}},
+	}
+
+	// Act
+	_, missingKeyErr := client.Publish(context.Background(), req)
+	forbiddenResp, forbiddenErr := client.Publish(withKey(consumerKey), req)
+	allowedResp, allowedErr := client.Publish(withKey(producerKey), req)
+
+	// Assert
+	require.Equal(t, codes.Unauthenticated, status.Code(missingKeyErr))
+
+	require.NoError(t, forbiddenErr)
+	require.Equal(t, "forbidden", forbiddenResp.GetResults()[0].GetError().GetCode())
+
+	require.NoError(t, allowedErr)
+	require.NotEmpty(t, allowedResp.GetResults()[0].GetMessage().GetId())
+}
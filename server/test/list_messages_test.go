@@ -0,0 +1,164 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"server/internal/utils"
+	"server/internal/utils/testutils"
+	"server/pkg/httpclient"
+	"server/pkg/httpmodels"
+	"server/test/fixtures"
+	"server/test/testkit"
+)
+
+func TestListMessagesPagination(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange: archived messages are listed together with live ones
+	archivedMsgID := fixtures.CreateArchivedMsg(app)
+	testkit.AdvanceClock(app, time.Second)
+	availableMsgID := fixtures.CreateAvailableMsg(app, fixtures.WithHeaders(map[string]string{"tenant": "acme"}))
+	testkit.AdvanceClock(app, time.Second)
+	// a higher priority makes it consumed before the older available message
+	processingMsgID := fixtures.CreateProcessingMsg(app, fixtures.WithPriority(200))
+	fixtures.CreateAvailableMsg(app, fixtures.WithQueue("test.result"))
+
+	// Act
+	var pages []*httpmodels.ListResponse
+	req := httpmodels.ListRequest{Queue: fixtures.DefaultMsgQueue, Limit: utils.P(2)}
+	for {
+		page, err := client.ListMessages(req)
+		require.NoError(t, err)
+
+		pages = append(pages, page)
+		if page.NextCursor == nil {
+			break
+		}
+		req.Cursor = page.NextCursor
+	}
+
+	// Assert
+	require.Len(t, pages, 2)
+	require.Len(t, pages[0].Messages, 2)
+	require.Len(t, pages[1].Messages, 1)
+
+	require.Equal(t, archivedMsgID, pages[0].Messages[0].ID)
+	require.Equal(t, httpmodels.MsgStatusDelivered, pages[0].Messages[0].Status)
+	require.NotNil(t, pages[0].Messages[0].FinalizedAt)
+
+	require.Equal(t, availableMsgID, pages[0].Messages[1].ID)
+	require.Equal(t, httpmodels.MsgStatusAvailable, pages[0].Messages[1].Status)
+	require.Equal(t, map[string]string{"tenant": "acme"}, pages[0].Messages[1].Headers)
+
+	require.Equal(t, processingMsgID, pages[1].Messages[0].ID)
+	require.Equal(t, httpmodels.MsgStatusProcessing, pages[1].Messages[0].Status)
+}
+
+func TestListMessagesFilters(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig(testkit.WithDeadLettering()))
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	startedAt := app.Clock.Now()
+	archivedMsgID := fixtures.CreateArchivedMsg(app, fixtures.WithPriority(10))
+	lowMsgID := fixtures.CreateAvailableMsg(app, fixtures.WithPriority(10))
+	highMsgID := fixtures.CreateAvailableMsg(app, fixtures.WithPriority(200))
+	testkit.AdvanceClock(app, time.Hour)
+	laterMsgID := fixtures.CreateAvailableMsg(app, fixtures.WithPriority(10))
+	// consumed by the fixtures, so they go before the available messages
+	retriedMsgID := fixtures.CreateDelayedMsg(app, fixtures.WithPriority(250))
+	dlqMsgID := fixtures.CreateAvailableMsg(app, fixtures.WithPriority(250), fixtures.WithHistory(fixtures.DefaultMsgQueue), fixtures.WithQueue(testkit.GetDLQ(fixtures.DefaultMsgQueue)))
+
+	list := func(req httpmodels.ListRequest) []string {
+		resp, err := client.ListMessages(req)
+		require.NoError(t, err)
+
+		ids := make([]string, 0, len(resp.Messages))
+		for _, msg := range resp.Messages {
+			ids = append(ids, msg.ID)
+		}
+		return ids
+	}
+
+	// Act & Assert
+	t.Run("by status", func(t *testing.T) {
+		require.ElementsMatch(t, []string{archivedMsgID}, list(httpmodels.ListRequest{
+			Queue:    fixtures.DefaultMsgQueue,
+			Statuses: []httpmodels.MessageStatus{httpmodels.MsgStatusDelivered},
+		}))
+	})
+
+	t.Run("by priority", func(t *testing.T) {
+		require.ElementsMatch(t, []string{archivedMsgID, lowMsgID, laterMsgID}, list(httpmodels.ListRequest{
+			Queue:       fixtures.DefaultMsgQueue,
+			MaxPriority: utils.P(100),
+		}))
+	})
+
+	t.Run("by created_at", func(t *testing.T) {
+		require.ElementsMatch(t, []string{archivedMsgID, lowMsgID, highMsgID}, list(httpmodels.ListRequest{
+			Queue:       fixtures.DefaultMsgQueue,
+			CreatedFrom: &startedAt,
+			CreatedTo:   utils.P(startedAt.Add(time.Minute)),
+		}))
+	})
+
+	t.Run("by retries", func(t *testing.T) {
+		require.ElementsMatch(t, []string{retriedMsgID}, list(httpmodels.ListRequest{
+			Queue:      fixtures.DefaultMsgQueue,
+			MinRetries: utils.P(1),
+		}))
+	})
+
+	t.Run("DLQ contents", func(t *testing.T) {
+		require.ElementsMatch(t, []string{dlqMsgID}, list(httpmodels.ListRequest{
+			Queue: testkit.GetDLQ(fixtures.DefaultMsgQueue),
+		}))
+	})
+}
+
+func TestListMessagesInvalidCursor(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	// Act
+	_, err := client.ListMessages(httpmodels.ListRequest{
+		Queue:  fixtures.DefaultMsgQueue,
+		Cursor: utils.P("not-a-cursor"),
+	})
+
+	// Assert
+	require.Error(t, err)
+	require.True(t, httpclient.IsCode(err, httpmodels.ErrorCodeRequestInvalid))
+}
+
+func TestListMessagesRequiresAdmin(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig(newAuthAppConfigOptions()...))
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	req := httpmodels.ListRequest{Queue: fixtures.DefaultMsgQueue}
+
+	// Act
+	_, consumerErr := client.WithAPIKey(consumerKey).ListMessages(req)
+	_, operatorErr := client.WithAPIKey(operatorKey).ListMessages(req)
+
+	// Assert
+	require.True(t, httpclient.IsCode(consumerErr, httpmodels.ErrorCodeForbidden))
+	require.NoError(t, operatorErr)
+}
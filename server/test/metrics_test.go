@@ -0,0 +1,132 @@
+package test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"server/internal/auth"
+	"server/internal/utils/testutils"
+	"server/pkg/httpmodels"
+	"server/test/fixtures"
+	"server/test/testkit"
+)
+
+func scrapeMetrics(t *testing.T, handler http.Handler) string {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	resp := recorder.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return string(body)
+}
+
+func TestMetricsQueueMessages(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	fixtures.CreateProcessingMsg(app) // first, so the fixture consumes it rather than the others
+	fixtures.CreateAvailableMsg(app)
+	fixtures.CreateAvailableMsg(app)
+	fixtures.CreatePreparedMsg(app, fixtures.WithQueue("test.result"))
+
+	// Act
+	body := scrapeMetrics(t, app.Router)
+
+	// Assert
+	require.Contains(t, body, `mq_queue_messages{queue="test",status="AVAILABLE"} 2`)
+	require.Contains(t, body, `mq_queue_messages{queue="test",status="PROCESSING"} 1`)
+	require.Contains(t, body, `mq_queue_messages{queue="test",status="DELAYED"} 0`)
+	require.Contains(t, body, `mq_queue_messages{queue="test.result",status="PREPARED"} 1`)
+}
+
+func TestMetricsRequireAPIKey(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig(
+		testkit.WithAPIKey("producer", "producer-secret", map[auth.Action][]string{
+			auth.ActionPublish: {"test"},
+		}),
+	))
+	testkit.CleanupDatabase(app.DB)
+
+	scrape := func(apiKey string) int {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		if apiKey != "" {
+			req.Header.Set("Authorization", "Bearer "+apiKey)
+		}
+
+		recorder := httptest.NewRecorder()
+		app.Router.ServeHTTP(recorder, req)
+
+		return recorder.Result().StatusCode
+	}
+
+	// Act & Assert
+	require.Equal(t, http.StatusUnauthorized, scrape(""))
+	require.Equal(t, http.StatusUnauthorized, scrape("unknown-secret"))
+	require.Equal(t, http.StatusOK, scrape("producer-secret"))
+}
+
+func TestMetricsUsecaseCounters(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	fixtures.CreateAvailableMsg(app)
+
+	// Act
+	consumed, err := client.ConsumeMessages(httpmodels.ConsumeRequest{Queue: "test"})
+	require.NoError(t, err)
+	require.Len(t, consumed, 1)
+
+	err = client.AckMessages(httpmodels.AckRequest{
+		httpmodels.AckRequestItem{
+			ID:        consumed[0].ID,
+			AttemptID: consumed[0].AttemptID,
+		},
+	})
+	require.NoError(t, err)
+
+	body := scrapeMetrics(t, app.Router)
+
+	// Assert
+	require.Contains(t, body, `mq_messages_published_total{queue="test"} 1`)
+	require.Contains(t, body, `mq_messages_consumed_total{queue="test"} 1`)
+	require.Contains(t, body, `mq_messages_acked_total{queue="test"} 1`)
+	require.Contains(t, body, `mq_http_request_duration_seconds_count{code="200",route="/messages/ack"} 1`)
+}
+
+func TestMetricsWorkerBatches(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	fixtures.CreateDeliveredMsg(app)
+
+	// Act
+	err := app.ArchiveMessages.Do(context.Background())
+	require.NoError(t, err)
+
+	body := scrapeMetrics(t, app.Router)
+
+	// Assert
+	require.Contains(t, body, `mq_worker_batch_size_count{worker="archive_messages"} 1`)
+	require.Contains(t, body, `mq_worker_batch_size_sum{worker="archive_messages"} 1`)
+}
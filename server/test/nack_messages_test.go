@@ -27,7 +27,7 @@ func TestNackMessages(t *testing.T) {
 
 	// Act
 	err := client.NackMessages(httpmodels.NackRequest{
-		httpmodels.NackRequestItem{ID: msgID},
+		httpmodels.NackRequestItem{ID: msgID, AttemptID: fixtures.GetAttemptID(app, msgID)},
 	})
 
 	// Assert response
@@ -51,7 +51,11 @@ func TestNackMessagesNoRedeliver(t *testing.T) {
 
 	// Act
 	err := client.NackMessages(httpmodels.NackRequest{
-		httpmodels.NackRequestItem{ID: msgID, Redeliver: utils.P(false)},
+		httpmodels.NackRequestItem{
+			ID:        msgID,
+			AttemptID: fixtures.GetAttemptID(app, msgID),
+			Redeliver: utils.P(false),
+		},
 	})
 
 	// Assert response
@@ -72,7 +76,11 @@ func TestNackUnknownMessage(t *testing.T) {
 
 	// Act
 	err := client.NackMessages(httpmodels.NackRequest{
-		httpmodels.NackRequestItem{ID: "d8d4d0f7-1bbd-48c0-9f80-c66f5fd45fc2", Redeliver: utils.P(false)},
+		httpmodels.NackRequestItem{
+			ID:        "d8d4d0f7-1bbd-48c0-9f80-c66f5fd45fc2",
+			AttemptID: "0f8e5a4c-2d0b-4c0e-9a8e-1f3c5b7d9e21",
+			Redeliver: utils.P(false),
+		},
 	})
 
 	// Assert
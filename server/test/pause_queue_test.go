@@ -0,0 +1,127 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"server/internal/utils"
+	"server/internal/utils/testutils"
+	"server/pkg/httpclient"
+	"server/pkg/httpmodels"
+	"server/test/fixtures"
+	"server/test/testkit"
+)
+
+func TestPauseQueue(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	fixtures.CreateAvailableMsg(app)
+	fixtures.CreateAvailableMsg(app, fixtures.WithQueue("test.result"))
+
+	// Act
+	err := client.PauseQueue(httpmodels.PauseRequest{Queue: fixtures.DefaultMsgQueue})
+	require.NoError(t, err)
+
+	startedAt := time.Now()
+	pausedResp, pausedErr := client.ConsumeMessages(httpmodels.ConsumeRequest{
+		Queue: fixtures.DefaultMsgQueue,
+		Poll:  utils.P(1),
+	})
+	pollDuration := time.Since(startedAt)
+
+	otherResp, otherErr := client.ConsumeMessages(httpmodels.ConsumeRequest{Queue: "test.result"})
+
+	// Assert
+	require.NoError(t, pausedErr)
+	require.Empty(t, pausedResp)
+	require.GreaterOrEqual(t, pollDuration, time.Second, "long-poll wait is honored")
+
+	require.NoError(t, otherErr)
+	require.Len(t, otherResp, 1, "other queues are not affected")
+}
+
+func TestPausedQueueAcceptsPublishing(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	require.NoError(t, client.PauseQueue(httpmodels.PauseRequest{Queue: fixtures.DefaultMsgQueue}))
+
+	// Act
+	publishResp, err := client.PublishMessages(httpmodels.PublishRequest{
+		{Queue: fixtures.DefaultMsgQueue, Payload: "{}"},
+	})
+	require.NoError(t, err)
+
+	pausedResp, err := client.ConsumeMessages(httpmodels.ConsumeRequest{Queue: fixtures.DefaultMsgQueue})
+	require.NoError(t, err)
+
+	require.NoError(t, client.ResumeQueue(httpmodels.ResumeRequest{Queue: fixtures.DefaultMsgQueue}))
+
+	resumedResp, err := client.ConsumeMessages(httpmodels.ConsumeRequest{Queue: fixtures.DefaultMsgQueue})
+	require.NoError(t, err)
+
+	// Assert
+	require.Empty(t, pausedResp)
+	require.Len(t, resumedResp, 1)
+	require.Equal(t, publishResp.Results[0].Data.ID, resumedResp[0].ID)
+}
+
+func TestPauseQueueIdempotent(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	req := httpmodels.PauseRequest{Queue: fixtures.DefaultMsgQueue}
+
+	// Act & Assert
+	require.NoError(t, client.PauseQueue(req))
+	require.NoError(t, client.PauseQueue(req))
+
+	require.NoError(t, client.ResumeQueue(httpmodels.ResumeRequest{Queue: fixtures.DefaultMsgQueue}))
+	require.NoError(t, client.ResumeQueue(httpmodels.ResumeRequest{Queue: fixtures.DefaultMsgQueue}))
+}
+
+func TestPauseUnknownQueue(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	// Act
+	err := client.PauseQueue(httpmodels.PauseRequest{Queue: "unknown_queue"})
+
+	// Assert
+	require.True(t, httpclient.IsCode(err, httpmodels.ErrorCodeQueueNotFound))
+}
+
+func TestPauseQueueRequiresAdmin(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig(newAuthAppConfigOptions()...))
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	// Act
+	consumerPauseErr := client.WithAPIKey(consumerKey).PauseQueue(httpmodels.PauseRequest{Queue: fixtures.DefaultMsgQueue})
+	consumerResumeErr := client.WithAPIKey(consumerKey).ResumeQueue(httpmodels.ResumeRequest{Queue: fixtures.DefaultMsgQueue})
+	operatorErr := client.WithAPIKey(operatorKey).PauseQueue(httpmodels.PauseRequest{Queue: fixtures.DefaultMsgQueue})
+
+	// Assert
+	require.True(t, httpclient.IsCode(consumerPauseErr, httpmodels.ErrorCodeForbidden))
+	require.True(t, httpclient.IsCode(consumerResumeErr, httpmodels.ErrorCodeForbidden))
+	require.NoError(t, operatorErr)
+}
@@ -3,7 +3,9 @@ package test
 import (
 	"context"
 	"slices"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -51,7 +53,7 @@ func TestPrepareMessage(t *testing.T) {
 	require.Equal(t, msgPayload, message.Payload())
 	require.Equal(t, app.Clock.Now(), message.CreatedAt())
 	require.Equal(t, domain.MsgStatusPrepared, message.Status())
-	require.Equal(t, msgPriority, message.Priority())
+	require.Equal(t, msgPriority, message.Priority().Int())
 }
 
 func TestPublishMessage(t *testing.T) {
@@ -105,7 +107,7 @@ func TestPublishMessage(t *testing.T) {
 		require.Equal(t, fixtures.DefaultMsgPayload, message.Payload())
 		require.Equal(t, app.Clock.Now(), message.CreatedAt())
 		require.Equal(t, domain.MsgStatusAvailable, message.Status())
-		require.Equal(t, fixtures.DefaultMsgPriority, message.Priority())
+		require.Equal(t, fixtures.DefaultMsgPriority, message.Priority().Int())
 	})
 
 	t.Run("creates message with custom priority", func(t *testing.T) {
@@ -118,7 +120,7 @@ func TestPublishMessage(t *testing.T) {
 		require.Equal(t, fixtures.DefaultMsgPayload, message.Payload())
 		require.Equal(t, app.Clock.Now(), message.CreatedAt())
 		require.Equal(t, domain.MsgStatusAvailable, message.Status())
-		require.Equal(t, fixtures.DefaultMsgPriority+1, message.Priority())
+		require.Equal(t, fixtures.DefaultMsgPriority+1, message.Priority().Int())
 	})
 
 	t.Run("fails for unknown queue", func(t *testing.T) {
@@ -155,3 +157,181 @@ func TestPublishGeneralFailure(t *testing.T) {
 	// Assert response
 	require.True(t, httpclient.IsCode(err, httpmodels.ErrorCodeBatchSizeTooBig))
 }
+
+func TestPublishPriorityRange(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig(testkit.WithPriorityRange(0, 10, 5)))
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	// Act
+	respDTO, err := client.PublishMessages(httpmodels.PublishRequest{
+		httpmodels.PublishRequestItem{
+			Queue:   fixtures.DefaultMsgQueue,
+			Payload: fixtures.DefaultMsgPayload,
+		},
+		httpmodels.PublishRequestItem{
+			Queue:    fixtures.DefaultMsgQueue,
+			Payload:  fixtures.DefaultMsgPayload,
+			Priority: utils.P(10),
+		},
+		httpmodels.PublishRequestItem{
+			Queue:    fixtures.DefaultMsgQueue,
+			Payload:  fixtures.DefaultMsgPayload,
+			Priority: utils.P(11),
+		},
+	})
+
+	// Assert response
+	require.NoError(t, err)
+	require.Len(t, respDTO.Results, 3)
+
+	t.Run("uses queue's default priority", func(t *testing.T) {
+		require.Nil(t, respDTO.Results[0].Error)
+
+		message, err := app.MsgRepo.GetByID(context.Background(), app.DB, respDTO.Results[0].Data.ID)
+		require.NoError(t, err)
+		require.Equal(t, 5, message.Priority().Int())
+	})
+
+	t.Run("accepts priority within range", func(t *testing.T) {
+		require.Nil(t, respDTO.Results[1].Error)
+
+		message, err := app.MsgRepo.GetByID(context.Background(), app.DB, respDTO.Results[1].Data.ID)
+		require.NoError(t, err)
+		require.Equal(t, 10, message.Priority().Int())
+	})
+
+	t.Run("fails for priority out of range", func(t *testing.T) {
+		require.NotNil(t, respDTO.Results[2].Error)
+		require.True(t, httpclient.IsCode(respDTO.Results[2].Error, httpmodels.ErrorCodeRequestInvalid))
+	})
+}
+
+func TestPublishWithHeaders(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	headers := map[string]string{"content-type": "application/json", "tenant": "acme"}
+
+	// Act
+	respDTO, err := client.PublishMessages(httpmodels.PublishRequest{
+		httpmodels.PublishRequestItem{
+			Queue:   fixtures.DefaultMsgQueue,
+			Payload: fixtures.DefaultMsgPayload,
+			Headers: headers,
+		},
+		httpmodels.PublishRequestItem{
+			Queue:   fixtures.DefaultMsgQueue,
+			Payload: fixtures.DefaultMsgPayload,
+			Headers: map[string]string{"": "unnamed"},
+		},
+	})
+
+	// Assert response
+	require.NoError(t, err)
+	require.Len(t, respDTO.Results, 2)
+
+	require.Nil(t, respDTO.Results[0].Error)
+	require.NotNil(t, respDTO.Results[1].Error)
+	require.True(t, httpclient.IsCode(respDTO.Results[1].Error, httpmodels.ErrorCodeRequestInvalid))
+
+	// Assert the message in DB
+	message, err := app.MsgRepo.GetByID(context.Background(), app.DB, respDTO.Results[0].Data.ID)
+	require.NoError(t, err)
+	require.Equal(t, headers, message.Headers().Map())
+}
+
+func TestPublishWithDedupKey(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig(testkit.WithDedupWindow(10 * time.Minute)))
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	publish := func(dedupKey string) *httpmodels.PublishedMessage {
+		respDTO, err := client.PublishMessages(httpmodels.PublishRequest{
+			httpmodels.PublishRequestItem{
+				Queue:    fixtures.DefaultMsgQueue,
+				Payload:  fixtures.DefaultMsgPayload,
+				DedupKey: utils.P(dedupKey),
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, respDTO.Results, 1)
+		require.Nil(t, respDTO.Results[0].Error)
+		return respDTO.Results[0].Data
+	}
+
+	// Act
+	original := publish("order-1")
+	testkit.AdvanceClock(app, 5*time.Minute)
+	retried := publish("order-1")
+	other := publish("order-2")
+	testkit.AdvanceClock(app, 6*time.Minute)
+	afterWindow := publish("order-1")
+
+	// Assert
+	require.False(t, original.Duplicate)
+
+	require.True(t, retried.Duplicate)
+	require.Equal(t, original.ID, retried.ID)
+
+	require.False(t, other.Duplicate)
+	require.NotEqual(t, original.ID, other.ID)
+
+	require.False(t, afterWindow.Duplicate)
+	require.NotEqual(t, original.ID, afterWindow.ID)
+
+	counts, err := app.MsgRepo.CountByQueue(context.Background(), app.DB, domain.MsgStatusAvailable)
+	require.NoError(t, err)
+	require.Equal(t, map[domain.QueueName]int{
+		domain.UnsafeQueueName(fixtures.DefaultMsgQueue): 3,
+	}, counts)
+}
+
+func TestPublishWithDedupKeyConcurrently(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	const publishers = 10
+
+	// Act
+	results := make([]*httpmodels.PublishedMessage, publishers)
+	var wg sync.WaitGroup
+	for i := range publishers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			respDTO, err := client.PublishMessages(httpmodels.PublishRequest{
+				httpmodels.PublishRequestItem{
+					Queue:    fixtures.DefaultMsgQueue,
+					Payload:  fixtures.DefaultMsgPayload,
+					DedupKey: utils.P("order-1"),
+				},
+			})
+			if err == nil && respDTO.Results[0].Error == nil {
+				results[i] = respDTO.Results[0].Data
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Assert
+	published := 0
+	for _, result := range results {
+		require.NotNil(t, result)
+		require.Equal(t, results[0].ID, result.ID)
+		if !result.Duplicate {
+			published++
+		}
+	}
+	require.Equal(t, 1, published)
+}
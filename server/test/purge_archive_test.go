@@ -0,0 +1,57 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"server/internal/storage"
+	"server/internal/utils/testutils"
+	"server/test/fixtures"
+	"server/test/testkit"
+)
+
+func TestPurgeArchiveExpired(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig(testkit.WithRetention(24 * time.Hour)))
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	oldMsgID := fixtures.CreateArchivedMsg(app)
+	testkit.AdvanceClock(app, 12*time.Hour)
+	freshMsgID := fixtures.CreateArchivedMsg(app)
+	testkit.AdvanceClock(app, 13*time.Hour)
+
+	// Act
+	err := app.PurgeArchive.Do(context.Background())
+	require.NoError(t, err)
+
+	// Assert
+	_, err = app.ArchivedMsgRepo.GetByID(context.Background(), app.DB, oldMsgID)
+	require.ErrorIs(t, err, storage.ErrArchivedMsgNotFound)
+
+	_, err = app.ArchivedMsgRepo.GetByID(context.Background(), app.DB, freshMsgID)
+	require.NoError(t, err)
+}
+
+func TestPurgeArchiveWithoutRetention(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	msgID := fixtures.CreateArchivedMsg(app)
+	testkit.AdvanceClock(app, 365*24*time.Hour)
+
+	// Act
+	err := app.PurgeArchive.Do(context.Background())
+	require.NoError(t, err)
+
+	// Assert
+	_, err = app.ArchivedMsgRepo.GetByID(context.Background(), app.DB, msgID)
+	require.NoError(t, err)
+}
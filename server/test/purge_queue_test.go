@@ -0,0 +1,105 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"server/internal/domain"
+	"server/internal/storage"
+	"server/internal/utils/testutils"
+	"server/pkg/httpclient"
+	"server/pkg/httpmodels"
+	"server/test/fixtures"
+	"server/test/testkit"
+)
+
+func TestPurgeQueue(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	purgedIDs := []string{
+		fixtures.CreateDelayedMsg(app),
+		fixtures.CreateProcessingMsg(app),
+		fixtures.CreateAvailableMsg(app),
+	}
+	otherMsgID := fixtures.CreateAvailableMsg(app, fixtures.WithQueue("test.result"))
+
+	// Act
+	resp, err := client.PurgeQueue(httpmodels.PurgeRequest{Queue: fixtures.DefaultMsgQueue})
+
+	// Assert response
+	require.NoError(t, err)
+	require.Equal(t, 3, resp.Purged)
+
+	// Assert messages in DB
+	for _, msgID := range purgedIDs {
+		_, err := app.MsgRepo.GetByID(context.Background(), app.DB, msgID)
+		require.ErrorIs(t, err, storage.ErrMsgNotFound)
+
+		_, err = app.ArchivedMsgRepo.GetByID(context.Background(), app.DB, msgID)
+		require.ErrorIs(t, err, storage.ErrArchivedMsgNotFound)
+	}
+
+	_, err = app.MsgRepo.GetByID(context.Background(), app.DB, otherMsgID)
+	require.NoError(t, err)
+}
+
+func TestPurgeQueueFilteredWithArchive(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	deliveredMsgID := fixtures.CreateDeliveredMsg(app)
+	availableMsgID := fixtures.CreateAvailableMsg(app)
+
+	// Act
+	resp, err := client.PurgeQueue(httpmodels.PurgeRequest{
+		Queue:    fixtures.DefaultMsgQueue,
+		Statuses: []httpmodels.MessageStatus{httpmodels.MsgStatusAvailable},
+		Archive:  true,
+	})
+
+	// Assert response
+	require.NoError(t, err)
+	require.Equal(t, 1, resp.Purged)
+
+	// Assert messages in DB
+	_, err = app.MsgRepo.GetByID(context.Background(), app.DB, availableMsgID)
+	require.ErrorIs(t, err, storage.ErrMsgNotFound)
+
+	archivedMsg, err := app.ArchivedMsgRepo.GetByID(context.Background(), app.DB, availableMsgID)
+	require.NoError(t, err)
+	require.Equal(t, domain.MsgStatusDropped, archivedMsg.Status())
+	require.True(t, app.Clock.Now().Equal(archivedMsg.FinalizedAt()))
+
+	deliveredMsg, err := app.MsgRepo.GetByID(context.Background(), app.DB, deliveredMsgID)
+	require.NoError(t, err)
+	require.Equal(t, domain.MsgStatusDelivered, deliveredMsg.Status())
+}
+
+func TestPurgeQueueRequiresAdmin(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig(newAuthAppConfigOptions()...))
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	req := httpmodels.PurgeRequest{Queue: fixtures.DefaultMsgQueue}
+
+	// Act
+	_, consumerErr := client.WithAPIKey(consumerKey).PurgeQueue(req)
+	_, operatorErr := client.WithAPIKey(operatorKey).PurgeQueue(req)
+
+	// Assert
+	require.True(t, httpclient.IsCode(consumerErr, httpmodels.ErrorCodeForbidden))
+	require.NoError(t, operatorErr)
+}
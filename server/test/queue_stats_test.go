@@ -0,0 +1,133 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"server/internal/utils"
+	"server/internal/utils/testutils"
+	"server/pkg/httpclient"
+	"server/pkg/httpmodels"
+	"server/test/fixtures"
+	"server/test/testkit"
+)
+
+func TestGetQueueStats(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig(testkit.WithDeadLettering()))
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	dlq := testkit.GetDLQ(fixtures.DefaultMsgQueue)
+
+	// Arrange
+	fixtures.CreateAvailableMsg(app, fixtures.WithHistory(fixtures.DefaultMsgQueue), fixtures.WithQueue(dlq))
+	fixtures.CreateDelayedMsg(app) // becomes available in 30s
+	fixtures.CreateAvailableMsg(app)
+	testkit.AdvanceClock(app, 10*time.Second)
+	fixtures.CreateAvailableMsg(app)
+
+	// Act
+	resp, err := client.GetQueueStats(httpmodels.StatsRequest{
+		Queues: []httpmodels.QueueName{fixtures.DefaultMsgQueue, dlq},
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, app.Clock.Now().Unix(), resp.CollectedAt.Unix())
+	require.Len(t, resp.Queues, 2)
+
+	require.Equal(t, httpmodels.QueueStats{
+		Queue: fixtures.DefaultMsgQueue,
+		Messages: map[httpmodels.MessageStatus]int{
+			httpmodels.MsgStatusPrepared:   0,
+			httpmodels.MsgStatusAvailable:  2,
+			httpmodels.MsgStatusProcessing: 0,
+			httpmodels.MsgStatusDelayed:    1,
+			httpmodels.MsgStatusDelivered:  0,
+			httpmodels.MsgStatusDropped:    0,
+		},
+		OldestAvailableAge: utils.P(10),
+		DelayedDueSoon:     1,
+		DLQSize:            utils.P(1),
+	}, resp.Queues[0])
+
+	require.Equal(t, dlq, resp.Queues[1].Queue)
+	require.Equal(t, 1, resp.Queues[1].Messages[httpmodels.MsgStatusAvailable])
+	require.Nil(t, resp.Queues[1].DLQSize)
+}
+
+func TestGetQueueStatsAllQueues(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	// Act
+	resp, err := client.GetQueueStats(httpmodels.StatsRequest{})
+
+	// Assert
+	require.NoError(t, err)
+
+	queues := make([]string, 0, len(resp.Queues))
+	for _, stats := range resp.Queues {
+		queues = append(queues, stats.Queue)
+		require.Nil(t, stats.OldestAvailableAge)
+		require.Nil(t, stats.DLQSize)
+	}
+	require.Equal(t, []string{"all_results", "test", "test.result"}, queues)
+}
+
+func TestGetQueueStatsCached(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig(testkit.WithStatsCacheTTL(time.Minute)))
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	req := httpmodels.StatsRequest{Queues: []httpmodels.QueueName{fixtures.DefaultMsgQueue}}
+
+	availableCount := func() int {
+		resp, err := client.GetQueueStats(req)
+		require.NoError(t, err)
+		return resp.Queues[0].Messages[httpmodels.MsgStatusAvailable]
+	}
+
+	// Arrange
+	require.Equal(t, 0, availableCount())
+	fixtures.CreateAvailableMsg(app)
+
+	// Act & Assert
+	require.Equal(t, 0, availableCount(), "stats are served from the cache")
+
+	testkit.AdvanceClock(app, time.Minute)
+	require.Equal(t, 1, availableCount(), "stats are collected again after the cache TTL")
+}
+
+func TestGetQueueStatsRequiresAdmin(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig(newAuthAppConfigOptions()...))
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	// Act
+	_, consumerErr := client.WithAPIKey(consumerKey).GetQueueStats(httpmodels.StatsRequest{
+		Queues: []httpmodels.QueueName{fixtures.DefaultMsgQueue},
+	})
+	consumerAll, consumerAllErr := client.WithAPIKey(consumerKey).GetQueueStats(httpmodels.StatsRequest{})
+	operatorAll, operatorAllErr := client.WithAPIKey(operatorKey).GetQueueStats(httpmodels.StatsRequest{})
+
+	// Assert
+	require.True(t, httpclient.IsCode(consumerErr, httpmodels.ErrorCodeForbidden))
+
+	require.NoError(t, consumerAllErr)
+	require.Empty(t, consumerAll.Queues)
+
+	require.NoError(t, operatorAllErr)
+	require.Len(t, operatorAll.Queues, 3)
+}
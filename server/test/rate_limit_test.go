@@ -0,0 +1,108 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"server/internal/utils"
+	"server/internal/utils/testutils"
+	"server/pkg/httpmodels"
+	"server/test/fixtures"
+	"server/test/testkit"
+)
+
+func TestRateLimitBurst(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig(testkit.WithRateLimit(1, time.Minute, 2)))
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	for range 5 {
+		fixtures.CreateAvailableMsg(app)
+	}
+
+	// Act
+	firstResp, err := client.ConsumeMessages(httpmodels.ConsumeRequest{
+		Queue: fixtures.DefaultMsgQueue,
+		Limit: utils.P(5),
+	})
+	require.NoError(t, err)
+
+	secondResp, err := client.ConsumeMessages(httpmodels.ConsumeRequest{
+		Queue: fixtures.DefaultMsgQueue,
+		Limit: utils.P(5),
+	})
+	require.NoError(t, err)
+
+	// Assert
+	require.Len(t, firstResp, 2)
+	require.Empty(t, secondResp)
+}
+
+func TestRateLimitRefill(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig(testkit.WithRateLimit(1, time.Minute, 2)))
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	for range 5 {
+		fixtures.CreateAvailableMsg(app)
+	}
+
+	resp, err := client.ConsumeMessages(httpmodels.ConsumeRequest{
+		Queue: fixtures.DefaultMsgQueue,
+		Limit: utils.P(5),
+	})
+	require.NoError(t, err)
+	require.Len(t, resp, 2)
+
+	testkit.AdvanceClock(app, time.Minute)
+
+	// Act
+	resp, err = client.ConsumeMessages(httpmodels.ConsumeRequest{
+		Queue: fixtures.DefaultMsgQueue,
+		Limit: utils.P(5),
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, resp, 1)
+}
+
+func TestRateLimitUnusedTokensKept(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig(testkit.WithRateLimit(1, time.Minute, 3)))
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	fixtures.CreateAvailableMsg(app)
+
+	resp, err := client.ConsumeMessages(httpmodels.ConsumeRequest{
+		Queue: fixtures.DefaultMsgQueue,
+		Limit: utils.P(3),
+	})
+	require.NoError(t, err)
+	require.Len(t, resp, 1)
+
+	for range 3 {
+		fixtures.CreateAvailableMsg(app)
+	}
+
+	// Act
+	resp, err = client.ConsumeMessages(httpmodels.ConsumeRequest{
+		Queue: fixtures.DefaultMsgQueue,
+		Limit: utils.P(3),
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, resp, 2)
+}
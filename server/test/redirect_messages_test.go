@@ -32,6 +32,7 @@ func TestRedirectMessages(t *testing.T) {
 	err := client.RedirectMessages(httpmodels.RedirectRequest{
 		httpmodels.RedirectRequestItem{
 			ID:          msgID,
+			AttemptID:   fixtures.GetAttemptID(app, msgID),
 			Destination: destinationQueue,
 		},
 	})
@@ -68,6 +69,7 @@ func TestRedirectToUnknownQueue(t *testing.T) {
 	err := client.RedirectMessages(httpmodels.RedirectRequest{
 		httpmodels.RedirectRequestItem{
 			ID:          msgID,
+			AttemptID:   fixtures.GetAttemptID(app, msgID),
 			Destination: "unknown_queue",
 		},
 	})
@@ -87,6 +89,7 @@ func TestRedirectUnknownMessage(t *testing.T) {
 	err := client.RedirectMessages(httpmodels.RedirectRequest{
 		httpmodels.RedirectRequestItem{
 			ID:          "d8d4d0f7-1bbd-48c0-9f80-c66f5fd45fc2",
+			AttemptID:   "0f8e5a4c-2d0b-4c0e-9a8e-1f3c5b7d9e21",
 			Destination: "all_results",
 		},
 	})
@@ -109,6 +112,7 @@ func TestRedirectToDLQNotAllowed(t *testing.T) {
 	err := client.RedirectMessages(httpmodels.RedirectRequest{
 		httpmodels.RedirectRequestItem{
 			ID:          msgID,
+			AttemptID:   fixtures.GetAttemptID(app, msgID),
 			Destination: testkit.GetDLQ(fixtures.DefaultMsgQueue),
 		},
 	})
@@ -0,0 +1,126 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"server/internal/domain"
+	"server/internal/utils"
+	"server/internal/utils/testutils"
+	"server/pkg/httpclient"
+	"server/pkg/httpmodels"
+	"server/test/fixtures"
+	"server/test/testkit"
+)
+
+func TestRedriveDLQ(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig(testkit.WithDeadLettering()))
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	dlq := testkit.GetDLQ(fixtures.DefaultMsgQueue)
+
+	// Arrange
+	msg1ID := fixtures.CreateAvailableMsg(app, fixtures.WithHistory(fixtures.DefaultMsgQueue), fixtures.WithQueue(dlq))
+	msg2ID := fixtures.CreateAvailableMsg(app, fixtures.WithHistory(fixtures.DefaultMsgQueue), fixtures.WithQueue(dlq))
+	otherMsgID := fixtures.CreateAvailableMsg(app)
+
+	// Act
+	resp, err := client.RedriveDLQ(httpmodels.RedriveRequest{Queue: dlq})
+
+	// Assert response
+	require.NoError(t, err)
+	require.Equal(t, &httpmodels.RedriveResponse{Redriven: 2, Skipped: 0}, resp)
+
+	// Assert messages in DB
+	for _, msgID := range []string{msg1ID, msg2ID} {
+		message, err := app.MsgRepo.GetByIDWithHistory(context.Background(), app.DB, msgID)
+		require.NoError(t, err)
+
+		require.Equal(t, domain.MsgStatusAvailable, message.Status())
+		require.Equal(t, fixtures.DefaultMsgQueue, message.Queue().String())
+		require.Equal(t, 0, message.Retries())
+
+		chapters, loaded := message.History().Chapters()
+		require.True(t, loaded)
+		require.Len(t, chapters, 2)
+		require.Equal(t, fixtures.DefaultMsgQueue, chapters[0].Queue().String())
+		require.Equal(t, dlq, chapters[1].Queue().String())
+	}
+
+	otherMsg, err := app.MsgRepo.GetByID(context.Background(), app.DB, otherMsgID)
+	require.NoError(t, err)
+	require.Equal(t, 0, otherMsg.Generation())
+}
+
+func TestRedriveDLQFiltered(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig(testkit.WithDeadLettering()))
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	dlq := testkit.GetDLQ(fixtures.DefaultMsgQueue)
+
+	// Arrange
+	lowMsgID := fixtures.CreateAvailableMsg(app, fixtures.WithPriority(10), fixtures.WithHistory(fixtures.DefaultMsgQueue), fixtures.WithQueue(dlq))
+	high1MsgID := fixtures.CreateAvailableMsg(app, fixtures.WithPriority(200), fixtures.WithHistory(fixtures.DefaultMsgQueue), fixtures.WithQueue(dlq))
+	high2MsgID := fixtures.CreateAvailableMsg(app, fixtures.WithPriority(200), fixtures.WithHistory(fixtures.DefaultMsgQueue), fixtures.WithQueue(dlq))
+
+	// Act
+	resp, err := client.RedriveDLQ(httpmodels.RedriveRequest{
+		Queue:       dlq,
+		MinPriority: utils.P(100),
+		Limit:       utils.P(1),
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, 1, resp.Redriven)
+
+	queueOf := func(msgID string) string {
+		message, err := app.MsgRepo.GetByID(context.Background(), app.DB, msgID)
+		require.NoError(t, err)
+		return message.Queue().String()
+	}
+
+	require.Equal(t, dlq, queueOf(lowMsgID))
+	require.Equal(t, fixtures.DefaultMsgQueue, queueOf(high1MsgID)) // the oldest one goes first
+	require.Equal(t, dlq, queueOf(high2MsgID))
+}
+
+func TestRedriveNotDLQ(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig(testkit.WithDeadLettering()))
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	// Act
+	_, err := client.RedriveDLQ(httpmodels.RedriveRequest{Queue: fixtures.DefaultMsgQueue})
+
+	// Assert
+	require.True(t, httpclient.IsCode(err, httpmodels.ErrorCodeRequestInvalid))
+}
+
+func TestRedriveDLQRequiresAdmin(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig(append(newAuthAppConfigOptions(), testkit.WithDeadLettering())...))
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	req := httpmodels.RedriveRequest{Queue: testkit.GetDLQ(fixtures.DefaultMsgQueue)}
+
+	// Act
+	_, consumerErr := client.WithAPIKey(consumerKey).RedriveDLQ(req)
+	_, operatorErr := client.WithAPIKey(operatorKey).RedriveDLQ(req)
+
+	// Assert
+	require.True(t, httpclient.IsCode(consumerErr, httpmodels.ErrorCodeForbidden))
+	require.NoError(t, operatorErr)
+}
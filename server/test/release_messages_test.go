@@ -36,7 +36,7 @@ func TestReleaseMessage(t *testing.T) {
 
 	require.Equal(t, fixtures.DefaultMsgQueue, message.Queue().String())
 	require.Equal(t, fixtures.DefaultMsgPayload, message.Payload())
-	require.Equal(t, fixtures.DefaultMsgPriority, message.Priority())
+	require.Equal(t, fixtures.DefaultMsgPriority, message.Priority().Int())
 	require.Equal(t, app.Clock.Now(), message.CreatedAt())
 	require.Equal(t, domain.MsgStatusAvailable, message.Status())
 }
@@ -1,7 +1,29 @@
 package testkit
 
+import (
+	"time"
+
+	"server/internal/auth"
+	"server/internal/config"
+	"server/internal/domain"
+	"server/internal/utils/opt"
+	"server/internal/utils/testutils"
+)
+
 type configOptions struct {
-	deadLetteringOn bool
+	deadLetteringOn   bool
+	maxProcessingTime opt.Val[time.Duration]
+	retention         opt.Val[time.Duration]
+	rateLimit         opt.Val[*domain.RateLimit]
+	priorityAging     opt.Val[*domain.PriorityAging]
+	priorityRange     *domain.PriorityRange
+	defaultPriority   domain.Priority
+	dedupWindow       time.Duration
+	statsCacheTTL     opt.Val[time.Duration]
+	eventBusDriver    config.EventBusDriver
+	apiKeys           []*auth.APIKey
+	webhooks          map[domain.QueueName]*config.WebhookConfig
+	queues            []string
 }
 
 type ConfigOption func(*configOptions)
@@ -12,8 +34,124 @@ func WithDeadLettering() ConfigOption {
 	}
 }
 
+func WithMaxProcessingTime(maxProcessingTime time.Duration) ConfigOption {
+	return func(o *configOptions) {
+		o.maxProcessingTime = opt.Some(maxProcessingTime)
+	}
+}
+
+func WithRetention(retention time.Duration) ConfigOption {
+	return func(o *configOptions) {
+		o.retention = opt.Some(retention)
+	}
+}
+
+func WithRateLimit(messages int, interval time.Duration, burst int) ConfigOption {
+	return func(o *configOptions) {
+		rateLimit, err := domain.NewRateLimit(messages, interval, burst)
+		if err != nil {
+			panic(err)
+		}
+		o.rateLimit = opt.Some(rateLimit)
+	}
+}
+
+func WithPriorityAging(interval time.Duration, step int, maxPriority int) ConfigOption {
+	return func(o *configOptions) {
+		aging, err := domain.NewPriorityAging(interval, step, domain.UnsafePriority(maxPriority))
+		if err != nil {
+			panic(err)
+		}
+		o.priorityAging = opt.Some(aging)
+	}
+}
+
+func WithPriorityRange(minPriority int, maxPriority int, defaultPriority int) ConfigOption {
+	return func(o *configOptions) {
+		priorityRange, err := domain.NewPriorityRange(
+			domain.UnsafePriority(minPriority),
+			domain.UnsafePriority(maxPriority),
+		)
+		if err != nil {
+			panic(err)
+		}
+		o.priorityRange = priorityRange
+		o.defaultPriority = domain.UnsafePriority(defaultPriority)
+	}
+}
+
+func WithDedupWindow(window time.Duration) ConfigOption {
+	return func(o *configOptions) {
+		o.dedupWindow = window
+	}
+}
+
+func WithStatsCacheTTL(ttl time.Duration) ConfigOption {
+	return func(o *configOptions) {
+		o.statsCacheTTL = opt.Some(ttl)
+	}
+}
+
+func WithEventBusDriver(driver config.EventBusDriver) ConfigOption {
+	return func(o *configOptions) {
+		o.eventBusDriver = driver
+	}
+}
+
+// WithAPIKey enables authentication and registers a key granting the given actions.
+func WithAPIKey(name, key string, grants map[auth.Action][]string) ConfigOption {
+	return func(o *configOptions) {
+		principal, err := auth.NewPrincipal(name, grants)
+		if err != nil {
+			panic(err)
+		}
+
+		apiKey, err := auth.NewAPIKey(key, principal)
+		if err != nil {
+			panic(err)
+		}
+
+		o.apiKeys = append(o.apiKeys, apiKey)
+	}
+}
+
+// WithWebhook switches the queue to push mode with delivery to the given URL.
+func WithWebhook(queue string, webhookURL string, headers map[string]string) ConfigOption {
+	return func(o *configOptions) {
+		webhook, err := config.NewWebhookConfig(webhookURL, headers, 2, 5*time.Second)
+		if err != nil {
+			panic(err)
+		}
+
+		if o.webhooks == nil {
+			o.webhooks = make(map[domain.QueueName]*config.WebhookConfig)
+		}
+		o.webhooks[domain.UnsafeQueueName(queue)] = webhook
+	}
+}
+
+// WithQueues replaces the default set of queues.
+func WithQueues(queues ...string) ConfigOption {
+	return func(o *configOptions) {
+		o.queues = queues
+	}
+}
+
+func defaultEventBusDriver() config.EventBusDriver {
+	if testutils.UseSQLite() {
+		return config.EventBusDriverMemory
+	}
+	return config.DefaultEventBusDriver
+}
+
 func buildConfigOptions(optArgs []ConfigOption) *configOptions {
-	opts := configOptions{}
+	opts := configOptions{
+		priorityRange:   domain.FullPriorityRange(),
+		defaultPriority: domain.UnsafePriority(config.DefaultPriority),
+		dedupWindow:     config.DefaultDedupWindow,
+		eventBusDriver:  defaultEventBusDriver(),
+		queues:          []string{"test", "test.result", "all_results"},
+	}
 	for _, fn := range optArgs {
 		fn(&opts)
 	}
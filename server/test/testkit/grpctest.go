@@ -0,0 +1,42 @@
+package testkit
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"server/internal/appbuilder"
+	"server/pkg/grpcapi"
+)
+
+// NewGRPCClient serves the app's gRPC API over an in-memory connection.
+func NewGRPCClient(t *testing.T, app *appbuilder.App) grpcapi.QueueServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1024 * 1024)
+	go func() {
+		_ = app.GRPCServer.Serve(listener)
+	}()
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("grpc.NewClient: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+		app.GRPCServer.Stop()
+	})
+
+	return grpcapi.NewQueueServiceClient(conn)
+}
@@ -1,14 +1,21 @@
 package testkit
 
 import (
+	"context"
 	"database/sql"
 	"testing"
 	"time"
 
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
 	"server/internal/appbuilder"
 	"server/internal/config"
 	"server/internal/domain"
+	"server/internal/migrations"
+	"server/internal/utils/dbutils"
 	"server/internal/utils/opt"
+	"server/internal/utils/testutils"
 	"server/internal/utils/timeutils"
 	"server/pkg/httpclient"
 )
@@ -18,29 +25,62 @@ func NewHTTPClient(t *testing.T, app *appbuilder.App) *httpclient.Client {
 }
 
 func NewApp(conf *config.Config) *appbuilder.App {
+	return buildApp(conf, nil)
+}
+
+// NewTracedApp records all spans of the app in the returned exporter.
+func NewTracedApp(conf *config.Config) (*appbuilder.App, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	return buildApp(conf, exporter), exporter
+}
+
+func buildApp(conf *config.Config, spanExporter sdktrace.SpanExporter) *appbuilder.App {
 	clock := timeutils.NewStubClock(time.Date(2025, 6, 12, 12, 0, 0, 0, time.Local))
 
 	app, err := appbuilder.BuildApp(conf, &appbuilder.Overrides{
-		Clock: clock,
+		Clock:        clock,
+		SpanExporter: spanExporter,
 	})
 	if err != nil {
 		panic(err)
 	}
 
+	// the Postgres schema comes from initdb, the SQLite file is created by the tests
+	if app.Dialect == dbutils.DialectSQLite {
+		migrator, err := migrations.NewMigrator(app.Logger, app.DB, app.Dialect)
+		if err != nil {
+			panic(err)
+		}
+		if _, err := migrator.Up(context.Background()); err != nil {
+			panic(err)
+		}
+	}
+
 	return app
 }
 
 func NewAppConfig(optArgs ...ConfigOption) *config.Config {
 	opts := buildConfigOptions(optArgs)
 
-	pgConf, err := config.NewPostgresConfig(
-		"127.0.0.1:5432",
-		"queue",
-		"user",
-		"pass",
-	)
-	if err != nil {
-		panic(err)
+	pgConf := opt.None[*config.PostgresConfig]()
+	sqliteConf := opt.None[*config.SQLiteConfig]()
+	if testutils.UseSQLite() {
+		tmp, err := config.NewSQLiteConfig(testutils.SQLitePath())
+		if err != nil {
+			panic(err)
+		}
+		sqliteConf = opt.Some(tmp)
+	} else {
+		tmp, err := config.NewPostgresConfig(
+			"127.0.0.1:5432",
+			"queue",
+			"user",
+			"pass",
+		)
+		if err != nil {
+			panic(err)
+		}
+		pgConf = opt.Some(tmp)
 	}
 
 	backoffConfig, err := domain.NewBackoffConfig(
@@ -54,6 +94,13 @@ func NewAppConfig(optArgs ...ConfigOption) *config.Config {
 	queueConfig, err := domain.NewQueueConfig(
 		opt.Some(backoffConfig),
 		time.Minute*5,
+		opts.maxProcessingTime,
+		opts.retention,
+		opts.rateLimit,
+		opts.priorityAging,
+		opts.priorityRange,
+		opts.defaultPriority,
+		opts.dedupWindow,
 		opts.deadLetteringOn,
 	)
 	if err != nil {
@@ -61,19 +108,36 @@ func NewAppConfig(optArgs ...ConfigOption) *config.Config {
 	}
 
 	queues := map[domain.QueueName]*domain.QueueConfig{}
-	for _, queue := range []string{"test", "test.result", "all_results"} {
+	for _, queue := range opts.queues {
 		queues[domain.UnsafeQueueName(queue)] = queueConfig
 		if opts.deadLetteringOn {
 			dlqName := domain.UnsafeQueueName(GetDLQ(queue))
-			queues[dlqName] = config.DefaultDLQueueConfig(queueConfig.ProcessingTimeout())
+			queues[dlqName] = config.DefaultDLQueueConfig(queueConfig)
+		}
+	}
+
+	authConfig := opt.None[*config.AuthConfig]()
+	if len(opts.apiKeys) > 0 {
+		tmp, err := config.NewAuthConfig(opts.apiKeys)
+		if err != nil {
+			panic(err)
 		}
+		authConfig = opt.Some(tmp)
 	}
 
 	conf, err := config.NewConfig(
 		config.DefaultAPIPort,
-		opt.Some(pgConf),
+		opt.None[uint16](),
+		pgConf,
+		sqliteConf,
+		false,
+		opts.eventBusDriver,
 		config.DefaultBatchSizeLimit,
+		opts.statsCacheTTL,
 		queues,
+		authConfig,
+		opts.webhooks,
+		opt.None[*config.TracingConfig](),
 	)
 	if err != nil {
 		panic(err)
@@ -93,12 +157,24 @@ func CleanupDatabase(db *sql.DB) {
 	if _, err := db.Exec("DELETE FROM message_payloads"); err != nil {
 		panic(err)
 	}
+	if _, err := db.Exec("DELETE FROM message_headers"); err != nil {
+		panic(err)
+	}
+	if _, err := db.Exec("DELETE FROM message_dedup_keys"); err != nil {
+		panic(err)
+	}
 	if _, err := db.Exec("DELETE FROM message_history"); err != nil {
 		panic(err)
 	}
 	if _, err := db.Exec("DELETE FROM archived_messages"); err != nil {
 		panic(err)
 	}
+	if _, err := db.Exec("DELETE FROM queue_token_buckets"); err != nil {
+		panic(err)
+	}
+	if _, err := db.Exec("DELETE FROM queue_pauses"); err != nil {
+		panic(err)
+	}
 }
 
 func GetDLQ(queue string) string {
@@ -0,0 +1,158 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc/metadata"
+
+	"server/internal/tracing"
+	"server/internal/utils"
+	"server/internal/utils/testutils"
+	"server/pkg/grpcapi"
+	"server/pkg/httpmodels"
+	"server/test/fixtures"
+	"server/test/testkit"
+)
+
+const publisherTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestTraceParentPropagation(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app, exporter := testkit.NewTracedApp(testkit.NewAppConfig())
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	publisherSpan := tracing.SpanContextFromTraceParent(publisherTraceParent)
+
+	// Act
+	publishResp, err := client.WithTraceParent(publisherTraceParent).PublishMessages(httpmodels.PublishRequest{
+		{Queue: fixtures.DefaultMsgQueue, Payload: fixtures.DefaultMsgPayload},
+	})
+	require.NoError(t, err)
+
+	consumeResp, err := client.ConsumeMessages(httpmodels.ConsumeRequest{Queue: fixtures.DefaultMsgQueue})
+	require.NoError(t, err)
+
+	// Assert
+	require.Len(t, consumeResp, 1)
+	require.Equal(t, publishResp.Results[0].Data.ID, consumeResp[0].ID)
+	require.NotNil(t, consumeResp[0].TraceParent)
+
+	consumerParent := tracing.SpanContextFromTraceParent(*consumeResp[0].TraceParent)
+	require.True(t, consumerParent.IsValid())
+	require.Equal(t, publisherSpan.TraceID(), consumerParent.TraceID())
+
+	spans := exporter.GetSpans()
+
+	t.Run("route span continues publisher's trace", func(t *testing.T) {
+		routeSpan := findSpan(t, spans, "/messages/publish")
+		require.Equal(t, publisherSpan.SpanID(), routeSpan.Parent.SpanID())
+		require.True(t, routeSpan.Parent.IsRemote())
+	})
+
+	t.Run("message carries its publishing span", func(t *testing.T) {
+		publishSpan := findSpan(t, spans, "PublishMessages.doOne")
+		require.Equal(t, publishSpan.SpanContext.SpanID(), consumerParent.SpanID())
+	})
+
+	t.Run("consume span links to the publishing span", func(t *testing.T) {
+		consumeSpan := findSpan(t, spans, "ConsumeMessages.Do")
+		require.Len(t, consumeSpan.Links, 1)
+		require.Equal(t, consumerParent.SpanID(), consumeSpan.Links[0].SpanContext.SpanID())
+	})
+}
+
+func TestTraceParentWithoutTracing(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app := testkit.NewApp(testkit.NewAppConfig())
+	client := testkit.NewHTTPClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	// Act
+	tracedResp, err := client.WithTraceParent(publisherTraceParent).PublishMessages(httpmodels.PublishRequest{
+		{Queue: fixtures.DefaultMsgQueue, Payload: fixtures.DefaultMsgPayload},
+	})
+	require.NoError(t, err)
+
+	untracedResp, err := client.PublishMessages(httpmodels.PublishRequest{
+		{Queue: fixtures.DefaultMsgQueue, Payload: fixtures.DefaultMsgPayload},
+	})
+	require.NoError(t, err)
+
+	consumeResp, err := client.ConsumeMessages(httpmodels.ConsumeRequest{
+		Queue: fixtures.DefaultMsgQueue,
+		Limit: utils.P(2),
+	})
+	require.NoError(t, err)
+
+	// Assert: the incoming trace context is still propagated as is
+	require.Len(t, consumeResp, 2)
+
+	traceParents := map[string]*string{}
+	for _, item := range consumeResp {
+		traceParents[item.ID] = item.TraceParent
+	}
+
+	require.Equal(t, publisherTraceParent, *traceParents[tracedResp.Results[0].Data.ID])
+	require.Nil(t, traceParents[untracedResp.Results[0].Data.ID])
+}
+
+func TestGRPCTraceParentPropagation(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app, exporter := testkit.NewTracedApp(testkit.NewAppConfig())
+	client := testkit.NewGRPCClient(t, app)
+	testkit.CleanupDatabase(app.DB)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "traceparent", publisherTraceParent)
+
+	// Act
+	_, err := client.Publish(ctx, &grpcapi.PublishRequest{
+		Messages: []*grpcapi.PublishRequestItem{{Queue: fixtures.DefaultMsgQueue, Payload: `{"arg": 1}`}},
+	})
+	require.NoError(t, err)
+
+	consumeResp, err := client.Consume(context.Background(), &grpcapi.ConsumeRequest{Queue: fixtures.DefaultMsgQueue})
+	require.NoError(t, err)
+
+	// Assert
+	require.Len(t, consumeResp.GetMessages(), 1)
+
+	consumerParent := tracing.SpanContextFromTraceParent(consumeResp.GetMessages()[0].GetTraceParent())
+	require.Equal(t, tracing.SpanContextFromTraceParent(publisherTraceParent).TraceID(), consumerParent.TraceID())
+
+	findSpan(t, exporter.GetSpans(), "/queue.v1.QueueService/Publish")
+}
+
+func TestWorkerSpans(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	app, exporter := testkit.NewTracedApp(testkit.NewAppConfig())
+	testkit.CleanupDatabase(app.DB)
+
+	// Act
+	err := app.ArchiveMessages.Do(context.Background())
+	require.NoError(t, err)
+
+	// Assert
+	span := findSpan(t, exporter.GetSpans(), "ArchiveMessages.Do")
+	require.False(t, span.Parent.IsValid())
+}
+
+func findSpan(t *testing.T, spans tracetest.SpanStubs, name string) tracetest.SpanStub {
+	t.Helper()
+
+	for _, span := range spans {
+		if span.Name == name {
+			return span
+		}
+	}
+
+	require.Failf(t, "span not found", "name: %s", name)
+	return tracetest.SpanStub{}
+}
@@ -0,0 +1,150 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"server/internal/domain"
+	"server/internal/utils/testutils"
+	"server/pkg/httpmodels"
+	"server/test/fixtures"
+	"server/test/testkit"
+)
+
+type webhookCall struct {
+	header http.Header
+	body   httpmodels.WebhookRequest
+}
+
+// newWebhookServer starts a receiver that answers every delivery with the given status.
+func newWebhookServer(t *testing.T, statusCode int) (*httptest.Server, chan webhookCall) {
+	calls := make(chan webhookCall, 10)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body httpmodels.WebhookRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+
+		calls <- webhookCall{header: r.Header, body: body}
+		w.WriteHeader(statusCode)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, calls
+}
+
+func TestWebhookDelivered(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	srv, calls := newWebhookServer(t, http.StatusOK)
+
+	app := testkit.NewApp(testkit.NewAppConfig(
+		testkit.WithWebhook("test", srv.URL, map[string]string{"X-Token": "secret"}),
+	))
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	msgID := fixtures.CreateAvailableMsg(app)
+
+	// Act
+	err := app.WebhookDispatcher.Do(context.Background())
+	require.NoError(t, err)
+
+	// Assert the webhook call
+	require.Len(t, calls, 1)
+	call := <-calls
+	require.Equal(t, "secret", call.header.Get("X-Token"))
+	require.Equal(t, "application/json", call.header.Get("Content-Type"))
+	require.Equal(t, msgID, call.body.ID)
+	require.Equal(t, fixtures.DefaultMsgQueue, call.body.Queue)
+	require.Equal(t, fixtures.DefaultMsgPayload, call.body.Payload)
+	require.Equal(t, map[string]string{}, call.body.Headers)
+
+	// Assert the message in DB
+	message, err := app.MsgRepo.GetByID(context.Background(), app.DB, msgID)
+	require.NoError(t, err)
+	require.Equal(t, domain.MsgStatusDelivered, message.Status())
+}
+
+func TestWebhookRetryableFailure(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	srv, calls := newWebhookServer(t, http.StatusServiceUnavailable)
+
+	app := testkit.NewApp(testkit.NewAppConfig(
+		testkit.WithWebhook("test", srv.URL, nil),
+	))
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	msgID := fixtures.CreateAvailableMsg(app)
+
+	// Act
+	err := app.WebhookDispatcher.Do(context.Background())
+	require.NoError(t, err)
+
+	// Assert
+	require.Len(t, calls, 1)
+
+	message, err := app.MsgRepo.GetByID(context.Background(), app.DB, msgID)
+	require.NoError(t, err)
+	require.Equal(t, domain.MsgStatusDelayed, message.Status())
+	require.Equal(t, 1, message.Retries())
+}
+
+func TestWebhookPermanentFailure(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	srv, calls := newWebhookServer(t, http.StatusBadRequest)
+
+	app := testkit.NewApp(testkit.NewAppConfig(
+		testkit.WithDeadLettering(),
+		testkit.WithWebhook("test", srv.URL, nil),
+	))
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	msgID := fixtures.CreateAvailableMsg(app)
+
+	// Act
+	err := app.WebhookDispatcher.Do(context.Background())
+	require.NoError(t, err)
+
+	// Assert
+	require.Len(t, calls, 1)
+
+	message, err := app.MsgRepo.GetByID(context.Background(), app.DB, msgID)
+	require.NoError(t, err)
+	require.Equal(t, domain.MsgStatusAvailable, message.Status())
+	require.Equal(t, testkit.GetDLQ(fixtures.DefaultMsgQueue), message.Queue().String())
+}
+
+func TestWebhookUnreachable(t *testing.T) {
+	testutils.SkipIfNotInTestEnv(t)
+
+	srv, _ := newWebhookServer(t, http.StatusOK)
+	srv.Close()
+
+	app := testkit.NewApp(testkit.NewAppConfig(
+		testkit.WithWebhook("test", srv.URL, nil),
+	))
+	testkit.CleanupDatabase(app.DB)
+
+	// Arrange
+	msgID := fixtures.CreateAvailableMsg(app)
+
+	// Act
+	err := app.WebhookDispatcher.Do(context.Background())
+	require.NoError(t, err)
+
+	// Assert
+	message, err := app.MsgRepo.GetByID(context.Background(), app.DB, msgID)
+	require.NoError(t, err)
+	require.Equal(t, domain.MsgStatusDelayed, message.Status())
+}